@@ -82,6 +82,8 @@ type Config struct {
 	Logging  LoggingConfig `yaml:"logging,omitempty"`
 	Limit    LimitConfig   `yaml:"limit,omitempty"`
 
+	Telemetry TelemetryConfig `yaml:"telemetry,omitempty"`
+
 	Development bool `yaml:"development,omitempty"`
 	//TLS config for port and bind addressess
 	TLS *tls.Config
@@ -100,6 +102,8 @@ type RTCConfig struct {
 	PacketBufferSizeVideo int `yaml:"packet_buffer_size_video,omitempty"`
 	// Number of packets to buffer for NACK - audio
 	PacketBufferSizeAudio int `yaml:"packet_buffer_size_audio,omitempty"`
+	// Maximum number of times a packet is retransmitted to a subscriber, 0 uses the default
+	MaxRetransmissions int `yaml:"max_retransmissions,omitempty"`
 
 	// Throttle periods for pli/fir rtcp packets
 	PLIThrottle PLIThrottleConfig `yaml:"pli_throttle,omitempty"`
@@ -214,6 +218,11 @@ type StreamTrackerConfig struct {
 	BitrateReportInterval map[int32]time.Duration             `yaml:"bitrate_report_interval,omitempty"`
 	PacketTracker         map[int32]StreamTrackerPacketConfig `yaml:"packet_tracker,omitempty"`
 	FrameTracker          map[int32]StreamTrackerFrameConfig  `yaml:"frame_tracker,omitempty"`
+	// tick interval of the reporter pushing layer bitrates to subscribers, larger values reduce CPU usage with many tracks.
+	// not to be confused with BitrateReportInterval, the per layer window bitrates are measured over
+	BitrateReporterTickInterval time.Duration `yaml:"bitrate_reporter_tick_interval,omitempty"`
+	// maximum number of layer events kept for post-mortem analysis, 0 uses the default
+	LayerEventLogSize int `yaml:"layer_event_log_size,omitempty"`
 }
 
 type StreamTrackersConfig struct {
@@ -225,6 +234,8 @@ type PlayoutDelayConfig struct {
 	Enabled bool `yaml:"enabled,omitempty"`
 	Min     int  `yaml:"min,omitempty"`
 	Max     int  `yaml:"max,omitempty"`
+	// hold published packets on the SFU for the minimum playout delay of the room before forwarding
+	EnforceOnSFU bool `yaml:"enforce_on_sfu,omitempty"`
 }
 
 type VideoConfig struct {
@@ -245,6 +256,16 @@ type RoomConfig struct {
 	SyncStreams                  bool               `yaml:"sync_streams,omitempty"`
 	MaxRoomNameLength            int                `yaml:"max_room_name_length,omitempty"`
 	MaxParticipantIdentityLength int                `yaml:"max_participant_identity_length,omitempty"`
+	// prefer the node a room was previously allocated to when it is re-created
+	StickyAllocation bool `yaml:"sticky_allocation,omitempty"`
+	// when set, room creations are appended as JSON lines to this file
+	AuditLogPath string `yaml:"audit_log_path,omitempty"`
+	// cap on aggregate bitrate of media published in a room, video is limited to the base temporal layer above it
+	MaxTotalBitrateBps uint64 `yaml:"max_total_bitrate_bps,omitempty"`
+	// template applied to rooms created without one
+	DefaultTemplate string `yaml:"default_template,omitempty"`
+	// number of rooms created concurrently by asynchronous room creation
+	CreateRoomWorkers int `yaml:"create_room_workers,omitempty"`
 }
 
 type CodecSpec struct {
@@ -303,6 +324,15 @@ type LimitConfig struct {
 	BytesPerSec            float32 `yaml:"bytes_per_sec,omitempty"`
 	SubscriptionLimitVideo int32   `yaml:"subscription_limit_video,omitempty"`
 	SubscriptionLimitAudio int32   `yaml:"subscription_limit_audio,omitempty"`
+	// also check limits of the node hinted for a room, the hosting node is always checked
+	ValidateNodeCapacity bool `yaml:"validate_node_capacity,omitempty"`
+}
+
+type TelemetryConfig struct {
+	// when set, metrics are pushed to a Prometheus pushgateway at this URL
+	PrometheusPushURL string `yaml:"prometheus_push_url,omitempty"`
+	// interval between pushes, defaults to 15s
+	PushInterval time.Duration `yaml:"push_interval,omitempty"`
 }
 
 type IngressConfig struct {
@@ -327,6 +357,8 @@ type ForwardStatsConfig struct {
 	SummaryInterval time.Duration `yaml:"summary_interval,omitempty"`
 	ReportInterval  time.Duration `yaml:"report_interval,omitempty"`
 	ReportWindow    time.Duration `yaml:"report_window,omitempty"`
+	// collect forward stats of each published track for debugging, independent of node level forward stats
+	PerTrack bool `yaml:"per_track,omitempty"`
 }
 
 func DefaultAPIConfig() APIConfig {
@@ -413,7 +445,8 @@ var DefaultConfig = Config{
 		DynacastPauseDelay: 5 * time.Second,
 		StreamTracker: StreamTrackersConfig{
 			Video: StreamTrackerConfig{
-				StreamTrackerType: StreamTrackerTypePacket,
+				StreamTrackerType:           StreamTrackerTypePacket,
+				BitrateReporterTickInterval: time.Second,
 				BitrateReportInterval: map[int32]time.Duration{
 					0: 1 * time.Second,
 					1: 1 * time.Second,
@@ -449,7 +482,8 @@ var DefaultConfig = Config{
 				},
 			},
 			Screenshare: StreamTrackerConfig{
-				StreamTrackerType: StreamTrackerTypePacket,
+				StreamTrackerType:           StreamTrackerTypePacket,
+				BitrateReporterTickInterval: time.Second,
 				BitrateReportInterval: map[int32]time.Duration{
 					0: 4 * time.Second,
 					1: 4 * time.Second,
@@ -501,6 +535,7 @@ var DefaultConfig = Config{
 		DepartureTimeout:             20,
 		MaxRoomNameLength:            256,
 		MaxParticipantIdentityLength: 256,
+		CreateRoomWorkers:            4,
 	},
 	Logging: LoggingConfig{
 		PionLevel: "error",
@@ -508,6 +543,9 @@ var DefaultConfig = Config{
 	TURN: TURNConfig{
 		Enabled: false,
 	},
+	Telemetry: TelemetryConfig{
+		PushInterval: 15 * time.Second,
+	},
 	NodeSelector: NodeSelectorConfig{
 		Kind:         "any",
 		SortBy:       "random",
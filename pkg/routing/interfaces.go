@@ -63,6 +63,9 @@ type ParticipantInit struct {
 	SubscriberAllowPause *bool
 }
 
+// RoomMigrationHandler is invoked on the node hosting a room when the room has moved to nodeID
+type RoomMigrationHandler func(ctx context.Context, roomName livekit.RoomName, nodeID livekit.NodeID)
+
 // Router allows multiple nodes to coordinate the participant session
 //
 //counterfeiter:generate . Router
@@ -79,6 +82,10 @@ type Router interface {
 	SetNodeForRoom(ctx context.Context, roomName livekit.RoomName, nodeId livekit.NodeID) error
 	ClearRoomState(ctx context.Context, roomName livekit.RoomName) error
 
+	// NotifyRoomMigration informs the node previously hosting a room that it has moved to nodeID
+	NotifyRoomMigration(ctx context.Context, prevNodeID livekit.NodeID, roomName livekit.RoomName, nodeID livekit.NodeID) error
+	OnRoomMigration(handler RoomMigrationHandler)
+
 	GetRegion() string
 
 	Start() error
@@ -37,6 +37,7 @@ type LocalRouter struct {
 	// channels for each participant
 	requestChannels  map[string]*MessageChannel
 	responseChannels map[string]*MessageChannel
+	onRoomMigration  RoomMigrationHandler
 	isStarted        atomic.Bool
 }
 
@@ -64,6 +65,27 @@ func (r *LocalRouter) ClearRoomState(_ context.Context, _ livekit.RoomName) erro
 	return nil
 }
 
+func (r *LocalRouter) NotifyRoomMigration(ctx context.Context, _ livekit.NodeID, roomName livekit.RoomName, nodeID livekit.NodeID) error {
+	r.handleRoomMigration(ctx, roomName, nodeID)
+	return nil
+}
+
+func (r *LocalRouter) OnRoomMigration(handler RoomMigrationHandler) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.onRoomMigration = handler
+}
+
+func (r *LocalRouter) handleRoomMigration(ctx context.Context, roomName livekit.RoomName, nodeID livekit.NodeID) {
+	r.lock.RLock()
+	onRoomMigration := r.onRoomMigration
+	r.lock.RUnlock()
+
+	if onRoomMigration != nil {
+		onRoomMigration(ctx, roomName, nodeID)
+	}
+}
+
 func (r *LocalRouter) RegisterNode() error {
 	return nil
 }
@@ -17,6 +17,7 @@ package routing
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"runtime/pprof"
 	"sync"
 	"time"
@@ -45,8 +46,16 @@ const (
 
 	// hash of room_name => node_id
 	NodeRoomKey = "room_node_map"
+
+	// pub/sub channel of a node, receives migrations of rooms hosted on it
+	RoomMigrationChannelPrefix = "room_migration:"
 )
 
+type roomMigration struct {
+	RoomName livekit.RoomName `json:"room_name"`
+	NodeID   livekit.NodeID   `json:"node_id"`
+}
+
 var _ Router = (*RedisRouter)(nil)
 
 // RedisRouter uses Redis pub/sub to route signaling messages across different nodes
@@ -131,6 +140,21 @@ func (r *RedisRouter) ClearRoomState(_ context.Context, roomName livekit.RoomNam
 	return nil
 }
 
+func (r *RedisRouter) NotifyRoomMigration(ctx context.Context, prevNodeID livekit.NodeID, roomName livekit.RoomName, nodeID livekit.NodeID) error {
+	if prevNodeID == livekit.NodeID(r.currentNode.Id) {
+		return r.LocalRouter.NotifyRoomMigration(ctx, prevNodeID, roomName, nodeID)
+	}
+
+	data, err := json.Marshal(&roomMigration{RoomName: roomName, NodeID: nodeID})
+	if err != nil {
+		return err
+	}
+	if err = r.rc.Publish(r.ctx, RoomMigrationChannelPrefix+string(prevNodeID), data).Err(); err != nil {
+		return errors.Wrap(err, "could not publish room migration")
+	}
+	return nil
+}
+
 func (r *RedisRouter) GetNode(nodeID livekit.NodeID) (*livekit.Node, error) {
 	data, err := r.rc.HGet(r.ctx, NodesKey, string(nodeID)).Result()
 	if err == redis.Nil {
@@ -180,6 +204,7 @@ func (r *RedisRouter) Start() error {
 	workerStarted := make(chan error)
 	go r.statsWorker()
 	go r.keepaliveWorker(workerStarted)
+	go r.roomMigrationWorker()
 
 	// wait until worker is running
 	return <-workerStarted
@@ -235,6 +260,31 @@ func (r *RedisRouter) statsWorker() {
 	}
 }
 
+// receive migrations of rooms hosted on this node
+func (r *RedisRouter) roomMigrationWorker() {
+	sub := r.rc.Subscribe(r.ctx, RoomMigrationChannelPrefix+r.currentNode.Id)
+	defer sub.Close()
+
+	msgs := sub.Channel()
+	for {
+		select {
+		case <-r.ctx.Done():
+			return
+		case msg, ok := <-msgs:
+			if !ok {
+				return
+			}
+
+			m := roomMigration{}
+			if err := json.Unmarshal([]byte(msg.Payload), &m); err != nil {
+				logger.Errorw("could not decode room migration", err)
+				continue
+			}
+			r.handleRoomMigration(r.ctx, m.RoomName, m.NodeID)
+		}
+	}
+}
+
 func (r *RedisRouter) keepaliveWorker(startedChan chan error) {
 	pings, err := r.kps.SubscribePing(r.ctx, livekit.NodeID(r.currentNode.Id))
 	if err != nil {
@@ -62,6 +62,25 @@ type FakeRouter struct {
 		result1 []*livekit.Node
 		result2 error
 	}
+	NotifyRoomMigrationStub        func(context.Context, livekit.NodeID, livekit.RoomName, livekit.NodeID) error
+	notifyRoomMigrationMutex       sync.RWMutex
+	notifyRoomMigrationArgsForCall []struct {
+		arg1 context.Context
+		arg2 livekit.NodeID
+		arg3 livekit.RoomName
+		arg4 livekit.NodeID
+	}
+	notifyRoomMigrationReturns struct {
+		result1 error
+	}
+	notifyRoomMigrationReturnsOnCall map[int]struct {
+		result1 error
+	}
+	OnRoomMigrationStub        func(routing.RoomMigrationHandler)
+	onRoomMigrationMutex       sync.RWMutex
+	onRoomMigrationArgsForCall []struct {
+		arg1 routing.RoomMigrationHandler
+	}
 	RegisterNodeStub        func() error
 	registerNodeMutex       sync.RWMutex
 	registerNodeArgsForCall []struct {
@@ -398,6 +417,102 @@ func (fake *FakeRouter) ListNodesReturnsOnCall(i int, result1 []*livekit.Node, r
 	}{result1, result2}
 }
 
+func (fake *FakeRouter) NotifyRoomMigration(arg1 context.Context, arg2 livekit.NodeID, arg3 livekit.RoomName, arg4 livekit.NodeID) error {
+	fake.notifyRoomMigrationMutex.Lock()
+	ret, specificReturn := fake.notifyRoomMigrationReturnsOnCall[len(fake.notifyRoomMigrationArgsForCall)]
+	fake.notifyRoomMigrationArgsForCall = append(fake.notifyRoomMigrationArgsForCall, struct {
+		arg1 context.Context
+		arg2 livekit.NodeID
+		arg3 livekit.RoomName
+		arg4 livekit.NodeID
+	}{arg1, arg2, arg3, arg4})
+	stub := fake.NotifyRoomMigrationStub
+	fakeReturns := fake.notifyRoomMigrationReturns
+	fake.recordInvocation("NotifyRoomMigration", []interface{}{arg1, arg2, arg3, arg4})
+	fake.notifyRoomMigrationMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeRouter) NotifyRoomMigrationCallCount() int {
+	fake.notifyRoomMigrationMutex.RLock()
+	defer fake.notifyRoomMigrationMutex.RUnlock()
+	return len(fake.notifyRoomMigrationArgsForCall)
+}
+
+func (fake *FakeRouter) NotifyRoomMigrationCalls(stub func(context.Context, livekit.NodeID, livekit.RoomName, livekit.NodeID) error) {
+	fake.notifyRoomMigrationMutex.Lock()
+	defer fake.notifyRoomMigrationMutex.Unlock()
+	fake.NotifyRoomMigrationStub = stub
+}
+
+func (fake *FakeRouter) NotifyRoomMigrationArgsForCall(i int) (context.Context, livekit.NodeID, livekit.RoomName, livekit.NodeID) {
+	fake.notifyRoomMigrationMutex.RLock()
+	defer fake.notifyRoomMigrationMutex.RUnlock()
+	argsForCall := fake.notifyRoomMigrationArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+}
+
+func (fake *FakeRouter) NotifyRoomMigrationReturns(result1 error) {
+	fake.notifyRoomMigrationMutex.Lock()
+	defer fake.notifyRoomMigrationMutex.Unlock()
+	fake.NotifyRoomMigrationStub = nil
+	fake.notifyRoomMigrationReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeRouter) NotifyRoomMigrationReturnsOnCall(i int, result1 error) {
+	fake.notifyRoomMigrationMutex.Lock()
+	defer fake.notifyRoomMigrationMutex.Unlock()
+	fake.NotifyRoomMigrationStub = nil
+	if fake.notifyRoomMigrationReturnsOnCall == nil {
+		fake.notifyRoomMigrationReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.notifyRoomMigrationReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeRouter) OnRoomMigration(arg1 routing.RoomMigrationHandler) {
+	fake.onRoomMigrationMutex.Lock()
+	fake.onRoomMigrationArgsForCall = append(fake.onRoomMigrationArgsForCall, struct {
+		arg1 routing.RoomMigrationHandler
+	}{arg1})
+	stub := fake.OnRoomMigrationStub
+	fake.recordInvocation("OnRoomMigration", []interface{}{arg1})
+	fake.onRoomMigrationMutex.Unlock()
+	if stub != nil {
+		fake.OnRoomMigrationStub(arg1)
+	}
+}
+
+func (fake *FakeRouter) OnRoomMigrationCallCount() int {
+	fake.onRoomMigrationMutex.RLock()
+	defer fake.onRoomMigrationMutex.RUnlock()
+	return len(fake.onRoomMigrationArgsForCall)
+}
+
+func (fake *FakeRouter) OnRoomMigrationCalls(stub func(routing.RoomMigrationHandler)) {
+	fake.onRoomMigrationMutex.Lock()
+	defer fake.onRoomMigrationMutex.Unlock()
+	fake.OnRoomMigrationStub = stub
+}
+
+func (fake *FakeRouter) OnRoomMigrationArgsForCall(i int) routing.RoomMigrationHandler {
+	fake.onRoomMigrationMutex.RLock()
+	defer fake.onRoomMigrationMutex.RUnlock()
+	argsForCall := fake.onRoomMigrationArgsForCall[i]
+	return argsForCall.arg1
+}
+
 func (fake *FakeRouter) RegisterNode() error {
 	fake.registerNodeMutex.Lock()
 	ret, specificReturn := fake.registerNodeReturnsOnCall[len(fake.registerNodeArgsForCall)]
@@ -776,6 +891,10 @@ func (fake *FakeRouter) Invocations() map[string][][]interface{} {
 	defer fake.getRegionMutex.RUnlock()
 	fake.listNodesMutex.RLock()
 	defer fake.listNodesMutex.RUnlock()
+	fake.notifyRoomMigrationMutex.RLock()
+	defer fake.notifyRoomMigrationMutex.RUnlock()
+	fake.onRoomMigrationMutex.RLock()
+	defer fake.onRoomMigrationMutex.RUnlock()
 	fake.registerNodeMutex.RLock()
 	defer fake.registerNodeMutex.RUnlock()
 	fake.removeDeadNodesMutex.RLock()
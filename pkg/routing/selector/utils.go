@@ -44,6 +44,13 @@ func GetAvailableNodes(nodes []*livekit.Node) []*livekit.Node {
 	}).([]*livekit.Node)
 }
 
+// GetNodesInRegion returns available nodes of the given region
+func GetNodesInRegion(nodes []*livekit.Node, region string) []*livekit.Node {
+	return funk.Filter(nodes, func(node *livekit.Node) bool {
+		return node.Region == region && IsAvailable(node)
+	}).([]*livekit.Node)
+}
+
 func GetNodeSysload(node *livekit.Node) float32 {
 	stats := node.Stats
 	numCpus := stats.NumCpus
@@ -41,6 +41,7 @@ type WebRTCConfig struct {
 type ReceiverConfig struct {
 	PacketBufferSizeVideo int
 	PacketBufferSizeAudio int
+	MaxRetransmissions    int
 }
 
 type RTPHeaderExtensionConfig struct {
@@ -146,6 +147,7 @@ func NewWebRTCConfig(conf *config.Config) (*WebRTCConfig, error) {
 		Receiver: ReceiverConfig{
 			PacketBufferSizeVideo: rtcConf.PacketBufferSizeVideo,
 			PacketBufferSizeAudio: rtcConf.PacketBufferSizeAudio,
+			MaxRetransmissions:    rtcConf.MaxRetransmissions,
 		},
 		Publisher:  publisherConfig,
 		Subscriber: subscriberConfig,
@@ -71,6 +71,10 @@ type MediaTrackParams struct {
 	SimTracks           map[uint32]SimulcastTrackInfo
 	OnRTCP              func([]rtcp.Packet)
 	ForwardStats        *sfu.ForwardStats
+	TrackForwardStats   bool
+	RoomBitrateLimiter  *sfu.RoomBitrateLimiter
+	RTPStatsStreamer    *sfu.RTPStatsStreamer
+	PlayoutDelay        *livekit.PlayoutDelay
 }
 
 func NewMediaTrack(params MediaTrackParams, ti *livekit.TrackInfo) *MediaTrack {
@@ -206,9 +210,13 @@ func (t *MediaTrack) AddReceiver(receiver *webrtc.RTPReceiver, track *webrtc.Tra
 		for _, pkt := range pkts {
 			switch pkt := pkt.(type) {
 			case *rtcp.SourceDescription:
+			case *rtcp.Goodbye:
+				if wr, ok := t.MediaTrackReceiver.Receiver(strings.ToLower(track.Codec().MimeType)).(*sfu.WebRTCReceiver); ok {
+					wr.HandleRTCP([]rtcp.Packet{pkt})
+				}
 			case *rtcp.SenderReport:
 				if pkt.SSRC == uint32(track.SSRC()) {
-					buff.SetSenderReportData(pkt.RTPTime, pkt.NTPTime)
+					buff.SetSenderReport(pkt)
 				}
 			case *rtcp.ExtendedReport:
 			rttFromXR:
@@ -283,6 +291,10 @@ func (t *MediaTrack) AddReceiver(receiver *webrtc.RTPReceiver, track *webrtc.Tra
 			sfu.WithLoadBalanceThreshold(20),
 			sfu.WithStreamTrackers(),
 			sfu.WithForwardStats(t.params.ForwardStats),
+			sfu.WithTrackForwardStats(t.params.TrackForwardStats),
+			sfu.WithRoomBitrateLimiter(t.params.RoomBitrateLimiter),
+			sfu.WithRTPStatsStreamer(t.params.RTPStatsStreamer),
+			sfu.WithPlayoutDelay(t.params.PlayoutDelay),
 		)
 		newWR.OnCloseHandler(func() {
 			t.MediaTrackReceiver.SetClosing()
@@ -389,6 +401,30 @@ func (t *MediaTrack) GetConnectionScoreAndQuality() (float32, livekit.Connection
 	return connectionquality.MaxMOS, livekit.ConnectionQuality_EXCELLENT
 }
 
+func (t *MediaTrack) IsActive() bool {
+	if rtcReceiver, ok := t.PrimaryReceiver().(*sfu.WebRTCReceiver); ok {
+		return rtcReceiver.IsActive()
+	}
+
+	return false
+}
+
+func (t *MediaTrack) LastPacketTime() time.Time {
+	if rtcReceiver, ok := t.PrimaryReceiver().(*sfu.WebRTCReceiver); ok {
+		return rtcReceiver.LastPacketTime()
+	}
+
+	return time.Time{}
+}
+
+func (t *MediaTrack) GetInactiveDuration() time.Duration {
+	if rtcReceiver, ok := t.PrimaryReceiver().(*sfu.WebRTCReceiver); ok {
+		return rtcReceiver.GetInactiveDuration()
+	}
+
+	return 0
+}
+
 func (t *MediaTrack) SetRTT(rtt uint32) {
 	if !t.rttFromXR.Load() {
 		t.MediaTrackReceiver.SetRTT(rtt)
@@ -141,7 +141,7 @@ func (t *MediaTrackSubscriptions) AddSubscriber(sub types.LocalParticipant, wr *
 		Trailer:           trailer,
 		Logger:            LoggerWithTrack(sub.GetLogger().WithComponent(sutils.ComponentSub), trackID, t.params.IsRelayed),
 		RTCPWriter:        sub.WriteSubscriberRTCP,
-	})
+	}, sfu.WithSequencerMaxRetransmissions(t.params.ReceiverConfig.MaxRetransmissions))
 	if err != nil {
 		return nil, err
 	}
@@ -140,8 +140,12 @@ type ParticipantParams struct {
 	SubscriptionLimitAudio       int32
 	SubscriptionLimitVideo       int32
 	PlayoutDelay                 *livekit.PlayoutDelay
+	EnforcePlayoutDelay          bool
 	SyncStreams                  bool
 	ForwardStats                 *sfu.ForwardStats
+	TrackForwardStats            bool
+	RoomBitrateLimiter           *sfu.RoomBitrateLimiter
+	RTPStatsStreamer             *sfu.RTPStatsStreamer
 }
 
 type ParticipantImpl struct {
@@ -250,9 +254,10 @@ func NewParticipant(params ParticipantParams) (*ParticipantImpl, error) {
 		params:       params,
 		disconnected: make(chan struct{}),
 		pubRTCPQueue: sutils.NewTypedOpsQueue[postRtcpOp](sutils.OpsQueueParams{
-			Name:    "pub-rtcp",
-			MinSize: 64,
-			Logger:  params.Logger,
+			Name:                "pub-rtcp",
+			MinSize:             64,
+			TrackProcessingTime: true,
+			Logger:              params.Logger,
 		}),
 		pendingTracks:           make(map[string]*pendingTrackInfo),
 		pendingPublishingTracks: make(map[livekit.TrackID]*pendingTrackInfo),
@@ -2100,6 +2105,10 @@ func (p *ParticipantImpl) addMediaTrack(signalCid string, sdpCid string, ti *liv
 		SimTracks:           p.params.SimTracks,
 		OnRTCP:              p.postRtcp,
 		ForwardStats:        p.params.ForwardStats,
+		TrackForwardStats:   p.params.TrackForwardStats,
+		RoomBitrateLimiter:  p.params.RoomBitrateLimiter,
+		RTPStatsStreamer:    p.params.RTPStatsStreamer,
+		PlayoutDelay:        p.getEnforcedPlayoutDelay(),
 	}, ti)
 
 	mt.OnSubscribedMaxQualityChange(p.onSubscribedMaxQualityChange)
@@ -2347,6 +2356,7 @@ func (p *ParticipantImpl) DebugInfo() map[string]interface{} {
 	info["PendingTracks"] = pendingTrackInfo
 
 	info["UpTrackManager"] = p.UpTrackManager.DebugInfo()
+	info["PubRTCPQueue"] = p.pubRTCPQueue.GetStats()
 
 	return info
 }
@@ -2501,6 +2511,14 @@ func (p *ParticipantImpl) UpdateMediaLoss(nodeID livekit.NodeID, trackID livekit
 	return nil
 }
 
+// playout delay to hold published packets for on the SFU, nil if not enforced
+func (p *ParticipantImpl) getEnforcedPlayoutDelay() *livekit.PlayoutDelay {
+	if !p.params.EnforcePlayoutDelay {
+		return nil
+	}
+	return p.params.PlayoutDelay
+}
+
 func (p *ParticipantImpl) GetPlayoutDelayConfig() *livekit.PlayoutDelay {
 	return p.params.PlayoutDelay
 }
@@ -40,6 +40,7 @@ import (
 	"github.com/livekit/livekit-server/pkg/config"
 	"github.com/livekit/livekit-server/pkg/routing"
 	"github.com/livekit/livekit-server/pkg/rtc/types"
+	"github.com/livekit/livekit-server/pkg/sfu"
 	"github.com/livekit/livekit-server/pkg/sfu/buffer"
 	"github.com/livekit/livekit-server/pkg/sfu/connectionquality"
 	"github.com/livekit/livekit-server/pkg/telemetry"
@@ -111,6 +112,7 @@ type Room struct {
 	participantRequestSources map[livekit.ParticipantIdentity]routing.MessageSource
 	hasPublished              map[livekit.ParticipantIdentity]bool
 	bufferFactory             *buffer.FactoryOfBufferFactory
+	bitrateLimiter            *sfu.RoomBitrateLimiter
 
 	// batch update participant info for non-publishers
 	batchedUpdates   map[livekit.ParticipantIdentity]*participantUpdate
@@ -120,9 +122,10 @@ type Room struct {
 
 	trailer []byte
 
-	onParticipantChanged func(p types.LocalParticipant)
-	onRoomUpdated        func()
-	onClose              func()
+	onParticipantChanged       func(p types.LocalParticipant)
+	onParticipantQualityChange func(participantID livekit.ParticipantID, score float32, quality livekit.ConnectionQuality)
+	onRoomUpdated              func()
+	onClose                    func()
 
 	simulationLock                                 sync.Mutex
 	disconnectSignalOnResumeParticipants           map[livekit.ParticipantIdentity]time.Time
@@ -180,6 +183,9 @@ func NewRoom(
 	if r.protoRoom.CreationTime == 0 {
 		r.protoRoom.CreationTime = time.Now().Unix()
 	}
+	if roomConfig.MaxTotalBitrateBps > 0 {
+		r.bitrateLimiter = sfu.NewRoomBitrateLimiter(roomConfig.MaxTotalBitrateBps)
+	}
 	r.protoProxy = utils.NewProtoProxy[*livekit.Room](roomUpdateInterval, r.updateProto)
 
 	go r.audioUpdateWorker()
@@ -275,6 +281,11 @@ func (r *Room) GetActiveSpeakers() []*livekit.SpeakerInfo {
 	return speakers
 }
 
+// BitrateLimiter returns the limiter shared by receivers of tracks published in the room, nil if there is no bitrate cap
+func (r *Room) BitrateLimiter() *sfu.RoomBitrateLimiter {
+	return r.bitrateLimiter
+}
+
 func (r *Room) GetBufferFactory() *buffer.Factory {
 	return r.bufferFactory.CreateBufferFactory()
 }
@@ -808,6 +819,14 @@ func (r *Room) OnParticipantChanged(f func(participant types.LocalParticipant))
 	r.onParticipantChanged = f
 }
 
+// OnParticipantQualityChange sets a callback which is invoked when the connection quality of media
+// published by a participant changes. Quality of a participant is the lowest quality of its published tracks.
+func (r *Room) OnParticipantQualityChange(f func(participantID livekit.ParticipantID, score float32, quality livekit.ConnectionQuality)) {
+	r.lock.Lock()
+	r.onParticipantQualityChange = f
+	r.lock.Unlock()
+}
+
 func (r *Room) SendDataPacket(dp *livekit.DataPacket, kind livekit.DataPacket_Kind) {
 	r.onDataPacket(nil, kind, dp)
 }
@@ -1312,11 +1331,13 @@ func (r *Room) connectionQualityWorker() {
 	defer ticker.Stop()
 
 	prevConnectionInfos := make(map[livekit.ParticipantID]*livekit.ConnectionQualityInfo)
+	prevPublisherQualities := make(map[livekit.ParticipantID]livekit.ConnectionQuality)
 	// send updates to only users that are subscribed to each other
 	for !r.IsClosed() {
 		<-ticker.C
 
 		participants := r.GetParticipants()
+		prevPublisherQualities = r.notifyParticipantQualityChanges(participants, prevPublisherQualities)
 		nowConnectionInfos := make(map[livekit.ParticipantID]*livekit.ConnectionQualityInfo, len(participants))
 
 		for _, p := range participants {
@@ -1387,6 +1408,66 @@ func (r *Room) connectionQualityWorker() {
 	}
 }
 
+// notifyParticipantQualityChanges invokes the participant quality change callback for
+// active participants whose publisher side quality differs from the last notified value.
+// Returns the qualities to compare against in the next call.
+func (r *Room) notifyParticipantQualityChanges(
+	participants []types.LocalParticipant,
+	prevQualities map[livekit.ParticipantID]livekit.ConnectionQuality,
+) map[livekit.ParticipantID]livekit.ConnectionQuality {
+	r.lock.RLock()
+	onParticipantQualityChange := r.onParticipantQualityChange
+	r.lock.RUnlock()
+	if onParticipantQualityChange == nil {
+		return prevQualities
+	}
+
+	nowQualities := make(map[livekit.ParticipantID]livekit.ConnectionQuality, len(participants))
+	for _, p := range participants {
+		if p.State() != livekit.ParticipantInfo_ACTIVE {
+			continue
+		}
+
+		score, quality, ok := getPublisherConnectionScoreAndQuality(p)
+		if !ok {
+			continue
+		}
+
+		pID := p.ID()
+		nowQualities[pID] = quality
+		if prevQuality, prevOk := prevQualities[pID]; !prevOk || prevQuality != quality {
+			onParticipantQualityChange(pID, score, quality)
+		}
+	}
+
+	return nowQualities
+}
+
+// getPublisherConnectionScoreAndQuality returns the lowest connection score and quality
+// across receivers of tracks published by the participant, ok is false if it has none.
+func getPublisherConnectionScoreAndQuality(p types.LocalParticipant) (float32, livekit.ConnectionQuality, bool) {
+	found := false
+	minQuality := livekit.ConnectionQuality_EXCELLENT
+	minScore := connectionquality.MaxMOS
+	for _, pt := range p.GetPublishedTracks() {
+		lmt, ok := pt.(types.LocalMediaTrack)
+		if !ok {
+			continue
+		}
+
+		score, quality := lmt.GetConnectionScoreAndQuality()
+		if !found || utils.IsConnectionQualityLower(minQuality, quality) {
+			minQuality = quality
+			minScore = score
+		} else if quality == minQuality && score < minScore {
+			minScore = score
+		}
+		found = true
+	}
+
+	return minScore, minQuality, found
+}
+
 func (r *Room) simulationCleanupWorker() {
 	for {
 		if r.IsClosed() {
@@ -829,3 +829,48 @@ func newRoomWithParticipants(t *testing.T, opts testRoomOpts) *Room {
 	}
 	return rm
 }
+
+func TestParticipantQualityChange(t *testing.T) {
+	rm := newRoomWithParticipants(t, testRoomOpts{num: 2})
+	defer rm.Close(types.ParticipantCloseReasonNone)
+
+	participants := rm.GetParticipants()
+	p0 := participants[0].(*typesfakes.FakeLocalParticipant)
+	p1 := participants[1].(*typesfakes.FakeLocalParticipant)
+
+	good := &typesfakes.FakeLocalMediaTrack{}
+	good.GetConnectionScoreAndQualityReturns(4.5, livekit.ConnectionQuality_EXCELLENT)
+	bad := &typesfakes.FakeLocalMediaTrack{}
+	bad.GetConnectionScoreAndQualityReturns(3.5, livekit.ConnectionQuality_GOOD)
+	p0.GetPublishedTracksReturns([]types.MediaTrack{good, bad})
+	p1.GetPublishedTracksReturns([]types.MediaTrack{good})
+
+	type qualityChange struct {
+		participantID livekit.ParticipantID
+		score         float32
+		quality       livekit.ConnectionQuality
+	}
+	var changes []qualityChange
+	rm.OnParticipantQualityChange(func(participantID livekit.ParticipantID, score float32, quality livekit.ConnectionQuality) {
+		changes = append(changes, qualityChange{participantID, score, quality})
+	})
+
+	// first evaluation notifies all publishers, lowest quality track determines participant quality
+	prev := rm.notifyParticipantQualityChanges(participants, nil)
+	require.ElementsMatch(t, []qualityChange{
+		{p0.ID(), 3.5, livekit.ConnectionQuality_GOOD},
+		{p1.ID(), 4.5, livekit.ConnectionQuality_EXCELLENT},
+	}, changes)
+
+	// no change in quality, even with a score change, does not notify
+	changes = nil
+	good.GetConnectionScoreAndQualityReturns(4.3, livekit.ConnectionQuality_EXCELLENT)
+	prev = rm.notifyParticipantQualityChanges(participants, prev)
+	require.Empty(t, changes)
+
+	// quality drop notifies only affected participant
+	good.GetConnectionScoreAndQualityReturns(2.0, livekit.ConnectionQuality_POOR)
+	p0.GetPublishedTracksReturns([]types.MediaTrack{bad})
+	_ = rm.notifyParticipantQualityChanges(participants, prev)
+	require.Equal(t, []qualityChange{{p1.ID(), 2.0, livekit.ConnectionQuality_POOR}}, changes)
+}
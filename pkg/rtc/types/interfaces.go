@@ -504,6 +504,10 @@ type LocalMediaTrack interface {
 	GetConnectionScoreAndQuality() (float32, livekit.ConnectionQuality)
 	GetTrackStats() *livekit.RTPStats
 
+	IsActive() bool
+	LastPacketTime() time.Time
+	GetInactiveDuration() time.Duration
+
 	SetRTT(rtt uint32)
 
 	NotifySubscriberNodeMaxQuality(nodeID livekit.NodeID, qualities []SubscribedCodecQuality)
@@ -3,6 +3,7 @@ package typesfakes
 
 import (
 	"sync"
+	"time"
 
 	"github.com/livekit/livekit-server/pkg/rtc/types"
 	"github.com/livekit/livekit-server/pkg/sfu"
@@ -72,6 +73,16 @@ type FakeLocalMediaTrack struct {
 		result1 float32
 		result2 livekit.ConnectionQuality
 	}
+	GetInactiveDurationStub        func() time.Duration
+	getInactiveDurationMutex       sync.RWMutex
+	getInactiveDurationArgsForCall []struct {
+	}
+	getInactiveDurationReturns struct {
+		result1 time.Duration
+	}
+	getInactiveDurationReturnsOnCall map[int]struct {
+		result1 time.Duration
+	}
 	GetNumSubscribersStub        func() int
 	getNumSubscribersMutex       sync.RWMutex
 	getNumSubscribersArgsForCall []struct {
@@ -138,6 +149,16 @@ type FakeLocalMediaTrack struct {
 	iDReturnsOnCall map[int]struct {
 		result1 livekit.TrackID
 	}
+	IsActiveStub        func() bool
+	isActiveMutex       sync.RWMutex
+	isActiveArgsForCall []struct {
+	}
+	isActiveReturns struct {
+		result1 bool
+	}
+	isActiveReturnsOnCall map[int]struct {
+		result1 bool
+	}
 	IsEncryptedStub        func() bool
 	isEncryptedMutex       sync.RWMutex
 	isEncryptedArgsForCall []struct {
@@ -199,6 +220,16 @@ type FakeLocalMediaTrack struct {
 	kindReturnsOnCall map[int]struct {
 		result1 livekit.TrackType
 	}
+	LastPacketTimeStub        func() time.Time
+	lastPacketTimeMutex       sync.RWMutex
+	lastPacketTimeArgsForCall []struct {
+	}
+	lastPacketTimeReturns struct {
+		result1 time.Time
+	}
+	lastPacketTimeReturnsOnCall map[int]struct {
+		result1 time.Time
+	}
 	NameStub        func() string
 	nameMutex       sync.RWMutex
 	nameArgsForCall []struct {
@@ -676,6 +707,59 @@ func (fake *FakeLocalMediaTrack) GetConnectionScoreAndQualityReturnsOnCall(i int
 	}{result1, result2}
 }
 
+func (fake *FakeLocalMediaTrack) GetInactiveDuration() time.Duration {
+	fake.getInactiveDurationMutex.Lock()
+	ret, specificReturn := fake.getInactiveDurationReturnsOnCall[len(fake.getInactiveDurationArgsForCall)]
+	fake.getInactiveDurationArgsForCall = append(fake.getInactiveDurationArgsForCall, struct {
+	}{})
+	stub := fake.GetInactiveDurationStub
+	fakeReturns := fake.getInactiveDurationReturns
+	fake.recordInvocation("GetInactiveDuration", []interface{}{})
+	fake.getInactiveDurationMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeLocalMediaTrack) GetInactiveDurationCallCount() int {
+	fake.getInactiveDurationMutex.RLock()
+	defer fake.getInactiveDurationMutex.RUnlock()
+	return len(fake.getInactiveDurationArgsForCall)
+}
+
+func (fake *FakeLocalMediaTrack) GetInactiveDurationCalls(stub func() time.Duration) {
+	fake.getInactiveDurationMutex.Lock()
+	defer fake.getInactiveDurationMutex.Unlock()
+	fake.GetInactiveDurationStub = stub
+}
+
+func (fake *FakeLocalMediaTrack) GetInactiveDurationReturns(result1 time.Duration) {
+	fake.getInactiveDurationMutex.Lock()
+	defer fake.getInactiveDurationMutex.Unlock()
+	fake.GetInactiveDurationStub = nil
+	fake.getInactiveDurationReturns = struct {
+		result1 time.Duration
+	}{result1}
+}
+
+func (fake *FakeLocalMediaTrack) GetInactiveDurationReturnsOnCall(i int, result1 time.Duration) {
+	fake.getInactiveDurationMutex.Lock()
+	defer fake.getInactiveDurationMutex.Unlock()
+	fake.GetInactiveDurationStub = nil
+	if fake.getInactiveDurationReturnsOnCall == nil {
+		fake.getInactiveDurationReturnsOnCall = make(map[int]struct {
+			result1 time.Duration
+		})
+	}
+	fake.getInactiveDurationReturnsOnCall[i] = struct {
+		result1 time.Duration
+	}{result1}
+}
+
 func (fake *FakeLocalMediaTrack) GetNumSubscribers() int {
 	fake.getNumSubscribersMutex.Lock()
 	ret, specificReturn := fake.getNumSubscribersReturnsOnCall[len(fake.getNumSubscribersArgsForCall)]
@@ -695,6 +779,8 @@ func (fake *FakeLocalMediaTrack) GetNumSubscribers() int {
 }
 
 func (fake *FakeLocalMediaTrack) GetNumSubscribersCallCount() int {
+	fake.getInactiveDurationMutex.RLock()
+	defer fake.getInactiveDurationMutex.RUnlock()
 	fake.getNumSubscribersMutex.RLock()
 	defer fake.getNumSubscribersMutex.RUnlock()
 	return len(fake.getNumSubscribersArgsForCall)
@@ -1021,6 +1107,59 @@ func (fake *FakeLocalMediaTrack) IDReturnsOnCall(i int, result1 livekit.TrackID)
 	}{result1}
 }
 
+func (fake *FakeLocalMediaTrack) IsActive() bool {
+	fake.isActiveMutex.Lock()
+	ret, specificReturn := fake.isActiveReturnsOnCall[len(fake.isActiveArgsForCall)]
+	fake.isActiveArgsForCall = append(fake.isActiveArgsForCall, struct {
+	}{})
+	stub := fake.IsActiveStub
+	fakeReturns := fake.isActiveReturns
+	fake.recordInvocation("IsActive", []interface{}{})
+	fake.isActiveMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeLocalMediaTrack) IsActiveCallCount() int {
+	fake.isActiveMutex.RLock()
+	defer fake.isActiveMutex.RUnlock()
+	return len(fake.isActiveArgsForCall)
+}
+
+func (fake *FakeLocalMediaTrack) IsActiveCalls(stub func() bool) {
+	fake.isActiveMutex.Lock()
+	defer fake.isActiveMutex.Unlock()
+	fake.IsActiveStub = stub
+}
+
+func (fake *FakeLocalMediaTrack) IsActiveReturns(result1 bool) {
+	fake.isActiveMutex.Lock()
+	defer fake.isActiveMutex.Unlock()
+	fake.IsActiveStub = nil
+	fake.isActiveReturns = struct {
+		result1 bool
+	}{result1}
+}
+
+func (fake *FakeLocalMediaTrack) IsActiveReturnsOnCall(i int, result1 bool) {
+	fake.isActiveMutex.Lock()
+	defer fake.isActiveMutex.Unlock()
+	fake.IsActiveStub = nil
+	if fake.isActiveReturnsOnCall == nil {
+		fake.isActiveReturnsOnCall = make(map[int]struct {
+			result1 bool
+		})
+	}
+	fake.isActiveReturnsOnCall[i] = struct {
+		result1 bool
+	}{result1}
+}
+
 func (fake *FakeLocalMediaTrack) IsEncrypted() bool {
 	fake.isEncryptedMutex.Lock()
 	ret, specificReturn := fake.isEncryptedReturnsOnCall[len(fake.isEncryptedArgsForCall)]
@@ -1040,6 +1179,8 @@ func (fake *FakeLocalMediaTrack) IsEncrypted() bool {
 }
 
 func (fake *FakeLocalMediaTrack) IsEncryptedCallCount() int {
+	fake.isActiveMutex.RLock()
+	defer fake.isActiveMutex.RUnlock()
 	fake.isEncryptedMutex.RLock()
 	defer fake.isEncryptedMutex.RUnlock()
 	return len(fake.isEncryptedArgsForCall)
@@ -1347,6 +1488,59 @@ func (fake *FakeLocalMediaTrack) KindReturnsOnCall(i int, result1 livekit.TrackT
 	}{result1}
 }
 
+func (fake *FakeLocalMediaTrack) LastPacketTime() time.Time {
+	fake.lastPacketTimeMutex.Lock()
+	ret, specificReturn := fake.lastPacketTimeReturnsOnCall[len(fake.lastPacketTimeArgsForCall)]
+	fake.lastPacketTimeArgsForCall = append(fake.lastPacketTimeArgsForCall, struct {
+	}{})
+	stub := fake.LastPacketTimeStub
+	fakeReturns := fake.lastPacketTimeReturns
+	fake.recordInvocation("LastPacketTime", []interface{}{})
+	fake.lastPacketTimeMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeLocalMediaTrack) LastPacketTimeCallCount() int {
+	fake.lastPacketTimeMutex.RLock()
+	defer fake.lastPacketTimeMutex.RUnlock()
+	return len(fake.lastPacketTimeArgsForCall)
+}
+
+func (fake *FakeLocalMediaTrack) LastPacketTimeCalls(stub func() time.Time) {
+	fake.lastPacketTimeMutex.Lock()
+	defer fake.lastPacketTimeMutex.Unlock()
+	fake.LastPacketTimeStub = stub
+}
+
+func (fake *FakeLocalMediaTrack) LastPacketTimeReturns(result1 time.Time) {
+	fake.lastPacketTimeMutex.Lock()
+	defer fake.lastPacketTimeMutex.Unlock()
+	fake.LastPacketTimeStub = nil
+	fake.lastPacketTimeReturns = struct {
+		result1 time.Time
+	}{result1}
+}
+
+func (fake *FakeLocalMediaTrack) LastPacketTimeReturnsOnCall(i int, result1 time.Time) {
+	fake.lastPacketTimeMutex.Lock()
+	defer fake.lastPacketTimeMutex.Unlock()
+	fake.LastPacketTimeStub = nil
+	if fake.lastPacketTimeReturnsOnCall == nil {
+		fake.lastPacketTimeReturnsOnCall = make(map[int]struct {
+			result1 time.Time
+		})
+	}
+	fake.lastPacketTimeReturnsOnCall[i] = struct {
+		result1 time.Time
+	}{result1}
+}
+
 func (fake *FakeLocalMediaTrack) Name() string {
 	fake.nameMutex.Lock()
 	ret, specificReturn := fake.nameReturnsOnCall[len(fake.nameArgsForCall)]
@@ -1366,6 +1560,8 @@ func (fake *FakeLocalMediaTrack) Name() string {
 }
 
 func (fake *FakeLocalMediaTrack) NameCallCount() int {
+	fake.lastPacketTimeMutex.RLock()
+	defer fake.lastPacketTimeMutex.RUnlock()
 	fake.nameMutex.RLock()
 	defer fake.nameMutex.RUnlock()
 	return len(fake.nameArgsForCall)
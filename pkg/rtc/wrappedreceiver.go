@@ -18,6 +18,7 @@ import (
 	"errors"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/pion/webrtc/v3"
 	"go.uber.org/atomic"
@@ -116,6 +117,18 @@ func (r *WrappedReceiver) Codecs() []webrtc.RTPCodecParameters {
 	return codecs
 }
 
+// GetRetransmissionTimeout can be called before the receiver is determined,
+// receivers of a track share the same kind and hence the same timeout
+func (r *WrappedReceiver) GetRetransmissionTimeout() time.Duration {
+	if r.TrackReceiver != nil {
+		return r.TrackReceiver.GetRetransmissionTimeout()
+	}
+	if len(r.receivers) > 0 {
+		return r.receivers[0].GetRetransmissionTimeout()
+	}
+	return 0
+}
+
 func (r *WrappedReceiver) DeleteDownTrack(participantID livekit.ParticipantID) {
 	if r.TrackReceiver != nil {
 		r.TrackReceiver.DeleteDownTrack(participantID)
@@ -322,3 +335,24 @@ func (d *DummyReceiver) GetTrackStats() *livekit.RTPStats {
 	}
 	return nil
 }
+
+func (d *DummyReceiver) GetSSRCForLayer(layer int32) uint32 {
+	if r, ok := d.receiver.Load().(sfu.TrackReceiver); ok {
+		return r.GetSSRCForLayer(layer)
+	}
+	return 0
+}
+
+func (d *DummyReceiver) IsSVC() bool {
+	if r, ok := d.receiver.Load().(sfu.TrackReceiver); ok {
+		return r.IsSVC()
+	}
+	return sfu.IsSvcCodec(d.codec.MimeType)
+}
+
+func (d *DummyReceiver) GetRetransmissionTimeout() time.Duration {
+	if r, ok := d.receiver.Load().(sfu.TrackReceiver); ok {
+		return r.GetRetransmissionTimeout()
+	}
+	return 0
+}
@@ -0,0 +1,90 @@
+// Copyright 2024 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/livekit/protocol/livekit"
+	"github.com/livekit/protocol/logger"
+)
+
+// AuditLogger records room creations
+type AuditLogger interface {
+	LogRoomCreated(ctx context.Context, room *livekit.Room, nodeID livekit.NodeID)
+}
+
+type roomCreatedRecord struct {
+	Time     time.Time `json:"time"`
+	Room     string    `json:"room"`
+	RoomID   string    `json:"roomId"`
+	NodeID   string    `json:"nodeId"`
+	APIKey   string    `json:"apiKey,omitempty"`
+	Identity string    `json:"identity,omitempty"`
+}
+
+// FileAuditLogger appends a JSON line per record to a file
+type FileAuditLogger struct {
+	path string
+	lock sync.Mutex
+}
+
+func NewFileAuditLogger(path string) *FileAuditLogger {
+	return &FileAuditLogger{
+		path: path,
+	}
+}
+
+func (l *FileAuditLogger) LogRoomCreated(ctx context.Context, room *livekit.Room, nodeID livekit.NodeID) {
+	record := roomCreatedRecord{
+		Time:   time.Now(),
+		Room:   room.Name,
+		RoomID: room.Sid,
+		NodeID: string(nodeID),
+		APIKey: GetAPIKey(ctx),
+	}
+	if grants := GetGrants(ctx); grants != nil {
+		record.Identity = grants.Identity
+	}
+
+	if err := l.write(record); err != nil {
+		logger.Warnw("could not write audit log", err, "path", l.path, "room", room.Name)
+	}
+}
+
+func (l *FileAuditLogger) write(record any) error {
+	line, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	f, err := os.OpenFile(l.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err = f.Write(line); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
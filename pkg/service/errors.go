@@ -16,6 +16,8 @@ package service
 
 import (
 	"github.com/livekit/psrpc"
+
+	"github.com/livekit/livekit-server/pkg/routing"
 )
 
 var (
@@ -33,6 +35,10 @@ var (
 	ErrRoomNotFound                     = psrpc.NewErrorf(psrpc.NotFound, "requested room does not exist")
 	ErrRoomLockFailed                   = psrpc.NewErrorf(psrpc.Internal, "could not lock room")
 	ErrRoomUnlockFailed                 = psrpc.NewErrorf(psrpc.Internal, "could not unlock room, lock token does not match")
+	ErrRoomTemplateNotFound             = psrpc.NewErrorf(psrpc.NotFound, "requested room template does not exist")
+	ErrRoomUnhealthy                    = psrpc.NewErrorf(psrpc.Unavailable, "room has inactive video tracks")
+	ErrNodeCapacityExceeded             = psrpc.NewError(psrpc.ResourceExhausted, routing.ErrNodeLimitReached)
+	ErrInvalidReservation               = psrpc.NewErrorf(psrpc.InvalidArgument, "number of rooms to reserve must be positive")
 	ErrRemoteUnmuteNoteEnabled          = psrpc.NewErrorf(psrpc.FailedPrecondition, "remote unmute not enabled")
 	ErrTrackNotFound                    = psrpc.NewErrorf(psrpc.NotFound, "track is not found")
 	ErrWebHookMissingAPIKey             = psrpc.NewErrorf(psrpc.InvalidArgument, "api_key is required to use webhooks")
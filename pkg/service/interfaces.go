@@ -38,6 +38,15 @@ type ObjectStore interface {
 
 	StoreParticipant(ctx context.Context, roomName livekit.RoomName, participant *livekit.ParticipantInfo) error
 	DeleteParticipant(ctx context.Context, roomName livekit.RoomName, identity livekit.ParticipantIdentity) error
+
+	StoreRoomTemplate(ctx context.Context, template *RoomTemplate) error
+	LoadRoomTemplate(ctx context.Context, templateID string) (*RoomTemplate, error)
+
+	// reservations hold capacity on a node for rooms that are about to be allocated to it,
+	// shared by all nodes allocating rooms. reserving again refreshes the ttl
+	ReserveNodeRooms(ctx context.Context, nodeID livekit.NodeID, rooms int, ttl time.Duration) error
+	ReleaseNodeReservation(ctx context.Context, nodeID livekit.NodeID) error
+	LoadNodeReservations(ctx context.Context, nodeIDs []livekit.NodeID) (map[livekit.NodeID]int, error)
 }
 
 //counterfeiter:generate . ServiceStore
@@ -75,6 +84,18 @@ type IngressStore interface {
 type RoomAllocator interface {
 	CreateRoom(ctx context.Context, req *livekit.CreateRoomRequest) (*livekit.Room, bool, error)
 	ValidateCreateRoom(ctx context.Context, roomName livekit.RoomName) error
+	RebalanceRoom(ctx context.Context, roomName livekit.RoomName) error
+	GetAllocationStats(ctx context.Context) ([]RoomAllocationStat, error)
+	BlacklistNode(ctx context.Context, nodeID livekit.NodeID, reason string) error
+	UnblacklistNode(ctx context.Context, nodeID livekit.NodeID) error
+	PreWarmNode(ctx context.Context, nodeID livekit.NodeID, rooms int) error
+}
+
+type RoomAllocationStat struct {
+	RoomName         livekit.RoomName
+	RoomID           livekit.RoomID
+	NodeID           livekit.NodeID
+	ParticipantCount uint32
 }
 
 //counterfeiter:generate . SIPStore
@@ -31,17 +31,28 @@ type LocalStore struct {
 	roomInternal map[livekit.RoomName]*livekit.RoomInternal
 	// map of roomName => { identity: participant }
 	participants map[livekit.RoomName]map[livekit.ParticipantIdentity]*livekit.ParticipantInfo
+	// map of templateID => template
+	roomTemplates map[string]*RoomTemplate
+	// map of nodeID => reserved rooms
+	nodeReservations map[livekit.NodeID]*localNodeReservation
 
 	lock       sync.RWMutex
 	globalLock sync.Mutex
 }
 
+type localNodeReservation struct {
+	rooms     int
+	expiresAt time.Time
+}
+
 func NewLocalStore() *LocalStore {
 	return &LocalStore{
-		rooms:        make(map[livekit.RoomName]*livekit.Room),
-		roomInternal: make(map[livekit.RoomName]*livekit.RoomInternal),
-		participants: make(map[livekit.RoomName]map[livekit.ParticipantIdentity]*livekit.ParticipantInfo),
-		lock:         sync.RWMutex{},
+		rooms:            make(map[livekit.RoomName]*livekit.Room),
+		roomInternal:     make(map[livekit.RoomName]*livekit.RoomInternal),
+		participants:     make(map[livekit.RoomName]map[livekit.ParticipantIdentity]*livekit.ParticipantInfo),
+		roomTemplates:    make(map[string]*RoomTemplate),
+		nodeReservations: make(map[livekit.NodeID]*localNodeReservation),
+		lock:             sync.RWMutex{},
 	}
 }
 
@@ -171,3 +182,65 @@ func (s *LocalStore) DeleteParticipant(_ context.Context, roomName livekit.RoomN
 	}
 	return nil
 }
+
+func (s *LocalStore) StoreRoomTemplate(_ context.Context, template *RoomTemplate) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.roomTemplates[template.ID] = template
+	return nil
+}
+
+func (s *LocalStore) LoadRoomTemplate(_ context.Context, templateID string) (*RoomTemplate, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	template := s.roomTemplates[templateID]
+	if template == nil {
+		return nil, ErrRoomTemplateNotFound
+	}
+	return template, nil
+}
+
+func (s *LocalStore) ReserveNodeRooms(_ context.Context, nodeID livekit.NodeID, rooms int, ttl time.Duration) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	res := s.nodeReservations[nodeID]
+	if res == nil || !res.expiresAt.After(time.Now()) {
+		res = &localNodeReservation{}
+		s.nodeReservations[nodeID] = res
+	}
+	res.rooms += rooms
+	res.expiresAt = time.Now().Add(ttl)
+	return nil
+}
+
+func (s *LocalStore) ReleaseNodeReservation(_ context.Context, nodeID livekit.NodeID) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	res := s.nodeReservations[nodeID]
+	if res == nil {
+		return nil
+	}
+	res.rooms--
+	if res.rooms <= 0 || !res.expiresAt.After(time.Now()) {
+		delete(s.nodeReservations, nodeID)
+	}
+	return nil
+}
+
+func (s *LocalStore) LoadNodeReservations(_ context.Context, nodeIDs []livekit.NodeID) (map[livekit.NodeID]int, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	now := time.Now()
+	reservations := make(map[livekit.NodeID]int)
+	for _, nodeID := range nodeIDs {
+		if res := s.nodeReservations[nodeID]; res != nil && res.expiresAt.After(now) {
+			reservations[nodeID] = res.rooms
+		}
+	}
+	return reservations, nil
+}
@@ -16,6 +16,7 @@ package service
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"strconv"
 	"strings"
@@ -41,6 +42,9 @@ const (
 	RoomsKey        = "rooms"
 	RoomInternalKey = "room_internal"
 
+	// RoomTemplateKey is hash of templateID => RoomTemplate JSON
+	RoomTemplateKey = "room_template"
+
 	// EgressKey is a hash of egressID => egress info
 	EgressKey        = "egress"
 	EndedEgressKey   = "ended_egress"
@@ -61,14 +65,18 @@ const (
 	// RoomLockPrefix is a simple key containing a provided lock uid
 	RoomLockPrefix = "room_lock:"
 
+	// NodeReservationPrefix is a simple key containing the number of rooms reserved on a node
+	NodeReservationPrefix = "node_reservation:"
+
 	maxRetries = 5
 )
 
 type RedisStore struct {
-	rc           redis.UniversalClient
-	unlockScript *redis.Script
-	ctx          context.Context
-	done         chan struct{}
+	rc                       redis.UniversalClient
+	unlockScript             *redis.Script
+	releaseReservationScript *redis.Script
+	ctx                      context.Context
+	done                     chan struct{}
 }
 
 func NewRedisStore(rc redis.UniversalClient) *RedisStore {
@@ -77,10 +85,17 @@ func NewRedisStore(rc redis.UniversalClient) *RedisStore {
 					 else return 0
 					 end`
 
+	releaseReservationScript := `local n = tonumber(redis.call("get", KEYS[1]))
+					 if not n then return 0
+					 elseif n <= 1 then return redis.call("del", KEYS[1])
+					 else return redis.call("decr", KEYS[1])
+					 end`
+
 	return &RedisStore{
-		ctx:          context.Background(),
-		rc:           rc,
-		unlockScript: redis.NewScript(unlockScript),
+		ctx:                      context.Background(),
+		rc:                       rc,
+		unlockScript:             redis.NewScript(unlockScript),
+		releaseReservationScript: redis.NewScript(releaseReservationScript),
 	}
 }
 
@@ -332,6 +347,72 @@ func (s *RedisStore) DeleteParticipant(_ context.Context, roomName livekit.RoomN
 	return s.rc.HDel(s.ctx, key, string(identity)).Err()
 }
 
+func (s *RedisStore) StoreRoomTemplate(_ context.Context, template *RoomTemplate) error {
+	data, err := json.Marshal(template)
+	if err != nil {
+		return err
+	}
+
+	return s.rc.HSet(s.ctx, RoomTemplateKey, template.ID, data).Err()
+}
+
+func (s *RedisStore) LoadRoomTemplate(_ context.Context, templateID string) (*RoomTemplate, error) {
+	data, err := s.rc.HGet(s.ctx, RoomTemplateKey, templateID).Result()
+	switch {
+	case err == redis.Nil:
+		return nil, ErrRoomTemplateNotFound
+	case err != nil:
+		return nil, err
+	}
+
+	template := &RoomTemplate{}
+	if err = json.Unmarshal([]byte(data), template); err != nil {
+		return nil, err
+	}
+	return template, nil
+}
+
+func (s *RedisStore) ReserveNodeRooms(_ context.Context, nodeID livekit.NodeID, rooms int, ttl time.Duration) error {
+	key := NodeReservationPrefix + string(nodeID)
+
+	pp := s.rc.TxPipeline()
+	pp.IncrBy(s.ctx, key, int64(rooms))
+	pp.Expire(s.ctx, key, ttl)
+
+	_, err := pp.Exec(s.ctx)
+	return err
+}
+
+func (s *RedisStore) ReleaseNodeReservation(_ context.Context, nodeID livekit.NodeID) error {
+	key := NodeReservationPrefix + string(nodeID)
+	return s.releaseReservationScript.Run(s.ctx, s.rc, []string{key}).Err()
+}
+
+func (s *RedisStore) LoadNodeReservations(_ context.Context, nodeIDs []livekit.NodeID) (map[livekit.NodeID]int, error) {
+	if len(nodeIDs) == 0 {
+		return nil, nil
+	}
+
+	pp := s.rc.Pipeline()
+	cmds := make([]*redis.StringCmd, 0, len(nodeIDs))
+	for _, nodeID := range nodeIDs {
+		cmds = append(cmds, pp.Get(s.ctx, NodeReservationPrefix+string(nodeID)))
+	}
+	if _, err := pp.Exec(s.ctx); err != nil && err != redis.Nil {
+		return nil, err
+	}
+
+	reservations := make(map[livekit.NodeID]int)
+	for i, cmd := range cmds {
+		rooms, err := cmd.Int()
+		if err != nil || rooms <= 0 {
+			continue
+		}
+		reservations[nodeIDs[i]] = rooms
+	}
+	return reservations, nil
+}
+
 func (s *RedisStore) StoreEgress(_ context.Context, info *livekit.EgressInfo) error {
 	data, err := proto.Marshal(info)
 	if err != nil {
@@ -28,6 +28,7 @@ import (
 	"github.com/livekit/protocol/utils"
 	"github.com/livekit/protocol/utils/guid"
 
+	"github.com/livekit/livekit-server/pkg/config"
 	"github.com/livekit/livekit-server/pkg/service"
 )
 
@@ -59,6 +60,51 @@ func TestRoomInternal(t *testing.T) {
 	require.NoError(t, rs.DeleteRoom(ctx, "test_room"))
 }
 
+func TestRoomTemplateStore(t *testing.T) {
+	ctx := context.Background()
+	rc := redisClient()
+	rs := service.NewRedisStore(rc)
+
+	_, err := rs.LoadRoomTemplate(ctx, "test_template")
+	require.ErrorIs(t, err, service.ErrRoomTemplateNotFound)
+
+	template := &service.RoomTemplate{
+		ID:              "test_template",
+		EmptyTimeout:    30,
+		MaxParticipants: 10,
+		EnabledCodecs:   []config.CodecSpec{{Mime: "video/vp8"}},
+	}
+	require.NoError(t, rs.StoreRoomTemplate(ctx, template))
+	actual, err := rs.LoadRoomTemplate(ctx, "test_template")
+	require.NoError(t, err)
+	require.Equal(t, template, actual)
+
+	// clean up
+	require.NoError(t, rc.HDel(ctx, service.RoomTemplateKey, template.ID).Err())
+}
+
+func TestNodeReservationStore(t *testing.T) {
+	ctx := context.Background()
+	rs := service.NewRedisStore(redisClient())
+
+	reservations, err := rs.LoadNodeReservations(ctx, []livekit.NodeID{"node-a", "node-b"})
+	require.NoError(t, err)
+	require.Empty(t, reservations)
+
+	require.NoError(t, rs.ReserveNodeRooms(ctx, "node-a", 2, time.Minute))
+	require.NoError(t, rs.ReserveNodeRooms(ctx, "node-a", 1, time.Minute))
+	reservations, err = rs.LoadNodeReservations(ctx, []livekit.NodeID{"node-a", "node-b"})
+	require.NoError(t, err)
+	require.Equal(t, map[livekit.NodeID]int{"node-a": 3}, reservations)
+
+	for i := 0; i < 4; i++ {
+		require.NoError(t, rs.ReleaseNodeReservation(ctx, "node-a"))
+	}
+	reservations, err = rs.LoadNodeReservations(ctx, []livekit.NodeID{"node-a"})
+	require.NoError(t, err)
+	require.Empty(t, reservations)
+}
+
 func TestParticipantPersistence(t *testing.T) {
 	ctx := context.Background()
 	rs := service.NewRedisStore(redisClient())
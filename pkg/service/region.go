@@ -0,0 +1,49 @@
+// Copyright 2024 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"net/http"
+)
+
+const regionHeader = "X-LiveKit-Region"
+
+type regionHintKey struct{}
+
+// region hint middleware, carries the region requested by the client in the request context
+type RegionHintMiddleware struct{}
+
+func NewRegionHintMiddleware() *RegionHintMiddleware {
+	return &RegionHintMiddleware{}
+}
+
+func (m *RegionHintMiddleware) ServeHTTP(w http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
+	if region := r.Header.Get(regionHeader); region != "" {
+		r = r.WithContext(WithRegionHint(r.Context(), region))
+	}
+
+	next.ServeHTTP(w, r)
+}
+
+// WithRegionHint sets the region room allocation should prefer
+func WithRegionHint(ctx context.Context, region string) context.Context {
+	return context.WithValue(ctx, regionHintKey{}, region)
+}
+
+func GetRegionHint(ctx context.Context) string {
+	region, _ := ctx.Value(regionHintKey{}).(string)
+	return region
+}
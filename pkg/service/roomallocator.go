@@ -17,8 +17,12 @@ package service
 import (
 	"context"
 	"errors"
+	"sync"
 	"time"
 
+	"github.com/jellydator/ttlcache/v3"
+	"google.golang.org/protobuf/proto"
+
 	"github.com/livekit/protocol/livekit"
 	"github.com/livekit/protocol/logger"
 	"github.com/livekit/protocol/utils"
@@ -27,6 +31,16 @@ import (
 	"github.com/livekit/livekit-server/pkg/config"
 	"github.com/livekit/livekit-server/pkg/routing"
 	"github.com/livekit/livekit-server/pkg/routing/selector"
+	sutils "github.com/livekit/livekit-server/pkg/utils"
+)
+
+const (
+	stickyAllocationTTL      = 10 * time.Minute
+	stickyAllocationCapacity = 10000
+
+	nodeReservationTTL = 30 * time.Second
+
+	createRoomQueueMinSize = 64
 )
 
 type StandardRoomAllocator struct {
@@ -34,6 +48,15 @@ type StandardRoomAllocator struct {
 	router    routing.Router
 	selector  selector.NodeSelector
 	roomStore ObjectStore
+	audit     AuditLogger
+
+	sticky    bool
+	lastNodes *ttlcache.Cache[livekit.RoomName, livekit.NodeID]
+
+	blacklistLock sync.RWMutex
+	blacklist     map[livekit.NodeID]string
+
+	createRoomQueue *sutils.OpsQueue
 }
 
 func NewRoomAllocator(conf *config.Config, router routing.Router, rs ObjectStore) (RoomAllocator, error) {
@@ -42,12 +65,32 @@ func NewRoomAllocator(conf *config.Config, router routing.Router, rs ObjectStore
 		return nil, err
 	}
 
-	return &StandardRoomAllocator{
+	r := &StandardRoomAllocator{
 		config:    conf,
 		router:    router,
 		selector:  ns,
 		roomStore: rs,
-	}, nil
+		sticky:    conf.Room.StickyAllocation,
+		blacklist: make(map[livekit.NodeID]string),
+
+		createRoomQueue: sutils.NewOpsQueue(sutils.OpsQueueParams{
+			Name:    "create-room",
+			MinSize: createRoomQueueMinSize,
+			Workers: max(conf.Room.CreateRoomWorkers, 1),
+			Logger:  logger.GetLogger(),
+		}),
+	}
+	r.createRoomQueue.Start()
+	if conf.Room.AuditLogPath != "" {
+		r.audit = NewFileAuditLogger(conf.Room.AuditLogPath)
+	}
+	if r.sticky {
+		r.lastNodes = ttlcache.New(
+			ttlcache.WithTTL[livekit.RoomName, livekit.NodeID](stickyAllocationTTL),
+			ttlcache.WithCapacity[livekit.RoomName, livekit.NodeID](stickyAllocationCapacity),
+		)
+	}
+	return r, nil
 }
 
 // CreateRoom creates a new room from a request and allocates it to a node to handle
@@ -74,6 +117,18 @@ func (r *StandardRoomAllocator) CreateRoom(ctx context.Context, req *livekit.Cre
 		}
 		internal = &livekit.RoomInternal{}
 		applyDefaultRoomConfig(rm, internal, &r.config.Room)
+
+		templateID := GetRoomTemplateID(req)
+		if templateID == "" {
+			templateID = r.config.Room.DefaultTemplate
+		}
+		if templateID != "" {
+			template, err := r.roomStore.LoadRoomTemplate(ctx, templateID)
+			if err != nil {
+				return nil, false, err
+			}
+			applyRoomTemplate(rm, internal, template)
+		}
 	} else if err != nil {
 		return nil, false, err
 	}
@@ -123,23 +178,48 @@ func (r *StandardRoomAllocator) CreateRoom(ctx context.Context, req *livekit.Cre
 	if err == nil && selector.IsAvailable(existing) {
 		// if node hosting the room is full, deny entry
 		if selector.LimitsReached(r.config.Limit, existing.Stats) {
-			return nil, false, routing.ErrNodeLimitReached
+			return nil, false, ErrNodeCapacityExceeded
 		}
 
+		r.setLastNode(livekit.RoomName(rm.Name), livekit.NodeID(existing.Id))
+		if created && r.audit != nil {
+			r.audit.LogRoomCreated(ctx, rm, livekit.NodeID(existing.Id))
+		}
 		return rm, created, nil
 	}
 
 	// select a new node
 	nodeID := livekit.NodeID(req.NodeId)
+	if nodeID != "" && r.isBlacklisted(nodeID) {
+		logger.Infow("ignoring blacklisted node hint", "room", rm.Name, "nodeID", nodeID)
+		nodeID = ""
+	}
+	// hinted node is held to the same limits, so that callers can retry with another hint
+	if nodeID != "" && r.config.Limit.ValidateNodeCapacity {
+		node, err := r.getNode(nodeID)
+		if err != nil && !errors.Is(err, routing.ErrNodeNotFound) {
+			return nil, false, err
+		}
+		if node != nil && selector.LimitsReached(r.config.Limit, node.Stats) {
+			return nil, false, ErrNodeCapacityExceeded
+		}
+	}
 	if nodeID == "" {
 		nodes, err := r.router.ListNodes()
 		if err != nil {
 			return nil, false, err
 		}
+		nodes = r.applyReservations(ctx, r.filterBlacklisted(nodes))
 
-		node, err := r.selector.SelectNode(nodes)
-		if err != nil {
-			return nil, false, err
+		node := r.getLastNode(livekit.RoomName(rm.Name), nodes)
+		if node == nil {
+			node = r.selectNodeInRegion(GetRegionHint(ctx), nodes)
+		}
+		if node == nil {
+			node, err = r.selector.SelectNode(nodes)
+			if err != nil {
+				return nil, false, err
+			}
 		}
 
 		nodeID = livekit.NodeID(node.Id)
@@ -150,10 +230,80 @@ func (r *StandardRoomAllocator) CreateRoom(ctx context.Context, req *livekit.Cre
 	if err != nil {
 		return nil, false, err
 	}
+	r.setLastNode(livekit.RoomName(rm.Name), nodeID)
+	r.consumeReservation(ctx, nodeID)
+
+	if created && r.audit != nil {
+		r.audit.LogRoomCreated(ctx, rm, nodeID)
+	}
 
 	return rm, true, nil
 }
 
+// CreateRoomAsync creates a room like CreateRoom without blocking the caller, fn is called with the result on completion.
+// Requests are processed by a pool of workers, requests for the same room are serialized by the room lock.
+// The context should outlive the request, as it is used for store and routing operations.
+func (r *StandardRoomAllocator) CreateRoomAsync(ctx context.Context, req *livekit.CreateRoomRequest, fn func(*livekit.Room, error)) {
+	r.createRoomQueue.Enqueue(func() {
+		rm, _, err := r.CreateRoom(ctx, req)
+		if fn != nil {
+			fn(rm, err)
+		}
+	})
+}
+
+// SetAuditLogger replaces the audit logger, nil disables audit logging
+func (r *StandardRoomAllocator) SetAuditLogger(audit AuditLogger) {
+	r.audit = audit
+}
+
+// selectNodeInRegion selects among nodes of the requested region, nil when none can be selected
+func (r *StandardRoomAllocator) selectNodeInRegion(region string, nodes []*livekit.Node) *livekit.Node {
+	if region == "" {
+		return nil
+	}
+
+	regionNodes := selector.GetNodesInRegion(nodes, region)
+	if len(regionNodes) == 0 {
+		return nil
+	}
+
+	node, err := r.selector.SelectNode(regionNodes)
+	if err != nil {
+		return nil
+	}
+	return node
+}
+
+func (r *StandardRoomAllocator) setLastNode(roomName livekit.RoomName, nodeID livekit.NodeID) {
+	if r.sticky {
+		r.lastNodes.Set(roomName, nodeID, ttlcache.DefaultTTL)
+	}
+}
+
+// getLastNode returns the node the room was previously allocated to if it is still usable
+func (r *StandardRoomAllocator) getLastNode(roomName livekit.RoomName, nodes []*livekit.Node) *livekit.Node {
+	if !r.sticky {
+		return nil
+	}
+
+	item := r.lastNodes.Get(roomName)
+	if item == nil {
+		return nil
+	}
+
+	for _, node := range nodes {
+		if livekit.NodeID(node.Id) != item.Value() {
+			continue
+		}
+		if selector.IsAvailable(node) && !selector.LimitsReached(r.config.Limit, node.Stats) {
+			return node
+		}
+		break
+	}
+	return nil
+}
+
 func (r *StandardRoomAllocator) ValidateCreateRoom(ctx context.Context, roomName livekit.RoomName) error {
 	// when auto create is disabled, we'll check to ensure it's already created
 	if !r.config.Room.AutoCreate {
@@ -165,6 +315,199 @@ func (r *StandardRoomAllocator) ValidateCreateRoom(ctx context.Context, roomName
 	return nil
 }
 
+// RebalanceRoom moves a room to a different node, participants are signalled to reconnect
+func (r *StandardRoomAllocator) RebalanceRoom(ctx context.Context, roomName livekit.RoomName) error {
+	token, err := r.roomStore.LockRoom(ctx, roomName, 5*time.Second)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = r.roomStore.UnlockRoom(ctx, roomName, token)
+	}()
+
+	current, err := r.router.GetNodeForRoom(ctx, roomName)
+	if err != nil {
+		return err
+	}
+
+	nodes, err := r.router.ListNodes()
+	if err != nil {
+		return err
+	}
+	candidates := make([]*livekit.Node, 0, len(nodes))
+	for _, node := range r.filterBlacklisted(nodes) {
+		if node.Id != current.Id {
+			candidates = append(candidates, node)
+		}
+	}
+
+	node, err := r.selector.SelectNode(candidates)
+	if err != nil {
+		return err
+	}
+
+	nodeID := livekit.NodeID(node.Id)
+	logger.Infow("rebalancing room", "room", roomName, "previousNodeID", current.Id, "selectedNodeID", nodeID)
+	if err = r.router.SetNodeForRoom(ctx, roomName, nodeID); err != nil {
+		return err
+	}
+
+	return r.router.NotifyRoomMigration(ctx, livekit.NodeID(current.Id), roomName, nodeID)
+}
+
+// GetAllocationStats returns the node each active room is allocated to,
+// rooms without an allocation have an empty NodeID
+func (r *StandardRoomAllocator) GetAllocationStats(ctx context.Context) ([]RoomAllocationStat, error) {
+	rooms, err := r.roomStore.ListRooms(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := make([]RoomAllocationStat, 0, len(rooms))
+	for _, rm := range rooms {
+		stat := RoomAllocationStat{
+			RoomName:         livekit.RoomName(rm.Name),
+			RoomID:           livekit.RoomID(rm.Sid),
+			ParticipantCount: rm.NumParticipants,
+		}
+
+		node, err := r.router.GetNodeForRoom(ctx, livekit.RoomName(rm.Name))
+		switch {
+		case err == nil:
+			stat.NodeID = livekit.NodeID(node.Id)
+		case !errors.Is(err, routing.ErrNotFound):
+			return nil, err
+		}
+
+		stats = append(stats, stat)
+	}
+	return stats, nil
+}
+
+// BlacklistNode prevents new rooms from being allocated to a node, rooms already on the node are not affected
+func (r *StandardRoomAllocator) BlacklistNode(_ context.Context, nodeID livekit.NodeID, reason string) error {
+	r.blacklistLock.Lock()
+	r.blacklist[nodeID] = reason
+	r.blacklistLock.Unlock()
+
+	logger.Infow("blacklisted node", "nodeID", nodeID, "reason", reason)
+	return nil
+}
+
+func (r *StandardRoomAllocator) UnblacklistNode(_ context.Context, nodeID livekit.NodeID) error {
+	r.blacklistLock.Lock()
+	delete(r.blacklist, nodeID)
+	r.blacklistLock.Unlock()
+
+	logger.Infow("unblacklisted node", "nodeID", nodeID)
+	return nil
+}
+
+func (r *StandardRoomAllocator) isBlacklisted(nodeID livekit.NodeID) bool {
+	r.blacklistLock.RLock()
+	defer r.blacklistLock.RUnlock()
+
+	_, ok := r.blacklist[nodeID]
+	return ok
+}
+
+func (r *StandardRoomAllocator) filterBlacklisted(nodes []*livekit.Node) []*livekit.Node {
+	r.blacklistLock.RLock()
+	defer r.blacklistLock.RUnlock()
+
+	if len(r.blacklist) == 0 {
+		return nodes
+	}
+
+	filtered := make([]*livekit.Node, 0, len(nodes))
+	for _, node := range nodes {
+		if _, ok := r.blacklist[livekit.NodeID(node.Id)]; !ok {
+			filtered = append(filtered, node)
+		}
+	}
+	return filtered
+}
+
+// PreWarmNode reserves capacity for a number of rooms on a node, for nodeReservationTTL.
+// Reservations are kept in the room store, so they are shared by all nodes allocating rooms.
+// While the reservation is held, the node is reported to selectors as hosting the reserved rooms,
+// steering concurrent allocations elsewhere. Rooms allocated to the node consume the reservation.
+// The reservation is rejected if the node is unavailable, blacklisted or has reached its limits.
+func (r *StandardRoomAllocator) PreWarmNode(ctx context.Context, nodeID livekit.NodeID, rooms int) error {
+	if rooms <= 0 {
+		return ErrInvalidReservation
+	}
+
+	node, err := r.getNode(nodeID)
+	if err != nil {
+		return err
+	}
+	if r.isBlacklisted(nodeID) || !selector.IsAvailable(node) || selector.LimitsReached(r.config.Limit, node.Stats) {
+		return ErrNodeCapacityExceeded
+	}
+
+	if err = r.roomStore.ReserveNodeRooms(ctx, nodeID, rooms, nodeReservationTTL); err != nil {
+		return err
+	}
+
+	logger.Infow("reserved node capacity", "nodeID", nodeID, "rooms", rooms, "ttl", nodeReservationTTL)
+	return nil
+}
+
+func (r *StandardRoomAllocator) getNode(nodeID livekit.NodeID) (*livekit.Node, error) {
+	nodes, err := r.router.ListNodes()
+	if err != nil {
+		return nil, err
+	}
+	for _, node := range nodes {
+		if livekit.NodeID(node.Id) == nodeID {
+			return node, nil
+		}
+	}
+	return nil, routing.ErrNodeNotFound
+}
+
+// applyReservations returns nodes with reserved rooms added to their reported number of rooms
+func (r *StandardRoomAllocator) applyReservations(ctx context.Context, nodes []*livekit.Node) []*livekit.Node {
+	nodeIDs := make([]livekit.NodeID, 0, len(nodes))
+	for _, node := range nodes {
+		nodeIDs = append(nodeIDs, livekit.NodeID(node.Id))
+	}
+
+	reservations, err := r.roomStore.LoadNodeReservations(ctx, nodeIDs)
+	if err != nil {
+		logger.Warnw("could not load node reservations", err)
+		return nodes
+	}
+	if len(reservations) == 0 {
+		return nodes
+	}
+
+	applied := make([]*livekit.Node, 0, len(nodes))
+	for _, node := range nodes {
+		reserved := reservations[livekit.NodeID(node.Id)]
+		if reserved <= 0 {
+			applied = append(applied, node)
+			continue
+		}
+
+		node = proto.Clone(node).(*livekit.Node)
+		if node.Stats == nil {
+			node.Stats = &livekit.NodeStats{}
+		}
+		node.Stats.NumRooms += int32(reserved)
+		applied = append(applied, node)
+	}
+	return applied
+}
+
+// consumeReservation releases one reserved room on the node, if any
+func (r *StandardRoomAllocator) consumeReservation(ctx context.Context, nodeID livekit.NodeID) {
+	if err := r.roomStore.ReleaseNodeReservation(ctx, nodeID); err != nil {
+		logger.Warnw("could not release node reservation", err, "nodeID", nodeID)
+	}
+}
+
 func applyDefaultRoomConfig(room *livekit.Room, internal *livekit.RoomInternal, conf *config.RoomConfig) {
 	room.EmptyTimeout = conf.EmptyTimeout
 	room.DepartureTimeout = conf.DepartureTimeout
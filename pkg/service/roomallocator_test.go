@@ -16,10 +16,15 @@ package service_test
 
 import (
 	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
 
+	"github.com/livekit/protocol/auth"
 	"github.com/livekit/protocol/livekit"
 
 	"github.com/livekit/livekit-server/pkg/config"
@@ -46,6 +51,71 @@ func TestCreateRoom(t *testing.T) {
 		require.NotEmpty(t, room.EnabledCodecs)
 	})
 
+	t.Run("room template defaults are applied before request settings", func(t *testing.T) {
+		conf, err := config.NewConfig("", true, nil, nil)
+		require.NoError(t, err)
+
+		node, err := routing.NewLocalNode(conf)
+		require.NoError(t, err)
+
+		store := &servicefakes.FakeObjectStore{}
+		store.LoadRoomReturns(nil, nil, service.ErrRoomNotFound)
+		store.LoadRoomTemplateReturns(&service.RoomTemplate{
+			ID:              "webinar",
+			EmptyTimeout:    30,
+			MaxParticipants: 500,
+			EnabledCodecs:   []config.CodecSpec{{Mime: "video/vp8"}},
+			MinPlayoutDelay: 100,
+			MaxPlayoutDelay: 200,
+		}, nil)
+		router := &routingfakes.FakeRouter{}
+		router.GetNodeForRoomReturns(node, nil)
+
+		ra, err := service.NewRoomAllocator(conf, router, store)
+		require.NoError(t, err)
+
+		req := &livekit.CreateRoomRequest{Name: "myroom", MaxParticipants: 50}
+		service.SetRoomTemplateID(req, "webinar")
+		room, _, err := ra.CreateRoom(context.Background(), req)
+		require.NoError(t, err)
+		_, templateID := store.LoadRoomTemplateArgsForCall(0)
+		require.Equal(t, "webinar", templateID)
+		require.EqualValues(t, 30, room.EmptyTimeout)
+		require.Equal(t, conf.Room.DepartureTimeout, room.DepartureTimeout)
+		require.EqualValues(t, 50, room.MaxParticipants)
+		require.Len(t, room.EnabledCodecs, 1)
+		require.Equal(t, "video/vp8", room.EnabledCodecs[0].Mime)
+		_, _, internal := store.StoreRoomArgsForCall(0)
+		require.EqualValues(t, 100, internal.PlayoutDelay.Min)
+		require.EqualValues(t, 200, internal.PlayoutDelay.Max)
+
+		store.LoadRoomTemplateReturns(nil, service.ErrRoomTemplateNotFound)
+		req = &livekit.CreateRoomRequest{Name: "myroom"}
+		service.SetRoomTemplateID(req, "webinar")
+		_, _, err = ra.CreateRoom(context.Background(), req)
+		require.ErrorIs(t, err, service.ErrRoomTemplateNotFound)
+
+		// rooms created without a template use the configured default
+		conf.Room.DefaultTemplate = "default"
+		_, _, err = ra.CreateRoom(context.Background(), &livekit.CreateRoomRequest{Name: "myroom"})
+		require.ErrorIs(t, err, service.ErrRoomTemplateNotFound)
+		_, templateID = store.LoadRoomTemplateArgsForCall(store.LoadRoomTemplateCallCount() - 1)
+		require.Equal(t, "default", templateID)
+	})
+
+	t.Run("room template id survives request encoding", func(t *testing.T) {
+		req := &livekit.CreateRoomRequest{Name: "myroom", MaxParticipants: 50}
+		require.Empty(t, service.GetRoomTemplateID(req))
+		service.SetRoomTemplateID(req, "webinar")
+
+		data, err := proto.Marshal(req)
+		require.NoError(t, err)
+		decoded := &livekit.CreateRoomRequest{}
+		require.NoError(t, proto.Unmarshal(data, decoded))
+		require.Equal(t, "webinar", service.GetRoomTemplateID(decoded))
+		require.EqualValues(t, 50, decoded.MaxParticipants)
+	})
+
 	t.Run("reject new participants when track limit has been reached", func(t *testing.T) {
 		conf, err := config.NewConfig("", true, nil, nil)
 		require.NoError(t, err)
@@ -90,3 +160,369 @@ func newTestRoomAllocator(t *testing.T, conf *config.Config, node *livekit.Node)
 	require.NoError(t, err)
 	return ra, conf
 }
+
+func TestRebalanceRoom(t *testing.T) {
+	conf, err := config.NewConfig("", true, nil, nil)
+	require.NoError(t, err)
+
+	current, err := routing.NewLocalNode(conf)
+	require.NoError(t, err)
+	other, err := routing.NewLocalNode(conf)
+	require.NoError(t, err)
+	other.Id = "other-node"
+
+	store := &servicefakes.FakeObjectStore{}
+	router := &routingfakes.FakeRouter{}
+	router.GetNodeForRoomReturns(current, nil)
+	router.ListNodesReturns([]*livekit.Node{current, other}, nil)
+
+	ra, err := service.NewRoomAllocator(conf, router, store)
+	require.NoError(t, err)
+
+	require.NoError(t, ra.RebalanceRoom(context.Background(), "myroom"))
+	require.Equal(t, 1, router.SetNodeForRoomCallCount())
+	_, roomName, nodeID := router.SetNodeForRoomArgsForCall(0)
+	require.Equal(t, livekit.RoomName("myroom"), roomName)
+	require.Equal(t, livekit.NodeID("other-node"), nodeID)
+
+	// node previously hosting the room is notified through the router
+	require.Equal(t, 1, router.NotifyRoomMigrationCallCount())
+	_, prevNodeID, roomName, nodeID := router.NotifyRoomMigrationArgsForCall(0)
+	require.Equal(t, livekit.NodeID(current.Id), prevNodeID)
+	require.Equal(t, livekit.RoomName("myroom"), roomName)
+	require.Equal(t, livekit.NodeID("other-node"), nodeID)
+
+	// no other node to move to
+	router.ListNodesReturns([]*livekit.Node{current}, nil)
+	require.Error(t, ra.RebalanceRoom(context.Background(), "myroom"))
+	require.Equal(t, 1, router.SetNodeForRoomCallCount())
+}
+
+func TestStickyAllocation(t *testing.T) {
+	conf, err := config.NewConfig("", true, nil, nil)
+	require.NoError(t, err)
+	conf.Room.StickyAllocation = true
+
+	nodeA, err := routing.NewLocalNode(conf)
+	require.NoError(t, err)
+	nodeA.Id = "node-a"
+	nodeB, err := routing.NewLocalNode(conf)
+	require.NoError(t, err)
+	nodeB.Id = "node-b"
+
+	store := &servicefakes.FakeObjectStore{}
+	store.LoadRoomReturns(nil, nil, service.ErrRoomNotFound)
+	router := &routingfakes.FakeRouter{}
+	router.GetNodeForRoomReturns(nil, routing.ErrNotFound)
+	router.ListNodesReturns([]*livekit.Node{nodeA, nodeB}, nil)
+
+	ra, err := service.NewRoomAllocator(conf, router, store)
+	require.NoError(t, err)
+
+	_, _, err = ra.CreateRoom(context.Background(), &livekit.CreateRoomRequest{Name: "myroom", NodeId: "node-a"})
+	require.NoError(t, err)
+
+	// re-created room goes back to the previous node
+	for i := 0; i < 5; i++ {
+		_, _, err = ra.CreateRoom(context.Background(), &livekit.CreateRoomRequest{Name: "myroom"})
+		require.NoError(t, err)
+		_, _, nodeID := router.SetNodeForRoomArgsForCall(router.SetNodeForRoomCallCount() - 1)
+		require.Equal(t, livekit.NodeID("node-a"), nodeID)
+	}
+
+	// previous node is gone, falls back to normal selection
+	router.ListNodesReturns([]*livekit.Node{nodeB}, nil)
+	_, _, err = ra.CreateRoom(context.Background(), &livekit.CreateRoomRequest{Name: "myroom"})
+	require.NoError(t, err)
+	_, _, nodeID := router.SetNodeForRoomArgsForCall(router.SetNodeForRoomCallCount() - 1)
+	require.Equal(t, livekit.NodeID("node-b"), nodeID)
+}
+
+func TestValidateNodeCapacity(t *testing.T) {
+	conf, err := config.NewConfig("", true, nil, nil)
+	require.NoError(t, err)
+	conf.Limit.ValidateNodeCapacity = true
+	conf.Limit.NumTracks = 10
+
+	node, err := routing.NewLocalNode(conf)
+	require.NoError(t, err)
+	node.Id = "full-node"
+	node.Stats.NumTracksIn = 100
+
+	t.Run("room node over capacity", func(t *testing.T) {
+		store := &servicefakes.FakeObjectStore{}
+		store.LoadRoomReturns(nil, nil, service.ErrRoomNotFound)
+		router := &routingfakes.FakeRouter{}
+		router.GetNodeForRoomReturns(node, nil)
+
+		ra, err := service.NewRoomAllocator(conf, router, store)
+		require.NoError(t, err)
+
+		_, _, err = ra.CreateRoom(context.Background(), &livekit.CreateRoomRequest{Name: "myroom"})
+		require.ErrorIs(t, err, service.ErrNodeCapacityExceeded)
+		require.ErrorIs(t, err, routing.ErrNodeLimitReached)
+	})
+
+	t.Run("hinted node over capacity", func(t *testing.T) {
+		store := &servicefakes.FakeObjectStore{}
+		store.LoadRoomReturns(nil, nil, service.ErrRoomNotFound)
+		router := &routingfakes.FakeRouter{}
+		router.GetNodeForRoomReturns(nil, routing.ErrNotFound)
+		router.ListNodesReturns([]*livekit.Node{node}, nil)
+
+		ra, err := service.NewRoomAllocator(conf, router, store)
+		require.NoError(t, err)
+
+		_, _, err = ra.CreateRoom(context.Background(), &livekit.CreateRoomRequest{Name: "myroom", NodeId: "full-node"})
+		require.ErrorIs(t, err, service.ErrNodeCapacityExceeded)
+
+		_, _, err = ra.CreateRoom(context.Background(), &livekit.CreateRoomRequest{Name: "myroom", NodeId: "other-node"})
+		require.NoError(t, err)
+	})
+}
+
+func TestGetAllocationStats(t *testing.T) {
+	conf, err := config.NewConfig("", true, nil, nil)
+	require.NoError(t, err)
+
+	node, err := routing.NewLocalNode(conf)
+	require.NoError(t, err)
+
+	store := &servicefakes.FakeObjectStore{}
+	store.ListRoomsReturns([]*livekit.Room{
+		{Name: "allocated", Sid: "RM_allocated", NumParticipants: 3},
+		{Name: "unallocated", Sid: "RM_unallocated"},
+	}, nil)
+	router := &routingfakes.FakeRouter{}
+	router.GetNodeForRoomCalls(func(_ context.Context, roomName livekit.RoomName) (*livekit.Node, error) {
+		if roomName == "allocated" {
+			return node, nil
+		}
+		return nil, routing.ErrNotFound
+	})
+
+	ra, err := service.NewRoomAllocator(conf, router, store)
+	require.NoError(t, err)
+
+	stats, err := ra.GetAllocationStats(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, []service.RoomAllocationStat{
+		{RoomName: "allocated", RoomID: "RM_allocated", NodeID: livekit.NodeID(node.Id), ParticipantCount: 3},
+		{RoomName: "unallocated", RoomID: "RM_unallocated"},
+	}, stats)
+}
+
+func TestRegionHint(t *testing.T) {
+	conf, err := config.NewConfig("", true, nil, nil)
+	require.NoError(t, err)
+
+	nodeA, err := routing.NewLocalNode(conf)
+	require.NoError(t, err)
+	nodeA.Id = "node-a"
+	nodeA.Region = "us-east"
+	nodeB, err := routing.NewLocalNode(conf)
+	require.NoError(t, err)
+	nodeB.Id = "node-b"
+	nodeB.Region = "eu-west"
+
+	store := &servicefakes.FakeObjectStore{}
+	store.LoadRoomReturns(nil, nil, service.ErrRoomNotFound)
+	router := &routingfakes.FakeRouter{}
+	router.GetNodeForRoomReturns(nil, routing.ErrNotFound)
+	router.ListNodesReturns([]*livekit.Node{nodeA, nodeB}, nil)
+
+	ra, err := service.NewRoomAllocator(conf, router, store)
+	require.NoError(t, err)
+
+	ctx := service.WithRegionHint(context.Background(), "eu-west")
+	for i := 0; i < 5; i++ {
+		_, _, err = ra.CreateRoom(ctx, &livekit.CreateRoomRequest{Name: "myroom"})
+		require.NoError(t, err)
+		_, _, nodeID := router.SetNodeForRoomArgsForCall(router.SetNodeForRoomCallCount() - 1)
+		require.Equal(t, livekit.NodeID("node-b"), nodeID)
+	}
+
+	// no node in requested region, falls back to global selection
+	ctx = service.WithRegionHint(context.Background(), "ap-south")
+	_, _, err = ra.CreateRoom(ctx, &livekit.CreateRoomRequest{Name: "myroom"})
+	require.NoError(t, err)
+}
+
+func TestBlacklistNode(t *testing.T) {
+	conf, err := config.NewConfig("", true, nil, nil)
+	require.NoError(t, err)
+
+	nodeA, err := routing.NewLocalNode(conf)
+	require.NoError(t, err)
+	nodeA.Id = "node-a"
+	nodeB, err := routing.NewLocalNode(conf)
+	require.NoError(t, err)
+	nodeB.Id = "node-b"
+
+	store := &servicefakes.FakeObjectStore{}
+	store.LoadRoomReturns(nil, nil, service.ErrRoomNotFound)
+	router := &routingfakes.FakeRouter{}
+	router.GetNodeForRoomReturns(nil, routing.ErrNotFound)
+	router.ListNodesReturns([]*livekit.Node{nodeA, nodeB}, nil)
+
+	ra, err := service.NewRoomAllocator(conf, router, store)
+	require.NoError(t, err)
+	require.NoError(t, ra.BlacklistNode(context.Background(), "node-a", "maintenance"))
+
+	for i := 0; i < 5; i++ {
+		_, _, err = ra.CreateRoom(context.Background(), &livekit.CreateRoomRequest{Name: "myroom"})
+		require.NoError(t, err)
+		_, _, nodeID := router.SetNodeForRoomArgsForCall(router.SetNodeForRoomCallCount() - 1)
+		require.Equal(t, livekit.NodeID("node-b"), nodeID)
+	}
+
+	// hint to a blacklisted node is ignored
+	_, _, err = ra.CreateRoom(context.Background(), &livekit.CreateRoomRequest{Name: "myroom", NodeId: "node-a"})
+	require.NoError(t, err)
+	_, _, nodeID := router.SetNodeForRoomArgsForCall(router.SetNodeForRoomCallCount() - 1)
+	require.Equal(t, livekit.NodeID("node-b"), nodeID)
+
+	// existing rooms stay on a blacklisted node
+	router.GetNodeForRoomReturns(nodeA, nil)
+	numCalls := router.SetNodeForRoomCallCount()
+	_, _, err = ra.CreateRoom(context.Background(), &livekit.CreateRoomRequest{Name: "myroom"})
+	require.NoError(t, err)
+	require.Equal(t, numCalls, router.SetNodeForRoomCallCount())
+
+	require.NoError(t, ra.UnblacklistNode(context.Background(), "node-a"))
+	router.GetNodeForRoomReturns(nil, routing.ErrNotFound)
+	_, _, err = ra.CreateRoom(context.Background(), &livekit.CreateRoomRequest{Name: "myroom", NodeId: "node-a"})
+	require.NoError(t, err)
+	_, _, nodeID = router.SetNodeForRoomArgsForCall(router.SetNodeForRoomCallCount() - 1)
+	require.Equal(t, livekit.NodeID("node-a"), nodeID)
+}
+
+func TestPreWarmNode(t *testing.T) {
+	conf, err := config.NewConfig("", true, nil, nil)
+	require.NoError(t, err)
+	conf.NodeSelector.SortBy = "rooms"
+	conf.Limit.NumTracks = 10
+
+	nodeA, err := routing.NewLocalNode(conf)
+	require.NoError(t, err)
+	nodeA.Id = "node-a"
+	nodeA.Stats.NumRooms = 1
+	nodeB, err := routing.NewLocalNode(conf)
+	require.NoError(t, err)
+	nodeB.Id = "node-b"
+	nodeB.Stats.NumRooms = 2
+	nodeC, err := routing.NewLocalNode(conf)
+	require.NoError(t, err)
+	nodeC.Id = "node-c"
+	nodeC.Stats.NumTracksIn = 10
+	nodeC.Stats.NumRooms = 5
+
+	// reservations are shared through the store
+	store := service.NewLocalStore()
+	router := &routingfakes.FakeRouter{}
+	router.GetNodeForRoomReturns(nil, routing.ErrNotFound)
+	router.ListNodesReturns([]*livekit.Node{nodeA, nodeB, nodeC}, nil)
+
+	ra, err := service.NewRoomAllocator(conf, router, store)
+	require.NoError(t, err)
+
+	createRoom := func(hint livekit.NodeID) livekit.NodeID {
+		_, _, err := ra.CreateRoom(context.Background(), &livekit.CreateRoomRequest{Name: "myroom", NodeId: string(hint)})
+		require.NoError(t, err)
+		_, _, nodeID := router.SetNodeForRoomArgsForCall(router.SetNodeForRoomCallCount() - 1)
+		return nodeID
+	}
+
+	require.Equal(t, livekit.NodeID("node-a"), createRoom(""))
+
+	require.ErrorIs(t, ra.PreWarmNode(context.Background(), "node-a", 0), service.ErrInvalidReservation)
+	require.ErrorIs(t, ra.PreWarmNode(context.Background(), "unknown", 1), routing.ErrNodeNotFound)
+	require.ErrorIs(t, ra.PreWarmNode(context.Background(), "node-c", 1), service.ErrNodeCapacityExceeded)
+
+	// reserved rooms are counted against node-a
+	require.NoError(t, ra.PreWarmNode(context.Background(), "node-a", 2))
+	require.Equal(t, livekit.NodeID("node-b"), createRoom(""))
+	require.EqualValues(t, 1, nodeA.Stats.NumRooms)
+
+	// rooms allocated to node-a consume the reservation
+	require.Equal(t, livekit.NodeID("node-a"), createRoom("node-a"))
+	require.Equal(t, livekit.NodeID("node-a"), createRoom("node-a"))
+	require.Equal(t, livekit.NodeID("node-a"), createRoom(""))
+
+	// reservations made by another allocator are honored
+	other, err := service.NewRoomAllocator(conf, router, store)
+	require.NoError(t, err)
+	require.NoError(t, other.PreWarmNode(context.Background(), "node-a", 2))
+	require.Equal(t, livekit.NodeID("node-b"), createRoom(""))
+}
+
+func TestCreateRoomAsync(t *testing.T) {
+	conf, err := config.NewConfig("", true, nil, nil)
+	require.NoError(t, err)
+
+	node, err := routing.NewLocalNode(conf)
+	require.NoError(t, err)
+
+	store := &servicefakes.FakeObjectStore{}
+	store.LoadRoomReturns(nil, nil, service.ErrRoomNotFound)
+	router := &routingfakes.FakeRouter{}
+	router.GetNodeForRoomReturns(nil, routing.ErrNotFound)
+	router.ListNodesReturns([]*livekit.Node{node}, nil)
+
+	ra, err := service.NewRoomAllocator(conf, router, store)
+	require.NoError(t, err)
+
+	type result struct {
+		room *livekit.Room
+		err  error
+	}
+	results := make(chan result, 1)
+	onComplete := func(room *livekit.Room, err error) {
+		results <- result{room, err}
+	}
+
+	ra.(*service.StandardRoomAllocator).CreateRoomAsync(context.Background(), &livekit.CreateRoomRequest{Name: "myroom"}, onComplete)
+	res := <-results
+	require.NoError(t, res.err)
+	require.Equal(t, "myroom", res.room.Name)
+	require.Equal(t, 1, router.SetNodeForRoomCallCount())
+
+	store.LockRoomReturns("", service.ErrRoomLockFailed)
+	ra.(*service.StandardRoomAllocator).CreateRoomAsync(context.Background(), &livekit.CreateRoomRequest{Name: "myroom"}, onComplete)
+	res = <-results
+	require.ErrorIs(t, res.err, service.ErrRoomLockFailed)
+	require.Nil(t, res.room)
+}
+
+func TestAuditLog(t *testing.T) {
+	conf, err := config.NewConfig("", true, nil, nil)
+	require.NoError(t, err)
+	conf.Room.AuditLogPath = filepath.Join(t.TempDir(), "audit.log")
+
+	node, err := routing.NewLocalNode(conf)
+	require.NoError(t, err)
+
+	store := &servicefakes.FakeObjectStore{}
+	store.LoadRoomReturns(nil, nil, service.ErrRoomNotFound)
+	router := &routingfakes.FakeRouter{}
+	router.GetNodeForRoomReturns(nil, routing.ErrNotFound)
+	router.ListNodesReturns([]*livekit.Node{node}, nil)
+
+	ra, err := service.NewRoomAllocator(conf, router, store)
+	require.NoError(t, err)
+
+	ctx := service.WithGrants(context.Background(), &auth.ClaimGrants{Identity: "admin"}, "apikey")
+	rm, _, err := ra.CreateRoom(ctx, &livekit.CreateRoomRequest{Name: "myroom"})
+	require.NoError(t, err)
+
+	data, err := os.ReadFile(conf.Room.AuditLogPath)
+	require.NoError(t, err)
+
+	var record map[string]any
+	require.NoError(t, json.Unmarshal(data, &record))
+	require.Equal(t, "myroom", record["room"])
+	require.Equal(t, rm.Sid, record["roomId"])
+	require.Equal(t, node.Id, record["nodeId"])
+	require.Equal(t, "apikey", record["apiKey"])
+	require.Equal(t, "admin", record["identity"])
+}
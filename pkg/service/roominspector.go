@@ -0,0 +1,138 @@
+// Copyright 2024 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/livekit/livekit-server/pkg/rtc"
+	"github.com/livekit/livekit-server/pkg/rtc/types"
+	"github.com/livekit/protocol/livekit"
+)
+
+type RoomHealthReport struct {
+	RoomName livekit.RoomName `json:"room_name"`
+	Tracks   []*TrackHealth   `json:"tracks"`
+}
+
+type TrackHealth struct {
+	ParticipantIdentity livekit.ParticipantIdentity `json:"participant_identity"`
+	TrackID             livekit.TrackID             `json:"track_id"`
+	Kind                livekit.TrackType           `json:"kind"`
+	IsActive            bool                        `json:"is_active"`
+	// zero if no packets have been received
+	LastPacketTime    time.Time                 `json:"last_packet_time"`
+	ConnectionScore   float32                   `json:"connection_score"`
+	ConnectionQuality livekit.ConnectionQuality `json:"connection_quality"`
+}
+
+// RoomProvider looks up rooms hosted on this node, implemented by RoomManager
+type RoomProvider interface {
+	GetRoom(ctx context.Context, roomName livekit.RoomName) *rtc.Room
+}
+
+// RoomInspector reports health of media flowing in rooms hosted on this node.
+// It is served at /room_health?room=<name> on the node hosting the room, and requires room admin permission.
+type RoomInspector struct {
+	roomProvider RoomProvider
+}
+
+func NewRoomInspector(roomProvider RoomProvider) *RoomInspector {
+	return &RoomInspector{
+		roomProvider: roomProvider,
+	}
+}
+
+// HealthCheck returns the state of all tracks published in the room. The report is accompanied by
+// ErrRoomUnhealthy if an unmuted video track has not received media for longer than the room's empty timeout.
+func (r *RoomInspector) HealthCheck(ctx context.Context, roomName livekit.RoomName) (*RoomHealthReport, error) {
+	room := r.roomProvider.GetRoom(ctx, roomName)
+	if room == nil {
+		return nil, ErrRoomNotFound
+	}
+
+	emptyTimeout := time.Duration(room.ToProto().EmptyTimeout) * time.Second
+	return getRoomHealthReport(roomName, room.GetParticipants(), emptyTimeout)
+}
+
+func (r *RoomInspector) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	roomName := livekit.RoomName(req.FormValue("room"))
+	if err := EnsureAdminPermission(req.Context(), roomName); err != nil {
+		handleError(w, req, http.StatusUnauthorized, err)
+		return
+	}
+
+	report, err := r.HealthCheck(req.Context(), roomName)
+	switch {
+	case errors.Is(err, ErrRoomNotFound):
+		handleError(w, req, http.StatusNotFound, err, "room", roomName)
+		return
+	case errors.Is(err, ErrRoomUnhealthy):
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusServiceUnavailable)
+	case err != nil:
+		handleError(w, req, http.StatusInternalServerError, err, "room", roomName)
+		return
+	default:
+		w.Header().Set("Content-Type", "application/json")
+	}
+	_ = json.NewEncoder(w).Encode(report)
+}
+
+func getRoomHealthReport(
+	roomName livekit.RoomName,
+	participants []types.LocalParticipant,
+	emptyTimeout time.Duration,
+) (*RoomHealthReport, error) {
+	report := &RoomHealthReport{
+		RoomName: roomName,
+	}
+
+	healthy := true
+	for _, p := range participants {
+		for _, pt := range p.GetPublishedTracks() {
+			lmt, ok := pt.(types.LocalMediaTrack)
+			if !ok {
+				continue
+			}
+
+			score, quality := lmt.GetConnectionScoreAndQuality()
+			th := &TrackHealth{
+				ParticipantIdentity: p.Identity(),
+				TrackID:             lmt.ID(),
+				Kind:                lmt.Kind(),
+				IsActive:            lmt.IsActive(),
+				LastPacketTime:      lmt.LastPacketTime(),
+				ConnectionScore:     score,
+				ConnectionQuality:   quality,
+			}
+			report.Tracks = append(report.Tracks, th)
+
+			// muted tracks are not expected to flow
+			if emptyTimeout > 0 && th.Kind == livekit.TrackType_VIDEO && !lmt.IsMuted() && lmt.GetInactiveDuration() > emptyTimeout {
+				healthy = false
+			}
+		}
+	}
+
+	if !healthy {
+		return report, ErrRoomUnhealthy
+	}
+	return report, nil
+}
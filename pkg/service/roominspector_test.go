@@ -0,0 +1,168 @@
+// Copyright 2024 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/livekit/protocol/auth"
+	"github.com/livekit/protocol/livekit"
+
+	"github.com/livekit/livekit-server/pkg/config"
+	"github.com/livekit/livekit-server/pkg/rtc"
+	"github.com/livekit/livekit-server/pkg/rtc/types"
+	"github.com/livekit/livekit-server/pkg/rtc/types/typesfakes"
+	"github.com/livekit/livekit-server/pkg/service"
+)
+
+type testRoomProvider map[livekit.RoomName]*rtc.Room
+
+func (p testRoomProvider) GetRoom(_ context.Context, roomName livekit.RoomName) *rtc.Room {
+	return p[roomName]
+}
+
+func TestRoomInspector_HealthCheck(t *testing.T) {
+	room := rtc.NewRoom(
+		&livekit.Room{Name: "myroom", EmptyTimeout: 10},
+		nil,
+		rtc.WebRTCConfig{},
+		config.RoomConfig{},
+		&config.AudioConfig{},
+		&livekit.ServerInfo{},
+		nil,
+		nil,
+		nil,
+	)
+	defer room.Close(types.ParticipantCloseReasonNone)
+
+	p := rtc.NewMockParticipant("p1", types.CurrentProtocol, false, true)
+	require.NoError(t, room.Join(p, nil, nil, nil))
+
+	video := &typesfakes.FakeLocalMediaTrack{}
+	video.IDReturns("video")
+	video.KindReturns(livekit.TrackType_VIDEO)
+	video.IsActiveReturns(true)
+	lastPacketTime := time.Now()
+	video.LastPacketTimeReturns(lastPacketTime)
+	video.GetInactiveDurationReturns(time.Second)
+	video.GetConnectionScoreAndQualityReturns(4.5, livekit.ConnectionQuality_EXCELLENT)
+
+	audio := &typesfakes.FakeLocalMediaTrack{}
+	audio.IDReturns("audio")
+	audio.KindReturns(livekit.TrackType_AUDIO)
+	audio.GetInactiveDurationReturns(time.Minute)
+	p.GetPublishedTracksReturns([]types.MediaTrack{video, audio})
+
+	inspector := service.NewRoomInspector(testRoomProvider{"myroom": room})
+
+	_, err := inspector.HealthCheck(context.Background(), "unknown")
+	require.ErrorIs(t, err, service.ErrRoomNotFound)
+
+	// inactive audio does not affect health
+	report, err := inspector.HealthCheck(context.Background(), "myroom")
+	require.NoError(t, err)
+	require.Equal(t, livekit.RoomName("myroom"), report.RoomName)
+	require.Equal(t, []*service.TrackHealth{
+		{
+			ParticipantIdentity: "p1",
+			TrackID:             "video",
+			Kind:                livekit.TrackType_VIDEO,
+			IsActive:            true,
+			LastPacketTime:      lastPacketTime,
+			ConnectionScore:     4.5,
+			ConnectionQuality:   livekit.ConnectionQuality_EXCELLENT,
+		},
+		{
+			ParticipantIdentity: "p1",
+			TrackID:             "audio",
+			Kind:                livekit.TrackType_AUDIO,
+		},
+	}, report.Tracks)
+
+	// video inactive for longer than empty timeout
+	video.IsActiveReturns(false)
+	video.GetInactiveDurationReturns(11 * time.Second)
+	report, err = inspector.HealthCheck(context.Background(), "myroom")
+	require.ErrorIs(t, err, service.ErrRoomUnhealthy)
+	require.Len(t, report.Tracks, 2)
+
+	// unless it is muted
+	video.IsMutedReturns(true)
+	_, err = inspector.HealthCheck(context.Background(), "myroom")
+	require.NoError(t, err)
+}
+
+func TestRoomInspector_ServeHTTP(t *testing.T) {
+	room := rtc.NewRoom(
+		&livekit.Room{Name: "myroom", EmptyTimeout: 10},
+		nil,
+		rtc.WebRTCConfig{},
+		config.RoomConfig{},
+		&config.AudioConfig{},
+		&livekit.ServerInfo{},
+		nil,
+		nil,
+		nil,
+	)
+	defer room.Close(types.ParticipantCloseReasonNone)
+
+	p := rtc.NewMockParticipant("p1", types.CurrentProtocol, false, true)
+	require.NoError(t, room.Join(p, nil, nil, nil))
+
+	video := &typesfakes.FakeLocalMediaTrack{}
+	video.IDReturns("video")
+	video.KindReturns(livekit.TrackType_VIDEO)
+	p.GetPublishedTracksReturns([]types.MediaTrack{video})
+
+	inspector := service.NewRoomInspector(testRoomProvider{"myroom": room})
+
+	serve := func(roomName string, grants *auth.ClaimGrants) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/room_health?room="+roomName, nil)
+		if grants != nil {
+			req = req.WithContext(service.WithGrants(req.Context(), grants, "key"))
+		}
+		w := httptest.NewRecorder()
+		inspector.ServeHTTP(w, req)
+		return w
+	}
+	adminGrants := func(roomName string) *auth.ClaimGrants {
+		return &auth.ClaimGrants{Video: &auth.VideoGrant{RoomAdmin: true, Room: roomName}}
+	}
+
+	require.Equal(t, http.StatusUnauthorized, serve("myroom", nil).Code)
+	require.Equal(t, http.StatusUnauthorized, serve("myroom", adminGrants("other")).Code)
+	require.Equal(t, http.StatusNotFound, serve("unknown", adminGrants("unknown")).Code)
+
+	w := serve("myroom", adminGrants("myroom"))
+	require.Equal(t, http.StatusOK, w.Code)
+	report := &service.RoomHealthReport{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), report))
+	require.Equal(t, livekit.RoomName("myroom"), report.RoomName)
+	require.Len(t, report.Tracks, 1)
+	require.Equal(t, livekit.TrackID("video"), report.Tracks[0].TrackID)
+
+	video.GetInactiveDurationReturns(11 * time.Second)
+	w = serve("myroom", adminGrants("myroom"))
+	require.Equal(t, http.StatusServiceUnavailable, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), report))
+	require.Len(t, report.Tracks, 1)
+}
@@ -86,7 +86,8 @@ type RoomManager struct {
 
 	iceConfigCache *sutils.IceConfigCache[iceConfigCacheKey]
 
-	forwardStats *sfu.ForwardStats
+	forwardStats     *sfu.ForwardStats
+	rtpStatsStreamer *sfu.RTPStatsStreamer
 }
 
 func NewLocalRoomManager(
@@ -108,7 +109,7 @@ func NewLocalRoomManager(
 		return nil, err
 	}
 
-	return &RoomManager{
+	r := &RoomManager{
 		config:            conf,
 		rtcConfig:         rtcConf,
 		currentNode:       currentNode,
@@ -122,6 +123,7 @@ func NewLocalRoomManager(
 		turnAuthHandler:   turnAuthHandler,
 		bus:               bus,
 		forwardStats:      forwardStats,
+		rtpStatsStreamer:  sfu.NewRTPStatsStreamer(),
 
 		rooms: make(map[livekit.RoomName]*rtc.Room),
 
@@ -135,7 +137,9 @@ func NewLocalRoomManager(
 			Region:        conf.Region,
 			NodeId:        currentNode.Id,
 		},
-	}, nil
+	}
+	router.OnRoomMigration(r.handleRoomMigration)
+	return r, nil
 }
 
 func (r *RoomManager) GetRoom(_ context.Context, roomName livekit.RoomName) *rtc.Room {
@@ -215,6 +219,17 @@ func (r *RoomManager) HasParticipants() bool {
 	return false
 }
 
+// StreamRTPStats sends RTP stats of published track streams on this node, limited to the given tracks if any,
+// every interval (1s if not positive) until the context is done or send fails.
+func (r *RoomManager) StreamRTPStats(
+	ctx context.Context,
+	interval time.Duration,
+	trackIDs []livekit.TrackID,
+	send func(key sfu.RTPStatsStreamKey, stats *livekit.RTPStats) error,
+) error {
+	return r.rtpStatsStreamer.Stream(ctx, interval, trackIDs, send)
+}
+
 func (r *RoomManager) Stop() {
 	// disconnect all clients
 	r.lock.RLock()
@@ -449,8 +464,12 @@ func (r *RoomManager) StartSession(
 		SubscriptionLimitAudio:       r.config.Limit.SubscriptionLimitAudio,
 		SubscriptionLimitVideo:       r.config.Limit.SubscriptionLimitVideo,
 		PlayoutDelay:                 roomInternal.GetPlayoutDelay(),
+		EnforcePlayoutDelay:          r.config.Room.PlayoutDelay.EnforceOnSFU,
 		SyncStreams:                  roomInternal.GetSyncStreams(),
 		ForwardStats:                 r.forwardStats,
+		TrackForwardStats:            r.config.RTC.ForwardStats.PerTrack,
+		RoomBitrateLimiter:           room.BitrateLimiter(),
+		RTPStatsStreamer:             r.rtpStatsStreamer,
 	})
 	if err != nil {
 		return err
@@ -733,6 +752,20 @@ func (r *RoomManager) DeleteRoom(ctx context.Context, req *livekit.DeleteRoomReq
 	return &livekit.DeleteRoomResponse{}, nil
 }
 
+// handleRoomMigration drops signal connections of participants of a room hosted on this node,
+// their resume attempts get routed to the new node which asks them to do a full reconnect
+func (r *RoomManager) handleRoomMigration(ctx context.Context, roomName livekit.RoomName, nodeID livekit.NodeID) {
+	room := r.GetRoom(ctx, roomName)
+	if room == nil {
+		return
+	}
+
+	room.Logger.Infow("room migrated, disconnecting participants", "nodeID", nodeID)
+	for _, participant := range room.GetParticipants() {
+		_ = participant.Close(false, types.ParticipantCloseReasonMigrationRequested, true)
+	}
+}
+
 func (r *RoomManager) UpdateSubscriptions(ctx context.Context, req *livekit.UpdateSubscriptionsRequest) (*livekit.UpdateSubscriptionsResponse, error) {
 	room, participant, err := r.roomAndParticipantForReq(ctx, req)
 	if err != nil {
@@ -0,0 +1,109 @@
+// Copyright 2024 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"google.golang.org/protobuf/encoding/protowire"
+
+	"github.com/livekit/protocol/livekit"
+
+	"github.com/livekit/livekit-server/pkg/config"
+)
+
+// field number of template_id in CreateRoomRequest
+const createRoomRequestTemplateIDField protowire.Number = 11
+
+// RoomTemplate is a named set of defaults applied to rooms created with it.
+// Settings in the create request take precedence over the template, zero values are not applied.
+type RoomTemplate struct {
+	ID               string             `json:"id"`
+	EmptyTimeout     uint32             `json:"empty_timeout,omitempty"`
+	DepartureTimeout uint32             `json:"departure_timeout,omitempty"`
+	MaxParticipants  uint32             `json:"max_participants,omitempty"`
+	Metadata         string             `json:"metadata,omitempty"`
+	EnabledCodecs    []config.CodecSpec `json:"enabled_codecs,omitempty"`
+	MinPlayoutDelay  uint32             `json:"min_playout_delay,omitempty"`
+	MaxPlayoutDelay  uint32             `json:"max_playout_delay,omitempty"`
+	SyncStreams      bool               `json:"sync_streams,omitempty"`
+}
+
+// GetRoomTemplateID returns the template_id of the request. The field is not generated by the
+// protocol version in use, so it is read from the unknown fields retained when the request is decoded.
+func GetRoomTemplateID(req *livekit.CreateRoomRequest) string {
+	b := req.ProtoReflect().GetUnknown()
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return ""
+		}
+		b = b[n:]
+
+		if num == createRoomRequestTemplateIDField && typ == protowire.BytesType {
+			templateID, n := protowire.ConsumeString(b)
+			if n < 0 {
+				return ""
+			}
+			return templateID
+		}
+
+		n = protowire.ConsumeFieldValue(num, typ, b)
+		if n < 0 {
+			return ""
+		}
+		b = b[n:]
+	}
+	return ""
+}
+
+// SetRoomTemplateID sets the template_id of the request
+func SetRoomTemplateID(req *livekit.CreateRoomRequest, templateID string) {
+	b := protowire.AppendTag(nil, createRoomRequestTemplateIDField, protowire.BytesType)
+	b = protowire.AppendString(b, templateID)
+	req.ProtoReflect().SetUnknown(append(req.ProtoReflect().GetUnknown(), b...))
+}
+
+func applyRoomTemplate(room *livekit.Room, internal *livekit.RoomInternal, template *RoomTemplate) {
+	if template.EmptyTimeout > 0 {
+		room.EmptyTimeout = template.EmptyTimeout
+	}
+	if template.DepartureTimeout > 0 {
+		room.DepartureTimeout = template.DepartureTimeout
+	}
+	if template.MaxParticipants > 0 {
+		room.MaxParticipants = template.MaxParticipants
+	}
+	if template.Metadata != "" {
+		room.Metadata = template.Metadata
+	}
+	if len(template.EnabledCodecs) != 0 {
+		room.EnabledCodecs = room.EnabledCodecs[:0]
+		for _, codec := range template.EnabledCodecs {
+			room.EnabledCodecs = append(room.EnabledCodecs, &livekit.Codec{
+				Mime:     codec.Mime,
+				FmtpLine: codec.FmtpLine,
+			})
+		}
+	}
+	if template.MinPlayoutDelay > 0 || template.MaxPlayoutDelay > 0 {
+		internal.PlayoutDelay = &livekit.PlayoutDelay{
+			Enabled: true,
+			Min:     template.MinPlayoutDelay,
+			Max:     template.MaxPlayoutDelay,
+		}
+	}
+	if template.SyncStreams {
+		internal.SyncStreams = true
+	}
+}
@@ -37,6 +37,7 @@ import (
 
 	"github.com/livekit/livekit-server/pkg/config"
 	"github.com/livekit/livekit-server/pkg/routing"
+	"github.com/livekit/livekit-server/pkg/telemetry/prometheus"
 	"github.com/livekit/livekit-server/version"
 	"github.com/livekit/protocol/auth"
 	"github.com/livekit/protocol/livekit"
@@ -50,6 +51,7 @@ type LivekitServer struct {
 	agentService *AgentService
 	httpServer   *http.Server
 	promServer   *http.Server
+	promPusher   *prometheus.Pusher
 	router       routing.Router
 	roomManager  *RoomManager
 	signalServer *SignalServer
@@ -71,6 +73,7 @@ func NewLivekitServer(conf *config.Config,
 	keyProvider auth.KeyProvider,
 	router routing.Router,
 	roomManager *RoomManager,
+	roomInspector *RoomInspector,
 	signalServer *SignalServer,
 	turnServer *turn.Server,
 	currentNode routing.LocalNode,
@@ -101,6 +104,7 @@ func NewLivekitServer(conf *config.Config,
 			// allow preflight to be cached for a day
 			MaxAge: 86400,
 		}),
+		NewRegionHintMiddleware(),
 	}
 	if keyProvider != nil {
 		middlewares = append(middlewares, NewAPIKeyAuthMiddleware(keyProvider))
@@ -133,6 +137,7 @@ func NewLivekitServer(conf *config.Config,
 	mux.Handle("/rtc", rtcService)
 	mux.Handle("/agent", agentService)
 	mux.HandleFunc("/rtc/validate", rtcService.Validate)
+	mux.Handle("/room_health", roomInspector)
 	mux.HandleFunc("/", s.defaultHandler)
 
 	s.httpServer = &http.Server{
@@ -144,6 +149,9 @@ func NewLivekitServer(conf *config.Config,
 			Handler: promhttp.Handler(),
 		}
 	}
+	if conf.Telemetry.PrometheusPushURL != "" {
+		s.promPusher = prometheus.NewPusher(conf.Telemetry.PrometheusPushURL, conf.Telemetry.PushInterval, currentNode.Id)
+	}
 
 	// clean up old rooms on startup
 	if err = roomManager.CleanupRooms(); err != nil {
@@ -237,6 +245,9 @@ func (s *LivekitServer) Start() error {
 	if s.config.PrometheusPort != 0 {
 		values = append(values, "portPrometheus", s.config.PrometheusPort)
 	}
+	if s.config.Telemetry.PrometheusPushURL != "" {
+		values = append(values, "prometheusPushURL", s.config.Telemetry.PrometheusPushURL)
+	}
 	if s.config.Region != "" {
 		values = append(values, "region", s.config.Region)
 	}
@@ -248,6 +259,9 @@ func (s *LivekitServer) Start() error {
 	for _, promLn := range promListeners {
 		go s.promServer.Serve(promLn)
 	}
+	if s.promPusher != nil {
+		s.promPusher.Start()
+	}
 
 	if err := s.signalServer.Start(); err != nil {
 		return err
@@ -288,6 +302,9 @@ func (s *LivekitServer) Start() error {
 	s.roomManager.Stop()
 	s.signalServer.Stop()
 	s.ioService.Stop()
+	if s.promPusher != nil {
+		s.promPusher.Stop()
+	}
 
 	close(s.closedChan)
 	return nil
@@ -64,6 +64,20 @@ type FakeObjectStore struct {
 		result1 []*livekit.Room
 		result2 error
 	}
+	LoadNodeReservationsStub        func(context.Context, []livekit.NodeID) (map[livekit.NodeID]int, error)
+	loadNodeReservationsMutex       sync.RWMutex
+	loadNodeReservationsArgsForCall []struct {
+		arg1 context.Context
+		arg2 []livekit.NodeID
+	}
+	loadNodeReservationsReturns struct {
+		result1 map[livekit.NodeID]int
+		result2 error
+	}
+	loadNodeReservationsReturnsOnCall map[int]struct {
+		result1 map[livekit.NodeID]int
+		result2 error
+	}
 	LoadParticipantStub        func(context.Context, livekit.RoomName, livekit.ParticipantIdentity) (*livekit.ParticipantInfo, error)
 	loadParticipantMutex       sync.RWMutex
 	loadParticipantArgsForCall []struct {
@@ -96,6 +110,20 @@ type FakeObjectStore struct {
 		result2 *livekit.RoomInternal
 		result3 error
 	}
+	LoadRoomTemplateStub        func(context.Context, string) (*service.RoomTemplate, error)
+	loadRoomTemplateMutex       sync.RWMutex
+	loadRoomTemplateArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+	}
+	loadRoomTemplateReturns struct {
+		result1 *service.RoomTemplate
+		result2 error
+	}
+	loadRoomTemplateReturnsOnCall map[int]struct {
+		result1 *service.RoomTemplate
+		result2 error
+	}
 	LockRoomStub        func(context.Context, livekit.RoomName, time.Duration) (string, error)
 	lockRoomMutex       sync.RWMutex
 	lockRoomArgsForCall []struct {
@@ -111,6 +139,32 @@ type FakeObjectStore struct {
 		result1 string
 		result2 error
 	}
+	ReleaseNodeReservationStub        func(context.Context, livekit.NodeID) error
+	releaseNodeReservationMutex       sync.RWMutex
+	releaseNodeReservationArgsForCall []struct {
+		arg1 context.Context
+		arg2 livekit.NodeID
+	}
+	releaseNodeReservationReturns struct {
+		result1 error
+	}
+	releaseNodeReservationReturnsOnCall map[int]struct {
+		result1 error
+	}
+	ReserveNodeRoomsStub        func(context.Context, livekit.NodeID, int, time.Duration) error
+	reserveNodeRoomsMutex       sync.RWMutex
+	reserveNodeRoomsArgsForCall []struct {
+		arg1 context.Context
+		arg2 livekit.NodeID
+		arg3 int
+		arg4 time.Duration
+	}
+	reserveNodeRoomsReturns struct {
+		result1 error
+	}
+	reserveNodeRoomsReturnsOnCall map[int]struct {
+		result1 error
+	}
 	StoreParticipantStub        func(context.Context, livekit.RoomName, *livekit.ParticipantInfo) error
 	storeParticipantMutex       sync.RWMutex
 	storeParticipantArgsForCall []struct {
@@ -137,6 +191,18 @@ type FakeObjectStore struct {
 	storeRoomReturnsOnCall map[int]struct {
 		result1 error
 	}
+	StoreRoomTemplateStub        func(context.Context, *service.RoomTemplate) error
+	storeRoomTemplateMutex       sync.RWMutex
+	storeRoomTemplateArgsForCall []struct {
+		arg1 context.Context
+		arg2 *service.RoomTemplate
+	}
+	storeRoomTemplateReturns struct {
+		result1 error
+	}
+	storeRoomTemplateReturnsOnCall map[int]struct {
+		result1 error
+	}
 	UnlockRoomStub        func(context.Context, livekit.RoomName, string) error
 	unlockRoomMutex       sync.RWMutex
 	unlockRoomArgsForCall []struct {
@@ -414,6 +480,76 @@ func (fake *FakeObjectStore) ListRoomsReturnsOnCall(i int, result1 []*livekit.Ro
 	}{result1, result2}
 }
 
+func (fake *FakeObjectStore) LoadNodeReservations(arg1 context.Context, arg2 []livekit.NodeID) (map[livekit.NodeID]int, error) {
+	var arg2Copy []livekit.NodeID
+	if arg2 != nil {
+		arg2Copy = make([]livekit.NodeID, len(arg2))
+		copy(arg2Copy, arg2)
+	}
+	fake.loadNodeReservationsMutex.Lock()
+	ret, specificReturn := fake.loadNodeReservationsReturnsOnCall[len(fake.loadNodeReservationsArgsForCall)]
+	fake.loadNodeReservationsArgsForCall = append(fake.loadNodeReservationsArgsForCall, struct {
+		arg1 context.Context
+		arg2 []livekit.NodeID
+	}{arg1, arg2Copy})
+	stub := fake.LoadNodeReservationsStub
+	fakeReturns := fake.loadNodeReservationsReturns
+	fake.recordInvocation("LoadNodeReservations", []interface{}{arg1, arg2Copy})
+	fake.loadNodeReservationsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeObjectStore) LoadNodeReservationsCallCount() int {
+	fake.loadNodeReservationsMutex.RLock()
+	defer fake.loadNodeReservationsMutex.RUnlock()
+	return len(fake.loadNodeReservationsArgsForCall)
+}
+
+func (fake *FakeObjectStore) LoadNodeReservationsCalls(stub func(context.Context, []livekit.NodeID) (map[livekit.NodeID]int, error)) {
+	fake.loadNodeReservationsMutex.Lock()
+	defer fake.loadNodeReservationsMutex.Unlock()
+	fake.LoadNodeReservationsStub = stub
+}
+
+func (fake *FakeObjectStore) LoadNodeReservationsArgsForCall(i int) (context.Context, []livekit.NodeID) {
+	fake.loadNodeReservationsMutex.RLock()
+	defer fake.loadNodeReservationsMutex.RUnlock()
+	argsForCall := fake.loadNodeReservationsArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeObjectStore) LoadNodeReservationsReturns(result1 map[livekit.NodeID]int, result2 error) {
+	fake.loadNodeReservationsMutex.Lock()
+	defer fake.loadNodeReservationsMutex.Unlock()
+	fake.LoadNodeReservationsStub = nil
+	fake.loadNodeReservationsReturns = struct {
+		result1 map[livekit.NodeID]int
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeObjectStore) LoadNodeReservationsReturnsOnCall(i int, result1 map[livekit.NodeID]int, result2 error) {
+	fake.loadNodeReservationsMutex.Lock()
+	defer fake.loadNodeReservationsMutex.Unlock()
+	fake.LoadNodeReservationsStub = nil
+	if fake.loadNodeReservationsReturnsOnCall == nil {
+		fake.loadNodeReservationsReturnsOnCall = make(map[int]struct {
+			result1 map[livekit.NodeID]int
+			result2 error
+		})
+	}
+	fake.loadNodeReservationsReturnsOnCall[i] = struct {
+		result1 map[livekit.NodeID]int
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeObjectStore) LoadParticipant(arg1 context.Context, arg2 livekit.RoomName, arg3 livekit.ParticipantIdentity) (*livekit.ParticipantInfo, error) {
 	fake.loadParticipantMutex.Lock()
 	ret, specificReturn := fake.loadParticipantReturnsOnCall[len(fake.loadParticipantArgsForCall)]
@@ -436,6 +572,8 @@ func (fake *FakeObjectStore) LoadParticipant(arg1 context.Context, arg2 livekit.
 }
 
 func (fake *FakeObjectStore) LoadParticipantCallCount() int {
+	fake.loadNodeReservationsMutex.RLock()
+	defer fake.loadNodeReservationsMutex.RUnlock()
 	fake.loadParticipantMutex.RLock()
 	defer fake.loadParticipantMutex.RUnlock()
 	return len(fake.loadParticipantArgsForCall)
@@ -549,6 +687,71 @@ func (fake *FakeObjectStore) LoadRoomReturnsOnCall(i int, result1 *livekit.Room,
 	}{result1, result2, result3}
 }
 
+func (fake *FakeObjectStore) LoadRoomTemplate(arg1 context.Context, arg2 string) (*service.RoomTemplate, error) {
+	fake.loadRoomTemplateMutex.Lock()
+	ret, specificReturn := fake.loadRoomTemplateReturnsOnCall[len(fake.loadRoomTemplateArgsForCall)]
+	fake.loadRoomTemplateArgsForCall = append(fake.loadRoomTemplateArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.LoadRoomTemplateStub
+	fakeReturns := fake.loadRoomTemplateReturns
+	fake.recordInvocation("LoadRoomTemplate", []interface{}{arg1, arg2})
+	fake.loadRoomTemplateMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeObjectStore) LoadRoomTemplateCallCount() int {
+	fake.loadRoomTemplateMutex.RLock()
+	defer fake.loadRoomTemplateMutex.RUnlock()
+	return len(fake.loadRoomTemplateArgsForCall)
+}
+
+func (fake *FakeObjectStore) LoadRoomTemplateCalls(stub func(context.Context, string) (*service.RoomTemplate, error)) {
+	fake.loadRoomTemplateMutex.Lock()
+	defer fake.loadRoomTemplateMutex.Unlock()
+	fake.LoadRoomTemplateStub = stub
+}
+
+func (fake *FakeObjectStore) LoadRoomTemplateArgsForCall(i int) (context.Context, string) {
+	fake.loadRoomTemplateMutex.RLock()
+	defer fake.loadRoomTemplateMutex.RUnlock()
+	argsForCall := fake.loadRoomTemplateArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeObjectStore) LoadRoomTemplateReturns(result1 *service.RoomTemplate, result2 error) {
+	fake.loadRoomTemplateMutex.Lock()
+	defer fake.loadRoomTemplateMutex.Unlock()
+	fake.LoadRoomTemplateStub = nil
+	fake.loadRoomTemplateReturns = struct {
+		result1 *service.RoomTemplate
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeObjectStore) LoadRoomTemplateReturnsOnCall(i int, result1 *service.RoomTemplate, result2 error) {
+	fake.loadRoomTemplateMutex.Lock()
+	defer fake.loadRoomTemplateMutex.Unlock()
+	fake.LoadRoomTemplateStub = nil
+	if fake.loadRoomTemplateReturnsOnCall == nil {
+		fake.loadRoomTemplateReturnsOnCall = make(map[int]struct {
+			result1 *service.RoomTemplate
+			result2 error
+		})
+	}
+	fake.loadRoomTemplateReturnsOnCall[i] = struct {
+		result1 *service.RoomTemplate
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeObjectStore) LockRoom(arg1 context.Context, arg2 livekit.RoomName, arg3 time.Duration) (string, error) {
 	fake.lockRoomMutex.Lock()
 	ret, specificReturn := fake.lockRoomReturnsOnCall[len(fake.lockRoomArgsForCall)]
@@ -571,6 +774,8 @@ func (fake *FakeObjectStore) LockRoom(arg1 context.Context, arg2 livekit.RoomNam
 }
 
 func (fake *FakeObjectStore) LockRoomCallCount() int {
+	fake.loadRoomTemplateMutex.RLock()
+	defer fake.loadRoomTemplateMutex.RUnlock()
 	fake.lockRoomMutex.RLock()
 	defer fake.lockRoomMutex.RUnlock()
 	return len(fake.lockRoomArgsForCall)
@@ -615,6 +820,132 @@ func (fake *FakeObjectStore) LockRoomReturnsOnCall(i int, result1 string, result
 	}{result1, result2}
 }
 
+func (fake *FakeObjectStore) ReleaseNodeReservation(arg1 context.Context, arg2 livekit.NodeID) error {
+	fake.releaseNodeReservationMutex.Lock()
+	ret, specificReturn := fake.releaseNodeReservationReturnsOnCall[len(fake.releaseNodeReservationArgsForCall)]
+	fake.releaseNodeReservationArgsForCall = append(fake.releaseNodeReservationArgsForCall, struct {
+		arg1 context.Context
+		arg2 livekit.NodeID
+	}{arg1, arg2})
+	stub := fake.ReleaseNodeReservationStub
+	fakeReturns := fake.releaseNodeReservationReturns
+	fake.recordInvocation("ReleaseNodeReservation", []interface{}{arg1, arg2})
+	fake.releaseNodeReservationMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeObjectStore) ReleaseNodeReservationCallCount() int {
+	fake.releaseNodeReservationMutex.RLock()
+	defer fake.releaseNodeReservationMutex.RUnlock()
+	return len(fake.releaseNodeReservationArgsForCall)
+}
+
+func (fake *FakeObjectStore) ReleaseNodeReservationCalls(stub func(context.Context, livekit.NodeID) error) {
+	fake.releaseNodeReservationMutex.Lock()
+	defer fake.releaseNodeReservationMutex.Unlock()
+	fake.ReleaseNodeReservationStub = stub
+}
+
+func (fake *FakeObjectStore) ReleaseNodeReservationArgsForCall(i int) (context.Context, livekit.NodeID) {
+	fake.releaseNodeReservationMutex.RLock()
+	defer fake.releaseNodeReservationMutex.RUnlock()
+	argsForCall := fake.releaseNodeReservationArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeObjectStore) ReleaseNodeReservationReturns(result1 error) {
+	fake.releaseNodeReservationMutex.Lock()
+	defer fake.releaseNodeReservationMutex.Unlock()
+	fake.ReleaseNodeReservationStub = nil
+	fake.releaseNodeReservationReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeObjectStore) ReleaseNodeReservationReturnsOnCall(i int, result1 error) {
+	fake.releaseNodeReservationMutex.Lock()
+	defer fake.releaseNodeReservationMutex.Unlock()
+	fake.ReleaseNodeReservationStub = nil
+	if fake.releaseNodeReservationReturnsOnCall == nil {
+		fake.releaseNodeReservationReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.releaseNodeReservationReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeObjectStore) ReserveNodeRooms(arg1 context.Context, arg2 livekit.NodeID, arg3 int, arg4 time.Duration) error {
+	fake.reserveNodeRoomsMutex.Lock()
+	ret, specificReturn := fake.reserveNodeRoomsReturnsOnCall[len(fake.reserveNodeRoomsArgsForCall)]
+	fake.reserveNodeRoomsArgsForCall = append(fake.reserveNodeRoomsArgsForCall, struct {
+		arg1 context.Context
+		arg2 livekit.NodeID
+		arg3 int
+		arg4 time.Duration
+	}{arg1, arg2, arg3, arg4})
+	stub := fake.ReserveNodeRoomsStub
+	fakeReturns := fake.reserveNodeRoomsReturns
+	fake.recordInvocation("ReserveNodeRooms", []interface{}{arg1, arg2, arg3, arg4})
+	fake.reserveNodeRoomsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeObjectStore) ReserveNodeRoomsCallCount() int {
+	fake.reserveNodeRoomsMutex.RLock()
+	defer fake.reserveNodeRoomsMutex.RUnlock()
+	return len(fake.reserveNodeRoomsArgsForCall)
+}
+
+func (fake *FakeObjectStore) ReserveNodeRoomsCalls(stub func(context.Context, livekit.NodeID, int, time.Duration) error) {
+	fake.reserveNodeRoomsMutex.Lock()
+	defer fake.reserveNodeRoomsMutex.Unlock()
+	fake.ReserveNodeRoomsStub = stub
+}
+
+func (fake *FakeObjectStore) ReserveNodeRoomsArgsForCall(i int) (context.Context, livekit.NodeID, int, time.Duration) {
+	fake.reserveNodeRoomsMutex.RLock()
+	defer fake.reserveNodeRoomsMutex.RUnlock()
+	argsForCall := fake.reserveNodeRoomsArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+}
+
+func (fake *FakeObjectStore) ReserveNodeRoomsReturns(result1 error) {
+	fake.reserveNodeRoomsMutex.Lock()
+	defer fake.reserveNodeRoomsMutex.Unlock()
+	fake.ReserveNodeRoomsStub = nil
+	fake.reserveNodeRoomsReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeObjectStore) ReserveNodeRoomsReturnsOnCall(i int, result1 error) {
+	fake.reserveNodeRoomsMutex.Lock()
+	defer fake.reserveNodeRoomsMutex.Unlock()
+	fake.ReserveNodeRoomsStub = nil
+	if fake.reserveNodeRoomsReturnsOnCall == nil {
+		fake.reserveNodeRoomsReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.reserveNodeRoomsReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeObjectStore) StoreParticipant(arg1 context.Context, arg2 livekit.RoomName, arg3 *livekit.ParticipantInfo) error {
 	fake.storeParticipantMutex.Lock()
 	ret, specificReturn := fake.storeParticipantReturnsOnCall[len(fake.storeParticipantArgsForCall)]
@@ -637,6 +968,10 @@ func (fake *FakeObjectStore) StoreParticipant(arg1 context.Context, arg2 livekit
 }
 
 func (fake *FakeObjectStore) StoreParticipantCallCount() int {
+	fake.releaseNodeReservationMutex.RLock()
+	defer fake.releaseNodeReservationMutex.RUnlock()
+	fake.reserveNodeRoomsMutex.RLock()
+	defer fake.reserveNodeRoomsMutex.RUnlock()
 	fake.storeParticipantMutex.RLock()
 	defer fake.storeParticipantMutex.RUnlock()
 	return len(fake.storeParticipantArgsForCall)
@@ -741,6 +1076,68 @@ func (fake *FakeObjectStore) StoreRoomReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
+func (fake *FakeObjectStore) StoreRoomTemplate(arg1 context.Context, arg2 *service.RoomTemplate) error {
+	fake.storeRoomTemplateMutex.Lock()
+	ret, specificReturn := fake.storeRoomTemplateReturnsOnCall[len(fake.storeRoomTemplateArgsForCall)]
+	fake.storeRoomTemplateArgsForCall = append(fake.storeRoomTemplateArgsForCall, struct {
+		arg1 context.Context
+		arg2 *service.RoomTemplate
+	}{arg1, arg2})
+	stub := fake.StoreRoomTemplateStub
+	fakeReturns := fake.storeRoomTemplateReturns
+	fake.recordInvocation("StoreRoomTemplate", []interface{}{arg1, arg2})
+	fake.storeRoomTemplateMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeObjectStore) StoreRoomTemplateCallCount() int {
+	fake.storeRoomTemplateMutex.RLock()
+	defer fake.storeRoomTemplateMutex.RUnlock()
+	return len(fake.storeRoomTemplateArgsForCall)
+}
+
+func (fake *FakeObjectStore) StoreRoomTemplateCalls(stub func(context.Context, *service.RoomTemplate) error) {
+	fake.storeRoomTemplateMutex.Lock()
+	defer fake.storeRoomTemplateMutex.Unlock()
+	fake.StoreRoomTemplateStub = stub
+}
+
+func (fake *FakeObjectStore) StoreRoomTemplateArgsForCall(i int) (context.Context, *service.RoomTemplate) {
+	fake.storeRoomTemplateMutex.RLock()
+	defer fake.storeRoomTemplateMutex.RUnlock()
+	argsForCall := fake.storeRoomTemplateArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeObjectStore) StoreRoomTemplateReturns(result1 error) {
+	fake.storeRoomTemplateMutex.Lock()
+	defer fake.storeRoomTemplateMutex.Unlock()
+	fake.StoreRoomTemplateStub = nil
+	fake.storeRoomTemplateReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeObjectStore) StoreRoomTemplateReturnsOnCall(i int, result1 error) {
+	fake.storeRoomTemplateMutex.Lock()
+	defer fake.storeRoomTemplateMutex.Unlock()
+	fake.StoreRoomTemplateStub = nil
+	if fake.storeRoomTemplateReturnsOnCall == nil {
+		fake.storeRoomTemplateReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.storeRoomTemplateReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeObjectStore) UnlockRoom(arg1 context.Context, arg2 livekit.RoomName, arg3 string) error {
 	fake.unlockRoomMutex.Lock()
 	ret, specificReturn := fake.unlockRoomReturnsOnCall[len(fake.unlockRoomArgsForCall)]
@@ -763,6 +1160,8 @@ func (fake *FakeObjectStore) UnlockRoom(arg1 context.Context, arg2 livekit.RoomN
 }
 
 func (fake *FakeObjectStore) UnlockRoomCallCount() int {
+	fake.storeRoomTemplateMutex.RLock()
+	defer fake.storeRoomTemplateMutex.RUnlock()
 	fake.unlockRoomMutex.RLock()
 	defer fake.unlockRoomMutex.RUnlock()
 	return len(fake.unlockRoomArgsForCall)
@@ -10,6 +10,19 @@ import (
 )
 
 type FakeRoomAllocator struct {
+	BlacklistNodeStub        func(context.Context, livekit.NodeID, string) error
+	blacklistNodeMutex       sync.RWMutex
+	blacklistNodeArgsForCall []struct {
+		arg1 context.Context
+		arg2 livekit.NodeID
+		arg3 string
+	}
+	blacklistNodeReturns struct {
+		result1 error
+	}
+	blacklistNodeReturnsOnCall map[int]struct {
+		result1 error
+	}
 	CreateRoomStub        func(context.Context, *livekit.CreateRoomRequest) (*livekit.Room, bool, error)
 	createRoomMutex       sync.RWMutex
 	createRoomArgsForCall []struct {
@@ -26,6 +39,56 @@ type FakeRoomAllocator struct {
 		result2 bool
 		result3 error
 	}
+	GetAllocationStatsStub        func(context.Context) ([]service.RoomAllocationStat, error)
+	getAllocationStatsMutex       sync.RWMutex
+	getAllocationStatsArgsForCall []struct {
+		arg1 context.Context
+	}
+	getAllocationStatsReturns struct {
+		result1 []service.RoomAllocationStat
+		result2 error
+	}
+	getAllocationStatsReturnsOnCall map[int]struct {
+		result1 []service.RoomAllocationStat
+		result2 error
+	}
+	PreWarmNodeStub        func(context.Context, livekit.NodeID, int) error
+	preWarmNodeMutex       sync.RWMutex
+	preWarmNodeArgsForCall []struct {
+		arg1 context.Context
+		arg2 livekit.NodeID
+		arg3 int
+	}
+	preWarmNodeReturns struct {
+		result1 error
+	}
+	preWarmNodeReturnsOnCall map[int]struct {
+		result1 error
+	}
+	RebalanceRoomStub        func(context.Context, livekit.RoomName) error
+	rebalanceRoomMutex       sync.RWMutex
+	rebalanceRoomArgsForCall []struct {
+		arg1 context.Context
+		arg2 livekit.RoomName
+	}
+	rebalanceRoomReturns struct {
+		result1 error
+	}
+	rebalanceRoomReturnsOnCall map[int]struct {
+		result1 error
+	}
+	UnblacklistNodeStub        func(context.Context, livekit.NodeID) error
+	unblacklistNodeMutex       sync.RWMutex
+	unblacklistNodeArgsForCall []struct {
+		arg1 context.Context
+		arg2 livekit.NodeID
+	}
+	unblacklistNodeReturns struct {
+		result1 error
+	}
+	unblacklistNodeReturnsOnCall map[int]struct {
+		result1 error
+	}
 	ValidateCreateRoomStub        func(context.Context, livekit.RoomName) error
 	validateCreateRoomMutex       sync.RWMutex
 	validateCreateRoomArgsForCall []struct {
@@ -42,6 +105,69 @@ type FakeRoomAllocator struct {
 	invocationsMutex sync.RWMutex
 }
 
+func (fake *FakeRoomAllocator) BlacklistNode(arg1 context.Context, arg2 livekit.NodeID, arg3 string) error {
+	fake.blacklistNodeMutex.Lock()
+	ret, specificReturn := fake.blacklistNodeReturnsOnCall[len(fake.blacklistNodeArgsForCall)]
+	fake.blacklistNodeArgsForCall = append(fake.blacklistNodeArgsForCall, struct {
+		arg1 context.Context
+		arg2 livekit.NodeID
+		arg3 string
+	}{arg1, arg2, arg3})
+	stub := fake.BlacklistNodeStub
+	fakeReturns := fake.blacklistNodeReturns
+	fake.recordInvocation("BlacklistNode", []interface{}{arg1, arg2, arg3})
+	fake.blacklistNodeMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeRoomAllocator) BlacklistNodeCallCount() int {
+	fake.blacklistNodeMutex.RLock()
+	defer fake.blacklistNodeMutex.RUnlock()
+	return len(fake.blacklistNodeArgsForCall)
+}
+
+func (fake *FakeRoomAllocator) BlacklistNodeCalls(stub func(context.Context, livekit.NodeID, string) error) {
+	fake.blacklistNodeMutex.Lock()
+	defer fake.blacklistNodeMutex.Unlock()
+	fake.BlacklistNodeStub = stub
+}
+
+func (fake *FakeRoomAllocator) BlacklistNodeArgsForCall(i int) (context.Context, livekit.NodeID, string) {
+	fake.blacklistNodeMutex.RLock()
+	defer fake.blacklistNodeMutex.RUnlock()
+	argsForCall := fake.blacklistNodeArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeRoomAllocator) BlacklistNodeReturns(result1 error) {
+	fake.blacklistNodeMutex.Lock()
+	defer fake.blacklistNodeMutex.Unlock()
+	fake.BlacklistNodeStub = nil
+	fake.blacklistNodeReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeRoomAllocator) BlacklistNodeReturnsOnCall(i int, result1 error) {
+	fake.blacklistNodeMutex.Lock()
+	defer fake.blacklistNodeMutex.Unlock()
+	fake.BlacklistNodeStub = nil
+	if fake.blacklistNodeReturnsOnCall == nil {
+		fake.blacklistNodeReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.blacklistNodeReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeRoomAllocator) CreateRoom(arg1 context.Context, arg2 *livekit.CreateRoomRequest) (*livekit.Room, bool, error) {
 	fake.createRoomMutex.Lock()
 	ret, specificReturn := fake.createRoomReturnsOnCall[len(fake.createRoomArgsForCall)]
@@ -110,6 +236,259 @@ func (fake *FakeRoomAllocator) CreateRoomReturnsOnCall(i int, result1 *livekit.R
 	}{result1, result2, result3}
 }
 
+func (fake *FakeRoomAllocator) GetAllocationStats(arg1 context.Context) ([]service.RoomAllocationStat, error) {
+	fake.getAllocationStatsMutex.Lock()
+	ret, specificReturn := fake.getAllocationStatsReturnsOnCall[len(fake.getAllocationStatsArgsForCall)]
+	fake.getAllocationStatsArgsForCall = append(fake.getAllocationStatsArgsForCall, struct {
+		arg1 context.Context
+	}{arg1})
+	stub := fake.GetAllocationStatsStub
+	fakeReturns := fake.getAllocationStatsReturns
+	fake.recordInvocation("GetAllocationStats", []interface{}{arg1})
+	fake.getAllocationStatsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeRoomAllocator) GetAllocationStatsCallCount() int {
+	fake.getAllocationStatsMutex.RLock()
+	defer fake.getAllocationStatsMutex.RUnlock()
+	return len(fake.getAllocationStatsArgsForCall)
+}
+
+func (fake *FakeRoomAllocator) GetAllocationStatsCalls(stub func(context.Context) ([]service.RoomAllocationStat, error)) {
+	fake.getAllocationStatsMutex.Lock()
+	defer fake.getAllocationStatsMutex.Unlock()
+	fake.GetAllocationStatsStub = stub
+}
+
+func (fake *FakeRoomAllocator) GetAllocationStatsArgsForCall(i int) context.Context {
+	fake.getAllocationStatsMutex.RLock()
+	defer fake.getAllocationStatsMutex.RUnlock()
+	argsForCall := fake.getAllocationStatsArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeRoomAllocator) GetAllocationStatsReturns(result1 []service.RoomAllocationStat, result2 error) {
+	fake.getAllocationStatsMutex.Lock()
+	defer fake.getAllocationStatsMutex.Unlock()
+	fake.GetAllocationStatsStub = nil
+	fake.getAllocationStatsReturns = struct {
+		result1 []service.RoomAllocationStat
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeRoomAllocator) GetAllocationStatsReturnsOnCall(i int, result1 []service.RoomAllocationStat, result2 error) {
+	fake.getAllocationStatsMutex.Lock()
+	defer fake.getAllocationStatsMutex.Unlock()
+	fake.GetAllocationStatsStub = nil
+	if fake.getAllocationStatsReturnsOnCall == nil {
+		fake.getAllocationStatsReturnsOnCall = make(map[int]struct {
+			result1 []service.RoomAllocationStat
+			result2 error
+		})
+	}
+	fake.getAllocationStatsReturnsOnCall[i] = struct {
+		result1 []service.RoomAllocationStat
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeRoomAllocator) PreWarmNode(arg1 context.Context, arg2 livekit.NodeID, arg3 int) error {
+	fake.preWarmNodeMutex.Lock()
+	ret, specificReturn := fake.preWarmNodeReturnsOnCall[len(fake.preWarmNodeArgsForCall)]
+	fake.preWarmNodeArgsForCall = append(fake.preWarmNodeArgsForCall, struct {
+		arg1 context.Context
+		arg2 livekit.NodeID
+		arg3 int
+	}{arg1, arg2, arg3})
+	stub := fake.PreWarmNodeStub
+	fakeReturns := fake.preWarmNodeReturns
+	fake.recordInvocation("PreWarmNode", []interface{}{arg1, arg2, arg3})
+	fake.preWarmNodeMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeRoomAllocator) PreWarmNodeCallCount() int {
+	fake.preWarmNodeMutex.RLock()
+	defer fake.preWarmNodeMutex.RUnlock()
+	return len(fake.preWarmNodeArgsForCall)
+}
+
+func (fake *FakeRoomAllocator) PreWarmNodeCalls(stub func(context.Context, livekit.NodeID, int) error) {
+	fake.preWarmNodeMutex.Lock()
+	defer fake.preWarmNodeMutex.Unlock()
+	fake.PreWarmNodeStub = stub
+}
+
+func (fake *FakeRoomAllocator) PreWarmNodeArgsForCall(i int) (context.Context, livekit.NodeID, int) {
+	fake.preWarmNodeMutex.RLock()
+	defer fake.preWarmNodeMutex.RUnlock()
+	argsForCall := fake.preWarmNodeArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeRoomAllocator) PreWarmNodeReturns(result1 error) {
+	fake.preWarmNodeMutex.Lock()
+	defer fake.preWarmNodeMutex.Unlock()
+	fake.PreWarmNodeStub = nil
+	fake.preWarmNodeReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeRoomAllocator) PreWarmNodeReturnsOnCall(i int, result1 error) {
+	fake.preWarmNodeMutex.Lock()
+	defer fake.preWarmNodeMutex.Unlock()
+	fake.PreWarmNodeStub = nil
+	if fake.preWarmNodeReturnsOnCall == nil {
+		fake.preWarmNodeReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.preWarmNodeReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeRoomAllocator) RebalanceRoom(arg1 context.Context, arg2 livekit.RoomName) error {
+	fake.rebalanceRoomMutex.Lock()
+	ret, specificReturn := fake.rebalanceRoomReturnsOnCall[len(fake.rebalanceRoomArgsForCall)]
+	fake.rebalanceRoomArgsForCall = append(fake.rebalanceRoomArgsForCall, struct {
+		arg1 context.Context
+		arg2 livekit.RoomName
+	}{arg1, arg2})
+	stub := fake.RebalanceRoomStub
+	fakeReturns := fake.rebalanceRoomReturns
+	fake.recordInvocation("RebalanceRoom", []interface{}{arg1, arg2})
+	fake.rebalanceRoomMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeRoomAllocator) RebalanceRoomCallCount() int {
+	fake.preWarmNodeMutex.RLock()
+	defer fake.preWarmNodeMutex.RUnlock()
+	fake.rebalanceRoomMutex.RLock()
+	defer fake.rebalanceRoomMutex.RUnlock()
+	return len(fake.rebalanceRoomArgsForCall)
+}
+
+func (fake *FakeRoomAllocator) RebalanceRoomCalls(stub func(context.Context, livekit.RoomName) error) {
+	fake.rebalanceRoomMutex.Lock()
+	defer fake.rebalanceRoomMutex.Unlock()
+	fake.RebalanceRoomStub = stub
+}
+
+func (fake *FakeRoomAllocator) RebalanceRoomArgsForCall(i int) (context.Context, livekit.RoomName) {
+	fake.rebalanceRoomMutex.RLock()
+	defer fake.rebalanceRoomMutex.RUnlock()
+	argsForCall := fake.rebalanceRoomArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeRoomAllocator) RebalanceRoomReturns(result1 error) {
+	fake.rebalanceRoomMutex.Lock()
+	defer fake.rebalanceRoomMutex.Unlock()
+	fake.RebalanceRoomStub = nil
+	fake.rebalanceRoomReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeRoomAllocator) RebalanceRoomReturnsOnCall(i int, result1 error) {
+	fake.rebalanceRoomMutex.Lock()
+	defer fake.rebalanceRoomMutex.Unlock()
+	fake.RebalanceRoomStub = nil
+	if fake.rebalanceRoomReturnsOnCall == nil {
+		fake.rebalanceRoomReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.rebalanceRoomReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeRoomAllocator) UnblacklistNode(arg1 context.Context, arg2 livekit.NodeID) error {
+	fake.unblacklistNodeMutex.Lock()
+	ret, specificReturn := fake.unblacklistNodeReturnsOnCall[len(fake.unblacklistNodeArgsForCall)]
+	fake.unblacklistNodeArgsForCall = append(fake.unblacklistNodeArgsForCall, struct {
+		arg1 context.Context
+		arg2 livekit.NodeID
+	}{arg1, arg2})
+	stub := fake.UnblacklistNodeStub
+	fakeReturns := fake.unblacklistNodeReturns
+	fake.recordInvocation("UnblacklistNode", []interface{}{arg1, arg2})
+	fake.unblacklistNodeMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeRoomAllocator) UnblacklistNodeCallCount() int {
+	fake.unblacklistNodeMutex.RLock()
+	defer fake.unblacklistNodeMutex.RUnlock()
+	return len(fake.unblacklistNodeArgsForCall)
+}
+
+func (fake *FakeRoomAllocator) UnblacklistNodeCalls(stub func(context.Context, livekit.NodeID) error) {
+	fake.unblacklistNodeMutex.Lock()
+	defer fake.unblacklistNodeMutex.Unlock()
+	fake.UnblacklistNodeStub = stub
+}
+
+func (fake *FakeRoomAllocator) UnblacklistNodeArgsForCall(i int) (context.Context, livekit.NodeID) {
+	fake.unblacklistNodeMutex.RLock()
+	defer fake.unblacklistNodeMutex.RUnlock()
+	argsForCall := fake.unblacklistNodeArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeRoomAllocator) UnblacklistNodeReturns(result1 error) {
+	fake.unblacklistNodeMutex.Lock()
+	defer fake.unblacklistNodeMutex.Unlock()
+	fake.UnblacklistNodeStub = nil
+	fake.unblacklistNodeReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeRoomAllocator) UnblacklistNodeReturnsOnCall(i int, result1 error) {
+	fake.unblacklistNodeMutex.Lock()
+	defer fake.unblacklistNodeMutex.Unlock()
+	fake.UnblacklistNodeStub = nil
+	if fake.unblacklistNodeReturnsOnCall == nil {
+		fake.unblacklistNodeReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.unblacklistNodeReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeRoomAllocator) ValidateCreateRoom(arg1 context.Context, arg2 livekit.RoomName) error {
 	fake.validateCreateRoomMutex.Lock()
 	ret, specificReturn := fake.validateCreateRoomReturnsOnCall[len(fake.validateCreateRoomArgsForCall)]
@@ -175,8 +554,16 @@ func (fake *FakeRoomAllocator) ValidateCreateRoomReturnsOnCall(i int, result1 er
 func (fake *FakeRoomAllocator) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
+	fake.blacklistNodeMutex.RLock()
+	defer fake.blacklistNodeMutex.RUnlock()
 	fake.createRoomMutex.RLock()
 	defer fake.createRoomMutex.RUnlock()
+	fake.getAllocationStatsMutex.RLock()
+	defer fake.getAllocationStatsMutex.RUnlock()
+	fake.rebalanceRoomMutex.RLock()
+	defer fake.rebalanceRoomMutex.RUnlock()
+	fake.unblacklistNodeMutex.RLock()
+	defer fake.unblacklistNodeMutex.RUnlock()
 	fake.validateCreateRoomMutex.RLock()
 	defer fake.validateCreateRoomMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
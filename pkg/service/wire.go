@@ -90,6 +90,8 @@ func InitializeServer(conf *config.Config, currentNode routing.LocalNode) (*Live
 		rpc.NewTypedRoomClient,
 		rpc.NewTypedParticipantClient,
 		NewLocalRoomManager,
+		NewRoomInspector,
+		wire.Bind(new(RoomProvider), new(*RoomManager)),
 		NewTURNAuthHandler,
 		getTURNAuthHandlerFunc,
 		newInProcessTurnServer,
@@ -57,10 +57,6 @@ func InitializeServer(conf *config.Config, currentNode routing.LocalNode) (*Live
 	}
 	router := routing.CreateRouter(universalClient, currentNode, signalClient, keepalivePubSub)
 	objectStore := createStore(universalClient)
-	roomAllocator, err := NewRoomAllocator(conf, router, objectStore)
-	if err != nil {
-		return nil, err
-	}
 	client, err := agent.NewAgentClient(messageBus)
 	if err != nil {
 		return nil, err
@@ -87,6 +83,18 @@ func InitializeServer(conf *config.Config, currentNode routing.LocalNode) (*Live
 		return nil, err
 	}
 	rtcEgressLauncher := NewEgressLauncher(egressClient, ioInfoService)
+	clientConfigurationManager := createClientConfiguration()
+	timedVersionGenerator := utils.NewDefaultTimedVersionGenerator()
+	turnAuthHandler := NewTURNAuthHandler(keyProvider)
+	forwardStats := createForwardStats(conf)
+	roomManager, err := NewLocalRoomManager(conf, objectStore, currentNode, router, telemetryService, clientConfigurationManager, client, rtcEgressLauncher, timedVersionGenerator, turnAuthHandler, messageBus, forwardStats)
+	if err != nil {
+		return nil, err
+	}
+	roomAllocator, err := NewRoomAllocator(conf, router, objectStore)
+	if err != nil {
+		return nil, err
+	}
 	topicFormatter := rpc.NewTopicFormatter()
 	roomClient, err := rpc.NewTypedRoomClient(clientParams)
 	if err != nil {
@@ -118,24 +126,17 @@ func InitializeServer(conf *config.Config, currentNode routing.LocalNode) (*Live
 	if err != nil {
 		return nil, err
 	}
-	clientConfigurationManager := createClientConfiguration()
-	timedVersionGenerator := utils.NewDefaultTimedVersionGenerator()
-	turnAuthHandler := NewTURNAuthHandler(keyProvider)
-	forwardStats := createForwardStats(conf)
-	roomManager, err := NewLocalRoomManager(conf, objectStore, currentNode, router, telemetryService, clientConfigurationManager, client, rtcEgressLauncher, timedVersionGenerator, turnAuthHandler, messageBus, forwardStats)
-	if err != nil {
-		return nil, err
-	}
 	signalServer, err := NewDefaultSignalServer(currentNode, messageBus, signalRelayConfig, router, roomManager)
 	if err != nil {
 		return nil, err
 	}
+	roomInspector := NewRoomInspector(roomManager)
 	authHandler := getTURNAuthHandlerFunc(turnAuthHandler)
 	server, err := newInProcessTurnServer(conf, authHandler)
 	if err != nil {
 		return nil, err
 	}
-	livekitServer, err := NewLivekitServer(conf, roomService, egressService, ingressService, sipService, ioInfoService, rtcService, agentService, keyProvider, router, roomManager, signalServer, server, currentNode)
+	livekitServer, err := NewLivekitServer(conf, roomService, egressService, ingressService, sipService, ioInfoService, rtcService, agentService, keyProvider, router, roomManager, roomInspector, signalServer, server, currentNode)
 	if err != nil {
 		return nil, err
 	}
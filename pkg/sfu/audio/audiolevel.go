@@ -23,8 +23,18 @@ import (
 const (
 	silentAudioLevel = 127
 	negInv20         = -1.0 / 20
+
+	// 4 seconds of 20 ms frames
+	audioLevelHistorySize = 200
 )
 
+// AudioLevelSample is the level of one observed frame, CaptureTime is the arrival time of the frame
+type AudioLevelSample struct {
+	Level       float64
+	Duration    time.Duration
+	CaptureTime time.Time
+}
+
 type AudioLevelParams struct {
 	ActiveLevel     uint8
 	MinPercentile   uint8
@@ -47,6 +57,10 @@ type AudioLevel struct {
 	activeDuration       uint32 // ms
 	observedDuration     uint32 // ms
 	lastObservedAt       time.Time
+
+	history      [audioLevelHistorySize]AudioLevelSample
+	historyHead  int
+	historyCount int
 }
 
 func NewAudioLevel(params AudioLevelParams) *AudioLevel {
@@ -73,6 +87,16 @@ func (l *AudioLevel) Observe(level uint8, durationMs uint32, arrivalTime time.Ti
 
 	l.lastObservedAt = arrivalTime
 
+	l.history[l.historyHead] = AudioLevelSample{
+		Level:       ConvertAudioLevel(float64(level)),
+		Duration:    time.Duration(durationMs) * time.Millisecond,
+		CaptureTime: arrivalTime,
+	}
+	l.historyHead = (l.historyHead + 1) % audioLevelHistorySize
+	if l.historyCount < audioLevelHistorySize {
+		l.historyCount++
+	}
+
 	l.observedDuration += durationMs
 
 	if level <= l.params.ActiveLevel {
@@ -111,6 +135,25 @@ func (l *AudioLevel) GetLevel(now time.Time) (float64, bool) {
 	return l.smoothedLevel, l.smoothedLevel >= l.activeThreshold
 }
 
+// returns up to n most recent per frame levels, oldest first
+func (l *AudioLevel) GetHistory(n int) []AudioLevelSample {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	if n > l.historyCount {
+		n = l.historyCount
+	}
+	if n <= 0 {
+		return nil
+	}
+
+	samples := make([]AudioLevelSample, 0, n)
+	for i := n; i > 0; i-- {
+		samples = append(samples, l.history[(l.historyHead-i+audioLevelHistorySize)%audioLevelHistorySize])
+	}
+	return samples
+}
+
 func (l *AudioLevel) resetIfStaleLocked(arrivalTime time.Time) {
 	if arrivalTime.Sub(l.lastObservedAt).Milliseconds() < int64(2*l.params.ObserveDuration) {
 		return
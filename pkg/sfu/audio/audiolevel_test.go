@@ -106,6 +106,27 @@ func TestAudioLevel(t *testing.T) {
 	})
 }
 
+func TestAudioLevelHistory(t *testing.T) {
+	clock := time.Now()
+	a := createAudioLevel(defaultActiveLevel, defaultPercentile, defaultObserveDuration)
+	require.Empty(t, a.GetHistory(10))
+
+	observeSamples(a, 40, 150, clock)
+	observeSamples(a, 20, 100, clock.Add(150*20*time.Millisecond))
+
+	history := a.GetHistory(1000)
+	require.Len(t, history, audioLevelHistorySize)
+	// oldest first, 100 of the first batch remain
+	require.Equal(t, ConvertAudioLevel(40), history[99].Level)
+	require.Equal(t, ConvertAudioLevel(20), history[100].Level)
+	require.Equal(t, 20*time.Millisecond, history[0].Duration)
+	require.True(t, history[0].CaptureTime.Before(history[len(history)-1].CaptureTime))
+
+	history = a.GetHistory(3)
+	require.Len(t, history, 3)
+	require.Equal(t, ConvertAudioLevel(20), history[2].Level)
+}
+
 func createAudioLevel(activeLevel uint8, minPercentile uint8, observeDuration uint32) *AudioLevel {
 	return NewAudioLevel(AudioLevelParams{
 		ActiveLevel:     activeLevel,
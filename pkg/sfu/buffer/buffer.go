@@ -35,7 +35,6 @@ import (
 	dd "github.com/livekit/livekit-server/pkg/sfu/rtpextension/dependencydescriptor"
 	"github.com/livekit/livekit-server/pkg/sfu/utils"
 	sutils "github.com/livekit/livekit-server/pkg/utils"
-	"github.com/livekit/mediatransportutil"
 	"github.com/livekit/mediatransportutil/pkg/bucket"
 	"github.com/livekit/mediatransportutil/pkg/nack"
 	"github.com/livekit/mediatransportutil/pkg/twcc"
@@ -137,6 +136,9 @@ type Buffer struct {
 	rtxPktBuf           []byte
 
 	absCaptureTimeExtID uint8
+
+	packetRateLimiter  *packetRateLimiter
+	packetsRateLimited atomic.Uint64
 }
 
 // NewBuffer constructs a new Buffer
@@ -187,6 +189,24 @@ func (b *Buffer) SetAudioLevelParams(audioLevelParams audio.AudioLevelParams) {
 	b.audioLevelParams = audioLevelParams
 }
 
+// SetMaxPacketRate limits the packet rate of this buffer, packets over the limit are dropped on ingest
+// before they are NACK tracked or counted in stats. 0 removes the limit.
+func (b *Buffer) SetMaxPacketRate(pps int) {
+	b.Lock()
+	defer b.Unlock()
+
+	if pps <= 0 {
+		b.packetRateLimiter = nil
+	} else {
+		b.packetRateLimiter = newPacketRateLimiter(pps)
+	}
+}
+
+// GetPacketsRateLimited returns number of packets dropped due to packet rate limit
+func (b *Buffer) GetPacketsRateLimited() uint64 {
+	return b.packetsRateLimited.Load()
+}
+
 func (b *Buffer) SetAudioLossProxying(enable bool) {
 	b.Lock()
 	defer b.Unlock()
@@ -338,6 +358,21 @@ func (b *Buffer) Write(pkt []byte) (n int, err error) {
 	}
 
 	now := time.Now()
+	if b.packetRateLimiter != nil {
+		if allowed, shouldWarn := b.packetRateLimiter.Allow(now); !allowed {
+			b.packetsRateLimited.Inc()
+			if shouldWarn {
+				b.logger.Warnw(
+					"packet rate limit exceeded", nil,
+					"sn", rtpPacket.SequenceNumber,
+					"dropped", b.packetRateLimiter.NumDropped(),
+				)
+			}
+			b.Unlock()
+			return
+		}
+	}
+
 	if b.twcc != nil && b.twccExtID != 0 && !b.closed.Load() {
 		if ext := rtpPacket.GetExtension(b.twccExtID); ext != nil {
 			b.twcc.Push(rtpPacket.SSRC, binary.BigEndian.Uint16(ext[0:2]), now.UnixNano(), rtpPacket.Marker)
@@ -742,8 +777,8 @@ func (b *Buffer) processHeaderExtensions(p *rtp.Packet, arrivalTime time.Time, i
 		if e := p.GetExtension(b.audioLevelExtID); e != nil {
 			ext := rtp.AudioLevelExtension{}
 			if err := ext.Unmarshal(e); err == nil {
-				if (p.Timestamp - b.latestTSForAudioLevel) < (1 << 31) {
-					duration := (int64(p.Timestamp) - int64(b.latestTSForAudioLevel)) * 1e3 / int64(b.clockRate)
+				if diff := utils.Distance(b.latestTSForAudioLevel, p.Timestamp); diff >= 0 {
+					duration := diff * 1e3 / int64(b.clockRate)
 					if duration > 0 {
 						b.audioLevel.Observe(ext.Level, uint32(duration), arrivalTime)
 					}
@@ -883,9 +918,8 @@ func (b *Buffer) mayGrowBucket() {
 	}
 	oldCap := cap
 	if deltaInfo := b.rtpStats.DeltaInfo(b.ppsSnapshotId); deltaInfo != nil {
-		duration := deltaInfo.EndTime.Sub(deltaInfo.StartTime)
-		if duration > 500*time.Millisecond {
-			pps := int(time.Duration(deltaInfo.Packets) * time.Second / duration)
+		if deltaInfo.Duration() > 500*time.Millisecond {
+			pps := int(deltaInfo.PacketRatePps())
 			for pps > cap && cap < maxPkts {
 				cap = b.bucket.Grow()
 			}
@@ -921,17 +955,11 @@ func (b *Buffer) buildReceptionReport() *rtcp.ReceptionReport {
 	return b.rtpStats.GetRtcpReceptionReport(b.mediaSSRC, proxyLoss, b.rrSnapshotId)
 }
 
-func (b *Buffer) SetSenderReportData(rtpTime uint32, ntpTime uint64) {
+func (b *Buffer) SetSenderReport(sr *rtcp.SenderReport) {
 	b.RLock()
-	srData := &RTCPSenderReportData{
-		RTPTimestamp: rtpTime,
-		NTPTimestamp: mediatransportutil.NtpTime(ntpTime),
-		At:           time.Now(),
-	}
-
 	didSet := false
 	if b.rtpStats != nil {
-		didSet = b.rtpStats.SetRtcpSenderReportData(srData)
+		didSet = b.rtpStats.UpdateFromSenderReport(sr, time.Now()) == nil
 	}
 	b.RUnlock()
 
@@ -972,6 +1000,20 @@ func (b *Buffer) getRTCP() []rtcp.Packet {
 	return pkts
 }
 
+// SnapshotRtcpReceptionReport returns a receiver report covering the period since the previous report,
+// used to send reports periodically in addition to the ones sent on packet arrival.
+func (b *Buffer) SnapshotRtcpReceptionReport() []rtcp.Packet {
+	b.Lock()
+	defer b.Unlock()
+
+	if b.closed.Load() {
+		return nil
+	}
+
+	b.lastReport = time.Now()
+	return b.getRTCP()
+}
+
 func (b *Buffer) GetPacket(buff []byte, sn uint16) (int, error) {
 	b.Lock()
 	defer b.Unlock()
@@ -1062,6 +1104,17 @@ func (b *Buffer) GetAudioLevel() (float64, bool) {
 	return b.audioLevel.GetLevel(time.Now())
 }
 
+func (b *Buffer) GetAudioLevelHistory(n int) []audio.AudioLevelSample {
+	b.RLock()
+	defer b.RUnlock()
+
+	if b.audioLevel == nil {
+		return nil
+	}
+
+	return b.audioLevel.GetHistory(n)
+}
+
 func (b *Buffer) OnFpsChanged(f func()) {
 	b.Lock()
 	b.onFpsChanged = f
@@ -229,6 +229,49 @@ func TestNewBuffer(t *testing.T) {
 	}
 }
 
+func TestBufferMaxPacketRate(t *testing.T) {
+	buff := NewBuffer(123, 100, 100)
+	buff.codecType = webrtc.RTPCodecTypeVideo
+	buff.OnRtcpFeedback(func(_ []rtcp.Packet) {})
+	buff.Bind(webrtc.RTPParameters{
+		HeaderExtensions: nil,
+		Codecs:           []webrtc.RTPCodecParameters{vp8Codec},
+	}, vp8Codec.RTPCodecCapability, 0)
+	buff.SetMaxPacketRate(5)
+
+	for sn := uint16(1); sn <= 10; sn++ {
+		pkt := &rtp.Packet{
+			Header: rtp.Header{
+				Version:        2,
+				PayloadType:    96,
+				SequenceNumber: sn,
+				SSRC:           123,
+			},
+			Payload: []byte{1},
+		}
+		buf, _ := pkt.Marshal()
+		_, _ = buff.Write(buf)
+	}
+
+	// packets over the limit are dropped before they are counted in stats
+	require.EqualValues(t, 5, buff.GetPacketsRateLimited())
+	require.Equal(t, uint16(5), buff.rtpStats.sequenceNumber.GetHighest())
+
+	buff.SetMaxPacketRate(0)
+	pkt := &rtp.Packet{
+		Header: rtp.Header{
+			Version:        2,
+			PayloadType:    96,
+			SequenceNumber: 11,
+			SSRC:           123,
+		},
+		Payload: []byte{1},
+	}
+	buf, _ := pkt.Marshal()
+	_, _ = buff.Write(buf)
+	require.Equal(t, uint16(11), buff.rtpStats.sequenceNumber.GetHighest())
+}
+
 func TestFractionLostReport(t *testing.T) {
 	buff := NewBuffer(123, 1, 1)
 	require.NotNil(t, buff)
@@ -314,6 +357,43 @@ func TestFractionLostReport(t *testing.T) {
 	wg.Wait()
 }
 
+func TestSnapshotRtcpReceptionReport(t *testing.T) {
+	buff := NewBuffer(123, 1, 1)
+	require.Nil(t, buff.SnapshotRtcpReceptionReport())
+
+	buff.Bind(webrtc.RTPParameters{
+		HeaderExtensions: nil,
+		Codecs:           []webrtc.RTPCodecParameters{opusCodec},
+	}, opusCodec.RTPCodecCapability, 0)
+	for i := 0; i < 5; i++ {
+		pkt := rtp.Packet{
+			Header: rtp.Header{
+				Version:        2,
+				PayloadType:    111,
+				SequenceNumber: uint16(i),
+				Timestamp:      uint32(i),
+				SSRC:           123,
+			},
+			Payload: []byte{0xff, 0xff, 0xff, 0xfd, 0xb4, 0x9f, 0x94, 0x1},
+		}
+		b, err := pkt.Marshal()
+		require.NoError(t, err)
+		_, err = buff.Write(b)
+		require.NoError(t, err)
+	}
+
+	pkts := buff.SnapshotRtcpReceptionReport()
+	require.Len(t, pkts, 1)
+	rr, ok := pkts[0].(*rtcp.ReceiverReport)
+	require.True(t, ok)
+	require.Len(t, rr.Reports, 1)
+	require.Equal(t, uint32(123), rr.Reports[0].SSRC)
+	require.Zero(t, rr.Reports[0].TotalLost)
+
+	// nothing received since last report
+	require.Nil(t, buff.SnapshotRtcpReceptionReport())
+}
+
 func BenchmarkMemcpu(b *testing.B) {
 	buf := make([]byte, 1500*1500*10)
 	buf2 := make([]byte, 1500*1500*20)
@@ -19,6 +19,8 @@ import (
 
 	"github.com/livekit/protocol/logger"
 	"github.com/pion/rtp/codecs"
+
+	"github.com/livekit/livekit-server/pkg/sfu/utils"
 )
 
 var minFramesForCalculation = [...]int{8, 15, 40}
@@ -380,11 +382,12 @@ func (f *FrameRateCalculatorDD) RecvPacket(ep *ExtPacket) bool {
 		return false
 	}
 
-	baseDiff := fn - f.baseFrame.fn
-	if baseDiff == 0 || baseDiff > 0x8000 {
+	if utils.Distance(f.baseFrame.fn, fn) <= 0 {
 		return false
 	}
 
+	baseDiff := fn - f.baseFrame.fn
+
 	if baseDiff >= uint16(len(f.fnReceived)) {
 		// frame number is not continuous, reset
 		f.baseFrame = nil
@@ -428,7 +431,7 @@ func (f *FrameRateCalculatorDD) RecvPacket(ep *ExtPacket) bool {
 	for _, fdiff := range ep.DependencyDescriptor.Descriptor.FrameDependencies.FrameDiffs {
 		dependFrame := fn - uint16(fdiff)
 		// frame too old, ignore
-		if dependFrame-f.secondFrames[spatial][temporal].fn > 0x8000 {
+		if utils.Distance(f.secondFrames[spatial][temporal].fn, dependFrame) < 0 {
 			continue
 		}
 
@@ -0,0 +1,69 @@
+// Copyright 2024 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package buffer
+
+import (
+	"time"
+)
+
+const (
+	packetRateLimiterWarnInterval = time.Second
+)
+
+// packetRateLimiter is a token bucket limiting packet rate, bucket holds up to one second worth of packets.
+// It is not thread safe, buffer calls it with its lock held.
+type packetRateLimiter struct {
+	rate       float64
+	tokens     float64
+	lastRefill time.Time
+
+	numDropped uint64
+	lastWarnAt time.Time
+}
+
+func newPacketRateLimiter(pps int) *packetRateLimiter {
+	return &packetRateLimiter{
+		rate:   float64(pps),
+		tokens: float64(pps),
+	}
+}
+
+// Allow returns true if a packet arriving at the given time is within the rate limit.
+// When the packet is over the limit, shouldWarn indicates if the violation should be logged,
+// which is the case for the first violation and at most once per packetRateLimiterWarnInterval thereafter.
+func (p *packetRateLimiter) Allow(now time.Time) (allowed bool, shouldWarn bool) {
+	if !p.lastRefill.IsZero() {
+		if elapsed := now.Sub(p.lastRefill).Seconds(); elapsed > 0 {
+			p.tokens = min(p.rate, p.tokens+elapsed*p.rate)
+		}
+	}
+	p.lastRefill = now
+
+	if p.tokens >= 1 {
+		p.tokens--
+		return true, false
+	}
+
+	p.numDropped++
+	if p.lastWarnAt.IsZero() || now.Sub(p.lastWarnAt) >= packetRateLimiterWarnInterval {
+		p.lastWarnAt = now
+		return false, true
+	}
+	return false, false
+}
+
+func (p *packetRateLimiter) NumDropped() uint64 {
+	return p.numDropped
+}
@@ -0,0 +1,58 @@
+// Copyright 2024 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package buffer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPacketRateLimiter(t *testing.T) {
+	p := newPacketRateLimiter(100)
+	now := time.Now()
+
+	// burst of one second worth of packets is allowed
+	for i := 0; i < 100; i++ {
+		allowed, _ := p.Allow(now)
+		require.True(t, allowed)
+	}
+
+	// warns on first violation
+	allowed, shouldWarn := p.Allow(now)
+	require.False(t, allowed)
+	require.True(t, shouldWarn)
+
+	// and not again within a second
+	allowed, shouldWarn = p.Allow(now.Add(5 * time.Millisecond))
+	require.False(t, allowed)
+	require.False(t, shouldWarn)
+
+	// refills at the configured rate, 200 pps offered, ~100 pps allowed
+	numAllowed, numWarnings := 0, 0
+	for i := 1; i <= 2000; i++ {
+		allowed, shouldWarn = p.Allow(now.Add(time.Duration(i) * 5 * time.Millisecond))
+		if allowed {
+			numAllowed++
+		}
+		if shouldWarn {
+			numWarnings++
+		}
+	}
+	require.InDelta(t, 1000, numAllowed, 2)
+	require.InDelta(t, 10, numWarnings, 1)
+	require.Equal(t, uint64(2000-numAllowed+2), p.NumDropped())
+}
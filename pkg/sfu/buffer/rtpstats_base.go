@@ -15,11 +15,16 @@
 package buffer
 
 import (
+	"errors"
 	"fmt"
+	"math"
+	"slices"
 	"sync"
 	"time"
 
 	"go.uber.org/zap/zapcore"
+	"golang.org/x/exp/maps"
+	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/timestamppb"
 
 	"github.com/livekit/mediatransportutil"
@@ -30,8 +35,17 @@ import (
 
 const (
 	cGapHistogramNumBins = 101
-	cNumSequenceNumbers  = 65536
-	cFirstSnapshotID     = 1
+
+	cReorderHistogramNumBins = 4
+
+	cKeyFrameIntervalHistorySize = 100
+
+	cBurstLossHistorySize = 64
+	// minimum number of consecutive lost packets to consider a gap as a burst loss
+	cBurstLossMinPackets = 3
+
+	cNumSequenceNumbers = 65536
+	cFirstSnapshotID    = 1
 
 	cFirstPacketTimeAdjustWindow    = 2 * time.Minute
 	cFirstPacketTimeAdjustThreshold = 15 * time.Second
@@ -39,6 +53,28 @@ const (
 	cPassthroughNTPTimestamp = true
 
 	cSequenceNumberLargeJumpThreshold = 1000
+
+	cDefaultSnapshotHistoryDepth = 10
+
+	cWebRTCStatsIDInboundRTP       = "inbound-rtp"
+	cWebRTCStatsIDOutboundRTP      = "outbound-rtp"
+	cWebRTCStatsIDRemoteInboundRTP = "remote-inbound-rtp"
+
+	cBandwidthUtilizationWarnThreshold = 0.9
+)
+
+var (
+	ErrSnapshotNameEmpty  = errors.New("snapshot name is empty")
+	ErrSnapshotNameExists = errors.New("snapshot name already registered")
+
+	ErrPublisherReportUnavailable  = errors.New("publisher sender report unavailable")
+	ErrSubscriberReportUnavailable = errors.New("subscriber sender report unavailable")
+	ErrE2EDelayInconsistent        = errors.New("inconsistent end-to-end delay")
+
+	ErrSenderReportInvalid           = errors.New("invalid sender report")
+	ErrSenderReportBeforeFirstPacket = errors.New("sender report received before first packet")
+	ErrSenderReportAnachronous       = errors.New("anachronous sender report")
+	ErrSenderReportOutOfOrder        = errors.New("out-of-order sender report")
 )
 
 // -------------------------------------------------------
@@ -78,6 +114,35 @@ type RTPDeltaInfo struct {
 	Nacks                uint32
 	Plis                 uint32
 	Firs                 uint32
+
+	// averages weighted by duration, available only on aggregated deltas, see AggregateRTPDeltaInfo
+	RttAvg    uint32
+	JitterAvg float64
+}
+
+// Duration returns the time span covered by the delta.
+func (d *RTPDeltaInfo) Duration() time.Duration {
+	return d.EndTime.Sub(d.StartTime)
+}
+
+// BitrateKbps returns the bitrate (including RTP headers) in kbps over the delta, 0 if the delta has no duration.
+func (d *RTPDeltaInfo) BitrateKbps() float64 {
+	seconds := d.Duration().Seconds()
+	if seconds <= 0 {
+		return 0
+	}
+
+	return float64(d.Bytes) * 8.0 / 1000.0 / seconds
+}
+
+// PacketRatePps returns the packet rate in packets per second over the delta, 0 if the delta has no duration.
+func (d *RTPDeltaInfo) PacketRatePps() float64 {
+	seconds := d.Duration().Seconds()
+	if seconds <= 0 {
+		return 0
+	}
+
+	return float64(d.Packets) / seconds
 }
 
 type snapshot struct {
@@ -117,6 +182,8 @@ type RTCPSenderReportData struct {
 	RTPTimestamp    uint32
 	RTPTimestampExt uint64
 	NTPTimestamp    mediatransportutil.NtpTime
+	PacketCount     uint32
+	PacketCountExt  uint64
 	At              time.Time
 	AtAdjusted      time.Time
 }
@@ -134,10 +201,12 @@ func (r *RTCPSenderReportData) ToString() string {
 		return ""
 	}
 
-	return fmt.Sprintf("ntp: %s, rtp: %d, extRtp: %d, at: %s, atAdj: %s",
+	return fmt.Sprintf("ntp: %s, rtp: %d, extRtp: %d, packets: %d, extPackets: %d, at: %s, atAdj: %s",
 		r.NTPTimestamp.Time().String(),
 		r.RTPTimestamp,
 		r.RTPTimestampExt,
+		r.PacketCount,
+		r.PacketCountExt,
 		r.At.String(),
 		r.AtAdjusted.String(),
 	)
@@ -151,6 +220,8 @@ func (r *RTCPSenderReportData) MarshalLogObject(e zapcore.ObjectEncoder) error {
 	e.AddTime("NTPTimestamp", r.NTPTimestamp.Time())
 	e.AddUint32("RTPTimestamp", r.RTPTimestamp)
 	e.AddUint64("RTPTimestampExt", r.RTPTimestampExt)
+	e.AddUint32("PacketCount", r.PacketCount)
+	e.AddUint64("PacketCountExt", r.PacketCountExt)
 	e.AddTime("At", r.At)
 	e.AddTime("AtAdjusted", r.AtAdjusted)
 	return nil
@@ -158,9 +229,25 @@ func (r *RTCPSenderReportData) MarshalLogObject(e zapcore.ObjectEncoder) error {
 
 // ------------------------------------------------------------------
 
+// ClockFunc returns the current time, allows replacing wall clock in tests
+type ClockFunc func() time.Time
+
 type RTPStatsParams struct {
 	ClockRate uint32
 	Logger    logger.Logger
+	// defaults to time.Now
+	ClockFunc ClockFunc
+	// number of delta infos kept per snapshot, defaults to cDefaultSnapshotHistoryDepth
+	SnapshotHistoryDepth int
+	// size of sequence number info ring used by sender stats, should be a power of 2, defaults to cDefaultSnInfoSize
+	SnInfoSize uint16
+}
+
+type burstLoss struct {
+	startSN  uint64
+	endSN    uint64
+	duration time.Duration
+	at       time.Time
 }
 
 type rtpStatsBase struct {
@@ -201,6 +288,9 @@ type rtpStatsBase struct {
 
 	gapHistogram [cGapHistogramNumBins]uint32
 
+	// out-of-order distance bins: 1-5, 6-20, 21-50, > 50 sequence numbers behind highest
+	reorderHistogram [cReorderHistogramNumBins]uint32
+
 	nacks        uint32
 	nackAcks     uint32
 	nackMisses   uint32
@@ -218,6 +308,15 @@ type rtpStatsBase struct {
 	keyFrames    uint32
 	lastKeyFrame time.Time
 
+	keyFrameIntervals      [cKeyFrameIntervalHistorySize]time.Duration
+	keyFrameIntervalsHead  int
+	keyFrameIntervalsCount int
+
+	burstLosses          [cBurstLossHistorySize]burstLoss
+	burstLossesHead      int
+	numBurstLosses       uint32
+	maxBurstLossDuration time.Duration
+
 	rtt    uint32
 	maxRtt uint32
 
@@ -226,14 +325,31 @@ type rtpStatsBase struct {
 
 	nextSnapshotID uint32
 	snapshots      []snapshot
+	snapshotNames  map[uint32]string
+
+	snapshotHistoryDepth int
+	snapshotHistory      map[uint32][]*RTPDeltaInfo
+
+	estimatedBitrate            int64
+	bandwidthEstimateTime       time.Time
+	bandwidthEstimateBytes      uint64
+	bandwidthUtilizationRatio   float64
+	isBandwidthUtilizationAbove bool
 }
 
 func newRTPStatsBase(params RTPStatsParams) *rtpStatsBase {
+	snapshotHistoryDepth := params.SnapshotHistoryDepth
+	if snapshotHistoryDepth <= 0 {
+		snapshotHistoryDepth = cDefaultSnapshotHistoryDepth
+	}
 	return &rtpStatsBase{
-		params:         params,
-		logger:         params.Logger,
-		nextSnapshotID: cFirstSnapshotID,
-		snapshots:      make([]snapshot, 2),
+		params:               params,
+		logger:               params.Logger,
+		nextSnapshotID:       cFirstSnapshotID,
+		snapshots:            make([]snapshot, 2),
+		snapshotNames:        make(map[uint32]string),
+		snapshotHistoryDepth: snapshotHistoryDepth,
+		snapshotHistory:      make(map[uint32][]*RTPDeltaInfo),
 	}
 }
 
@@ -272,6 +388,7 @@ func (r *rtpStatsBase) seed(from *rtpStatsBase) bool {
 	r.maxJitter = from.maxJitter
 
 	r.gapHistogram = from.gapHistogram
+	r.reorderHistogram = from.reorderHistogram
 
 	r.nacks = from.nacks
 	r.nackAcks = from.nackAcks
@@ -289,6 +406,14 @@ func (r *rtpStatsBase) seed(from *rtpStatsBase) bool {
 
 	r.keyFrames = from.keyFrames
 	r.lastKeyFrame = from.lastKeyFrame
+	r.keyFrameIntervals = from.keyFrameIntervals
+	r.keyFrameIntervalsHead = from.keyFrameIntervalsHead
+	r.keyFrameIntervalsCount = from.keyFrameIntervalsCount
+
+	r.burstLosses = from.burstLosses
+	r.burstLossesHead = from.burstLossesHead
+	r.numBurstLosses = from.numBurstLosses
+	r.maxBurstLossDuration = from.maxBurstLossDuration
 
 	r.rtt = from.rtt
 	r.maxRtt = from.maxRtt
@@ -309,9 +434,17 @@ func (r *rtpStatsBase) seed(from *rtpStatsBase) bool {
 	r.nextSnapshotID = from.nextSnapshotID
 	r.snapshots = make([]snapshot, cap(from.snapshots))
 	copy(r.snapshots, from.snapshots)
+	r.snapshotNames = maps.Clone(from.snapshotNames)
 	return true
 }
 
+func (r *rtpStatsBase) now() time.Time {
+	if r.params.ClockFunc != nil {
+		return r.params.ClockFunc()
+	}
+	return time.Now()
+}
+
 func (r *rtpStatsBase) SetLogger(logger logger.Logger) {
 	r.logger = logger
 }
@@ -320,7 +453,7 @@ func (r *rtpStatsBase) Stop() {
 	r.lock.Lock()
 	defer r.lock.Unlock()
 
-	r.endTime = time.Now()
+	r.endTime = r.now()
 }
 
 func (r *rtpStatsBase) newSnapshotID(extStartSN uint64) uint32 {
@@ -334,11 +467,34 @@ func (r *rtpStatsBase) newSnapshotID(extStartSN uint64) uint32 {
 	}
 
 	if r.initialized {
-		r.snapshots[id-cFirstSnapshotID] = r.initSnapshot(time.Now(), extStartSN)
+		r.snapshots[id-cFirstSnapshotID] = r.initSnapshot(r.now(), extStartSN)
 	}
 	return id
 }
 
+func (r *rtpStatsBase) newNamedSnapshotID(name string, extStartSN uint64) (uint32, error) {
+	if name == "" {
+		return 0, ErrSnapshotNameEmpty
+	}
+	for _, n := range r.snapshotNames {
+		if n == name {
+			return 0, ErrSnapshotNameExists
+		}
+	}
+
+	id := r.newSnapshotID(extStartSN)
+	r.snapshotNames[id] = name
+	return id, nil
+}
+
+// GetSnapshotName returns the name a snapshot was registered with, empty for unnamed snapshots
+func (r *rtpStatsBase) GetSnapshotName(snapshotID uint32) string {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	return r.snapshotNames[snapshotID]
+}
+
 func (r *rtpStatsBase) IsActive() bool {
 	r.lock.RLock()
 	defer r.lock.RUnlock()
@@ -374,7 +530,7 @@ func (r *rtpStatsBase) CheckAndUpdatePli(throttle int64, force bool) bool {
 	r.lock.Lock()
 	defer r.lock.Unlock()
 
-	if !r.endTime.IsZero() || (!force && time.Now().UnixNano()-r.lastPli.UnixNano() < throttle) {
+	if !r.endTime.IsZero() || (!force && r.now().UnixNano()-r.lastPli.UnixNano() < throttle) {
 		return false
 	}
 	r.updatePliLocked(1)
@@ -421,7 +577,7 @@ func (r *rtpStatsBase) UpdatePliTime() {
 }
 
 func (r *rtpStatsBase) updatePliTimeLocked() {
-	r.lastPli = time.Now()
+	r.lastPli = r.now()
 }
 
 func (r *rtpStatsBase) LastPli() time.Time {
@@ -440,7 +596,7 @@ func (r *rtpStatsBase) UpdateLayerLockPliAndTime(pliCount uint32) {
 	}
 
 	r.layerLockPlis += pliCount
-	r.lastLayerLockPli = time.Now()
+	r.lastLayerLockPli = r.now()
 }
 
 func (r *rtpStatsBase) UpdateFir(firCount uint32) {
@@ -462,7 +618,7 @@ func (r *rtpStatsBase) UpdateFirTime() {
 		return
 	}
 
-	r.lastFir = time.Now()
+	r.lastFir = r.now()
 }
 
 func (r *rtpStatsBase) UpdateKeyFrame(kfCount uint32) {
@@ -473,8 +629,100 @@ func (r *rtpStatsBase) UpdateKeyFrame(kfCount uint32) {
 		return
 	}
 
+	now := r.now()
+	if !r.lastKeyFrame.IsZero() {
+		r.keyFrameIntervals[r.keyFrameIntervalsHead] = now.Sub(r.lastKeyFrame)
+		r.keyFrameIntervalsHead = (r.keyFrameIntervalsHead + 1) % cKeyFrameIntervalHistorySize
+		if r.keyFrameIntervalsCount < cKeyFrameIntervalHistorySize {
+			r.keyFrameIntervalsCount++
+		}
+	}
+
 	r.keyFrames += kfCount
-	r.lastKeyFrame = time.Now()
+	r.lastKeyFrame = now
+}
+
+// GetKeyFrameIntervalStats returns percentiles of recent intervals between key frames, all 0 if not known
+func (r *rtpStatsBase) GetKeyFrameIntervalStats() (p50, p95, p99 time.Duration) {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	return r.getKeyFrameIntervalStatsLocked()
+}
+
+func (r *rtpStatsBase) getKeyFrameIntervalStatsLocked() (p50, p95, p99 time.Duration) {
+	if r.keyFrameIntervalsCount == 0 {
+		return
+	}
+
+	intervals := slices.Clone(r.keyFrameIntervals[:r.keyFrameIntervalsCount])
+	slices.Sort(intervals)
+
+	// nearest rank
+	percentile := func(p int) time.Duration {
+		rank := (p*len(intervals) + 99) / 100
+		return intervals[max(rank, 1)-1]
+	}
+	return percentile(50), percentile(95), percentile(99)
+}
+
+// UpdateBurstLoss records a burst loss event spanning sequence numbers [startSN, endSN] and lasting the given duration.
+func (r *rtpStatsBase) UpdateBurstLoss(startSN, endSN uint64, duration time.Duration) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.updateBurstLossLocked(startSN, endSN, duration)
+}
+
+func (r *rtpStatsBase) updateBurstLossLocked(startSN, endSN uint64, duration time.Duration) {
+	if !r.endTime.IsZero() || endSN < startSN {
+		return
+	}
+
+	r.burstLosses[r.burstLossesHead] = burstLoss{
+		startSN:  startSN,
+		endSN:    endSN,
+		duration: duration,
+		at:       r.now(),
+	}
+	r.burstLossesHead = (r.burstLossesHead + 1) % cBurstLossHistorySize
+	r.numBurstLosses++
+	if duration > r.maxBurstLossDuration {
+		r.maxBurstLossDuration = duration
+	}
+}
+
+// GetBurstLossRate returns number of burst loss events per minute over the life of the stream.
+func (r *rtpStatsBase) GetBurstLossRate() float64 {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	return r.getBurstLossRateLocked()
+}
+
+func (r *rtpStatsBase) getBurstLossRateLocked() float64 {
+	if r.numBurstLosses == 0 || r.startTime.IsZero() {
+		return 0
+	}
+
+	endTime := r.endTime
+	if endTime.IsZero() {
+		endTime = r.now()
+	}
+	elapsed := endTime.Sub(r.startTime).Minutes()
+	if elapsed <= 0 {
+		return 0
+	}
+
+	return float64(r.numBurstLosses) / elapsed
+}
+
+// GetBurstLossStats returns the number of burst loss events and the longest burst loss duration.
+func (r *rtpStatsBase) GetBurstLossStats() (uint32, time.Duration) {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	return r.numBurstLosses, r.maxBurstLossDuration
 }
 
 func (r *rtpStatsBase) UpdateRtt(rtt uint32) {
@@ -498,6 +746,49 @@ func (r *rtpStatsBase) UpdateRtt(rtt uint32) {
 	}
 }
 
+// UpdateBandwidthEstimate records the estimated available bandwidth (in bps) and updates ratio of
+// bitrate since the previous estimate to the estimate, warning when it goes above threshold.
+func (r *rtpStatsBase) UpdateBandwidthEstimate(estimatedBitrate int64) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if !r.endTime.IsZero() {
+		return
+	}
+
+	now := r.now()
+	if !r.bandwidthEstimateTime.IsZero() && estimatedBitrate > 0 {
+		if elapsed := now.Sub(r.bandwidthEstimateTime).Seconds(); elapsed > 0 {
+			bitrate := float64(r.bytes-r.bandwidthEstimateBytes) * 8.0 / elapsed
+			r.bandwidthUtilizationRatio = bitrate / float64(estimatedBitrate)
+
+			isAbove := r.bandwidthUtilizationRatio > cBandwidthUtilizationWarnThreshold
+			if isAbove && !r.isBandwidthUtilizationAbove {
+				r.logger.Warnw(
+					"bandwidth utilization high", nil,
+					"bitrate", bitrate,
+					"estimatedBitrate", estimatedBitrate,
+					"ratio", r.bandwidthUtilizationRatio,
+				)
+			}
+			r.isBandwidthUtilizationAbove = isAbove
+		}
+	}
+
+	r.estimatedBitrate = estimatedBitrate
+	r.bandwidthEstimateTime = now
+	r.bandwidthEstimateBytes = r.bytes
+}
+
+// GetBandwidthUtilizationRatio returns ratio of bitrate to estimated available bandwidth
+// as of the latest bandwidth estimate update, 0 if not known.
+func (r *rtpStatsBase) GetBandwidthUtilizationRatio() float64 {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	return r.bandwidthUtilizationRatio
+}
+
 func (r *rtpStatsBase) GetRtt() uint32 {
 	r.lock.RLock()
 	defer r.lock.RUnlock()
@@ -558,8 +849,28 @@ func (r *rtpStatsBase) maybeAdjustFirstPacketTime(srData *RTCPSenderReportData,
 	}
 }
 
+// GetPacketsLostPublic returns the number of packets lost so far.
+func (r *rtpStatsBase) GetPacketsLostPublic() uint64 {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	return r.packetsLost
+}
+
+// GetPacketsDuplicate returns the number of duplicate packets so far.
+func (r *rtpStatsBase) GetPacketsDuplicate() uint64 {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	return r.packetsDuplicate
+}
+
+func (r *rtpStatsBase) getPacketsExpected(extStartSN, extHighestSN uint64) uint64 {
+	return extHighestSN - extStartSN + 1
+}
+
 func (r *rtpStatsBase) getTotalPacketsPrimary(extStartSN, extHighestSN uint64) uint64 {
-	packetsExpected := extHighestSN - extStartSN + 1
+	packetsExpected := r.getPacketsExpected(extStartSN, extHighestSN)
 	if r.packetsLost > packetsExpected {
 		// should not happen
 		return 0
@@ -574,6 +885,32 @@ func (r *rtpStatsBase) getTotalPacketsPrimary(extStartSN, extHighestSN uint64) u
 }
 
 func (r *rtpStatsBase) deltaInfo(snapshotID uint32, extStartSN uint64, extHighestSN uint64) *RTPDeltaInfo {
+	deltaInfo := r.getDeltaInfo(snapshotID, extStartSN, extHighestSN)
+	r.addSnapshotHistory(r.snapshotHistory, snapshotID, deltaInfo)
+	return deltaInfo
+}
+
+func (r *rtpStatsBase) addSnapshotHistory(snapshotHistory map[uint32][]*RTPDeltaInfo, snapshotID uint32, deltaInfo *RTPDeltaInfo) {
+	if deltaInfo == nil {
+		return
+	}
+
+	history := append(snapshotHistory[snapshotID], deltaInfo)
+	if len(history) > r.snapshotHistoryDepth {
+		history = history[len(history)-r.snapshotHistoryDepth:]
+	}
+	snapshotHistory[snapshotID] = history
+}
+
+// GetSnapshotHistory returns the most recent delta infos of a snapshot, oldest first
+func (r *rtpStatsBase) GetSnapshotHistory(snapshotID uint32) []*RTPDeltaInfo {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	return slices.Clone(r.snapshotHistory[snapshotID])
+}
+
+func (r *rtpStatsBase) getDeltaInfo(snapshotID uint32, extStartSN uint64, extHighestSN uint64) *RTPDeltaInfo {
 	then, now := r.getAndResetSnapshot(snapshotID, extStartSN, extHighestSN)
 	if now == nil || then == nil {
 		return nil
@@ -691,6 +1028,10 @@ func (r *rtpStatsBase) MarshalLogObject(e zapcore.ObjectEncoder) error {
 		e.AddString("gapHistogram", str)
 	}
 
+	if r.packetsOutOfOrder != 0 {
+		e.AddString("reorderHistogram", fmt.Sprintf("%v", r.reorderHistogram))
+	}
+
 	e.AddUint32("nacks", r.nacks)
 	e.AddUint32("nackAcks", r.nackAcks)
 	e.AddUint32("nackMisses", r.nackMisses)
@@ -707,6 +1048,20 @@ func (r *rtpStatsBase) MarshalLogObject(e zapcore.ObjectEncoder) error {
 
 	e.AddUint32("keyFrames", r.keyFrames)
 	e.AddTime("lastKeyFrame", r.lastKeyFrame)
+	if r.keyFrameIntervalsCount != 0 {
+		p50, p95, p99 := r.getKeyFrameIntervalStatsLocked()
+		e.AddDuration("keyFrameIntervalP50", p50)
+		e.AddDuration("keyFrameIntervalP95", p95)
+		e.AddDuration("keyFrameIntervalP99", p99)
+	}
+
+	if r.numBurstLosses != 0 {
+		e.AddUint32("burstLosses", r.numBurstLosses)
+		e.AddDuration("maxBurstLossDuration", r.maxBurstLossDuration)
+		e.AddFloat64("burstLossRate", r.getBurstLossRateLocked())
+		last := r.burstLosses[(r.burstLossesHead+cBurstLossHistorySize-1)%cBurstLossHistorySize]
+		e.AddString("lastBurstLoss", fmt.Sprintf("%d-%d, %s @ %s", last.startSN, last.endSN, last.duration, last.at.String()))
+	}
 
 	e.AddUint32("rtt", r.rtt)
 	e.AddUint32("maxRtt", r.maxRtt)
@@ -730,7 +1085,7 @@ func (r *rtpStatsBase) toString(
 		return ""
 	}
 
-	expectedPackets := extHighestSN - extStartSN + 1
+	expectedPackets := r.getPacketsExpected(extStartSN, extHighestSN)
 	expectedPacketRate := float64(expectedPackets) / p.Duration
 
 	str := fmt.Sprintf("t: %+v|%+v|%.2fs", p.StartTime.AsTime().Format(time.UnixDate), p.EndTime.AsTime().Format(time.UnixDate), p.Duration)
@@ -796,7 +1151,7 @@ func (r *rtpStatsBase) toProto(
 
 	endTime := r.endTime
 	if endTime.IsZero() {
-		endTime = time.Now()
+		endTime = r.now()
 	}
 	elapsed := endTime.Sub(r.startTime).Seconds()
 	if elapsed == 0.0 {
@@ -809,7 +1164,7 @@ func (r *rtpStatsBase) toProto(
 
 	frameRate := float64(r.frames) / elapsed
 
-	packetsExpected := extHighestSN - extStartSN + 1
+	packetsExpected := r.getPacketsExpected(extStartSN, extHighestSN)
 	packetLostRate := float64(packetsLost) / elapsed
 	packetLostPercentage := float32(packetsLost) / float32(packetsExpected) * 100.0
 
@@ -894,6 +1249,47 @@ func (r *rtpStatsBase) toProto(
 	return p
 }
 
+// toWebRTCStats maps stats to attribute names of W3C WebRTC Statistics, i. e. RTCInboundRtpStreamStats
+// when inbound and RTCOutboundRtpStreamStats when outbound. Times are in seconds, timestamps in
+// milliseconds and byte counts exclude RTP headers as in the specification.
+func (r *rtpStatsBase) toWebRTCStats(isInbound bool, extStartSN, extHighestSN uint64) map[string]interface{} {
+	if r.startTime.IsZero() {
+		return nil
+	}
+
+	packets := r.getTotalPacketsPrimary(extStartSN, extHighestSN)
+	stats := map[string]interface{}{
+		"timestamp": float64(r.now().UnixNano()) / 1e6,
+		"nackCount": r.nacks,
+		"pliCount":  r.plis,
+		"firCount":  r.firs,
+	}
+	if isInbound {
+		stats["id"] = cWebRTCStatsIDInboundRTP
+		stats["type"] = "inbound-rtp"
+		stats["packetsReceived"] = packets
+		stats["bytesReceived"] = r.bytes - r.headerBytes
+		stats["headerBytesReceived"] = r.headerBytes
+		stats["packetsDuplicated"] = r.packetsDuplicate
+		stats["framesReceived"] = r.frames
+		stats["keyFramesDecoded"] = r.keyFrames
+		if !r.highestTime.IsZero() {
+			stats["lastPacketReceivedTimestamp"] = float64(r.highestTime.UnixNano()) / 1e6
+		}
+	} else {
+		stats["id"] = cWebRTCStatsIDOutboundRTP
+		stats["type"] = "outbound-rtp"
+		stats["packetsSent"] = packets
+		stats["bytesSent"] = r.bytes - r.headerBytes
+		stats["headerBytesSent"] = r.headerBytes
+		stats["retransmittedPacketsSent"] = r.packetsDuplicate
+		stats["retransmittedBytesSent"] = r.bytesDuplicate - r.headerBytesDuplicate
+		stats["framesSent"] = r.frames
+		stats["keyFramesEncoded"] = r.keyFrames
+	}
+	return stats
+}
+
 func (r *rtpStatsBase) updateJitter(ets uint64, packetTime time.Time) float64 {
 	// Do not update jitter on multiple packets of same frame.
 	// All packets of a frame have the same time stamp.
@@ -944,7 +1340,7 @@ func (r *rtpStatsBase) getAndResetSnapshot(snapshotID uint32, extStartSN uint64,
 	}
 
 	// snapshot now
-	now := r.getSnapshot(time.Now(), extHighestSN+1)
+	now := r.getSnapshot(r.now(), extHighestSN+1)
 	r.snapshots[idx] = now
 	return &then, &now
 }
@@ -1020,6 +1416,30 @@ func (r *rtpStatsBase) updateGapHistogram(gap int) {
 	}
 }
 
+func (r *rtpStatsBase) updateReorderHistogram(distance int) {
+	switch {
+	case distance <= 0:
+		return
+	case distance <= 5:
+		r.reorderHistogram[0]++
+	case distance <= 20:
+		r.reorderHistogram[1]++
+	case distance <= 50:
+		r.reorderHistogram[2]++
+	default:
+		r.reorderHistogram[3]++
+	}
+}
+
+// GetReorderHistogram returns counts of out-of-order packets binned by how many sequence numbers
+// behind the highest they arrived, bins are 1-5, 6-20, 21-50 and > 50.
+func (r *rtpStatsBase) GetReorderHistogram() [cReorderHistogramNumBins]uint32 {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	return r.reorderHistogram
+}
+
 func (r *rtpStatsBase) initSnapshot(startTime time.Time, extStartSN uint64) snapshot {
 	return snapshot{
 		isValid:    true,
@@ -1055,7 +1475,119 @@ func (r *rtpStatsBase) getSnapshot(startTime time.Time, extStartSN uint64) snaps
 // ----------------------------------
 
 func AggregateRTPStats(statsList []*livekit.RTPStats) *livekit.RTPStats {
-	return utils.AggregateRTPStats(statsList, cGapHistogramNumBins)
+	agg := utils.AggregateRTPStats(statsList, cGapHistogramNumBins)
+	if agg == nil {
+		return nil
+	}
+
+	// utils.AggregateRTPStats compares against last PLI time when picking last FIR time,
+	// re-do it here so that the latest FIR time across all entries is reported
+	lastFir := time.Time{}
+	var packetDrifts, reportDrifts []*livekit.RTPDrift
+	for _, stats := range statsList {
+		if stats == nil {
+			continue
+		}
+		if lastFir.IsZero() || lastFir.Before(stats.LastFir.AsTime()) {
+			lastFir = stats.LastFir.AsTime()
+		}
+		if stats.PacketDrift != nil {
+			packetDrifts = append(packetDrifts, stats.PacketDrift)
+		}
+		if stats.ReportDrift != nil {
+			reportDrifts = append(reportDrifts, stats.ReportDrift)
+		}
+	}
+	agg.LastFir = timestamppb.New(lastFir)
+	agg.PacketDrift = aggregateRTPDrift(packetDrifts)
+	agg.ReportDrift = aggregateRTPDrift(reportDrifts)
+	return agg
+}
+
+// EstimateE2EDelay estimates delay from capture at publisher to reception at subscriber.
+// Publisher leg is the difference between arrival time of latest publisher sender report and its NTP timestamp,
+// subscriber leg is half of RTT measured with sender reports sent to the subscriber.
+// As publisher NTP time is used, the estimate includes clock offset between publisher and server.
+func EstimateE2EDelay(publisherStats, subscriberStats *livekit.RTPStats) (time.Duration, error) {
+	if publisherStats == nil || publisherStats.ReportDrift == nil || publisherStats.RebasedReportDrift == nil {
+		return 0, ErrPublisherReportUnavailable
+	}
+	if subscriberStats == nil || subscriberStats.ReportDrift == nil || subscriberStats.RttCurrent == 0 {
+		return 0, ErrSubscriberReportUnavailable
+	}
+
+	publisherLeg := publisherStats.RebasedReportDrift.EndTime.AsTime().Sub(publisherStats.ReportDrift.EndTime.AsTime())
+	subscriberLeg := time.Duration(subscriberStats.RttCurrent) * time.Millisecond / 2
+	delay := publisherLeg + subscriberLeg
+	if delay < 0 {
+		return 0, ErrE2EDelayInconsistent
+	}
+	return delay, nil
+}
+
+// aggregateRTPDrift aggregates drifts which could be from different SSRCs, layers or codecs. As their
+// RTP time lines are unrelated, per entry drift rates are averaged weighted by duration and applied over
+// the time spanned by all entries. RTP timestamps of the aggregate are not set when there is more than one entry.
+func aggregateRTPDrift(drifts []*livekit.RTPDrift) *livekit.RTPDrift {
+	var (
+		startTime, endTime  time.Time
+		totalDuration       float64
+		totalDriftMs        float64
+		totalRTPClockTicks  float64
+		weightedNominalRate float64
+		numDrifts           int
+		onlyDrift           *livekit.RTPDrift
+	)
+	for _, drift := range drifts {
+		if drift.StartTime == nil || drift.EndTime == nil || drift.Duration <= 0 {
+			continue
+		}
+
+		// nominal clock rate is not part of drift, recover it from the duration covered without drift
+		nominalClockRate := (float64(drift.RtpClockTicks) - float64(drift.DriftSamples)) / drift.Duration
+		if nominalClockRate <= 0.0 {
+			continue
+		}
+
+		if startTime.IsZero() || drift.StartTime.AsTime().Before(startTime) {
+			startTime = drift.StartTime.AsTime()
+		}
+		if endTime.IsZero() || drift.EndTime.AsTime().After(endTime) {
+			endTime = drift.EndTime.AsTime()
+		}
+
+		// drift rate weighted by duration is drift of the entry
+		totalDuration += drift.Duration
+		totalDriftMs += float64(drift.DriftSamples) * 1000 / nominalClockRate
+		totalRTPClockTicks += float64(drift.RtpClockTicks)
+		weightedNominalRate += nominalClockRate * drift.Duration
+		numDrifts++
+		onlyDrift = drift
+	}
+	if numDrifts == 0 {
+		return nil
+	}
+	if numDrifts == 1 {
+		return proto.Clone(onlyDrift).(*livekit.RTPDrift)
+	}
+
+	elapsed := endTime.Sub(startTime).Seconds()
+	if elapsed <= 0.0 {
+		return nil
+	}
+
+	driftMs := totalDriftMs / totalDuration * elapsed
+	clockRate := totalRTPClockTicks / totalDuration
+	nominalClockRate := weightedNominalRate / totalDuration
+	return &livekit.RTPDrift{
+		StartTime:     timestamppb.New(startTime),
+		EndTime:       timestamppb.New(endTime),
+		Duration:      elapsed,
+		RtpClockTicks: uint64(math.Round(clockRate * elapsed)),
+		DriftSamples:  int64(math.Round(driftMs * nominalClockRate / 1000)),
+		DriftMs:       driftMs,
+		ClockRate:     clockRate,
+	}
 }
 
 func AggregateRTPDeltaInfo(deltaInfoList []*RTPDeltaInfo) *RTPDeltaInfo {
@@ -1087,6 +1619,11 @@ func AggregateRTPDeltaInfo(deltaInfoList []*RTPDeltaInfo) *RTPDeltaInfo {
 	maxRtt := uint32(0)
 	maxJitter := float64(0)
 
+	// averages are weighted by duration of delta, a delta without average contributes its max
+	weightedRtt := float64(0)
+	weightedJitter := float64(0)
+	totalDuration := time.Duration(0)
+
 	nacks := uint32(0)
 	plis := uint32(0)
 	firs := uint32(0)
@@ -1130,6 +1667,20 @@ func AggregateRTPDeltaInfo(deltaInfoList []*RTPDeltaInfo) *RTPDeltaInfo {
 			maxJitter = deltaInfo.JitterMax
 		}
 
+		if duration := deltaInfo.Duration(); duration > 0 {
+			rtt := deltaInfo.RttAvg
+			if rtt == 0 {
+				rtt = deltaInfo.RttMax
+			}
+			jitter := deltaInfo.JitterAvg
+			if jitter == 0 {
+				jitter = deltaInfo.JitterMax
+			}
+			weightedRtt += float64(rtt) * duration.Seconds()
+			weightedJitter += jitter * duration.Seconds()
+			totalDuration += duration
+		}
+
 		nacks += deltaInfo.Nacks
 		plis += deltaInfo.Plis
 		firs += deltaInfo.Firs
@@ -1138,6 +1689,13 @@ func AggregateRTPDeltaInfo(deltaInfoList []*RTPDeltaInfo) *RTPDeltaInfo {
 		return nil
 	}
 
+	avgRtt := uint32(0)
+	avgJitter := float64(0)
+	if totalDuration > 0 {
+		avgRtt = uint32(math.Round(weightedRtt / totalDuration.Seconds()))
+		avgJitter = weightedJitter / totalDuration.Seconds()
+	}
+
 	return &RTPDeltaInfo{
 		StartTime:            startTime,
 		EndTime:              endTime,
@@ -1159,6 +1717,8 @@ func AggregateRTPDeltaInfo(deltaInfoList []*RTPDeltaInfo) *RTPDeltaInfo {
 		Nacks:                nacks,
 		Plis:                 plis,
 		Firs:                 firs,
+		RttAvg:               avgRtt,
+		JitterAvg:            avgJitter,
 	}
 }
 
@@ -0,0 +1,330 @@
+// Copyright 2024 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package buffer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/livekit/protocol/livekit"
+	"github.com/livekit/protocol/logger"
+)
+
+func TestAggregateRTPStatsDrift(t *testing.T) {
+	t0 := time.Now()
+	drift1 := &livekit.RTPDrift{
+		StartTime:      timestamppb.New(t0),
+		EndTime:        timestamppb.New(t0.Add(10 * time.Second)),
+		Duration:       10,
+		StartTimestamp: 1000,
+		EndTimestamp:   1000 + 900000,
+		RtpClockTicks:  900000,
+	}
+	drift2 := &livekit.RTPDrift{
+		StartTime:      timestamppb.New(t0.Add(5 * time.Second)),
+		EndTime:        timestamppb.New(t0.Add(20 * time.Second)),
+		Duration:       15,
+		StartTimestamp: 1000 + 450000,
+		EndTimestamp:   1000 + 1800000 + 90,
+		RtpClockTicks:  1350090,
+		DriftSamples:   90,
+	}
+	statsList := []*livekit.RTPStats{
+		{
+			StartTime:   timestamppb.New(t0),
+			EndTime:     timestamppb.New(t0.Add(10 * time.Second)),
+			Duration:    10,
+			PacketDrift: drift1,
+			ReportDrift: drift1,
+		},
+		{
+			StartTime:   timestamppb.New(t0.Add(5 * time.Second)),
+			EndTime:     timestamppb.New(t0.Add(20 * time.Second)),
+			Duration:    15,
+			PacketDrift: drift2,
+		},
+	}
+
+	agg := AggregateRTPStats(statsList)
+	require.NotNil(t, agg)
+
+	// 1 ms drift over 25 seconds of entries applied over the 20 seconds spanned
+	require.NotNil(t, agg.PacketDrift)
+	require.Zero(t, agg.PacketDrift.StartTimestamp)
+	require.Zero(t, agg.PacketDrift.EndTimestamp)
+	require.InDelta(t, 20.0, agg.PacketDrift.Duration, 1e-6)
+	require.InDelta(t, 0.8, agg.PacketDrift.DriftMs, 1e-6)
+	require.Equal(t, int64(72), agg.PacketDrift.DriftSamples)
+	require.Equal(t, uint64(1800072), agg.PacketDrift.RtpClockTicks)
+
+	// single entry aggregates to itself
+	require.NotNil(t, agg.ReportDrift)
+	require.Equal(t, int64(0), agg.ReportDrift.DriftSamples)
+	require.Equal(t, uint64(900000), agg.ReportDrift.RtpClockTicks)
+}
+
+func TestAggregateRTPStatsDriftUnrelatedTimelines(t *testing.T) {
+	t0 := time.Now()
+	// same drift rate on two streams with unrelated timestamp bases and clock rates
+	drifts := []*livekit.RTPDrift{
+		{
+			StartTime:      timestamppb.New(t0),
+			EndTime:        timestamppb.New(t0.Add(10 * time.Second)),
+			Duration:       10,
+			StartTimestamp: 3_000_000_000,
+			EndTimestamp:   3_000_000_000 + 900090,
+			RtpClockTicks:  900090,
+			DriftSamples:   90,
+		},
+		{
+			StartTime:      timestamppb.New(t0),
+			EndTime:        timestamppb.New(t0.Add(10 * time.Second)),
+			Duration:       10,
+			StartTimestamp: 1000,
+			EndTimestamp:   1000 + 480048,
+			RtpClockTicks:  480048,
+			DriftSamples:   48,
+		},
+	}
+	statsList := []*livekit.RTPStats{
+		{
+			StartTime:   timestamppb.New(t0),
+			EndTime:     timestamppb.New(t0.Add(10 * time.Second)),
+			Duration:    10,
+			PacketDrift: drifts[0],
+		},
+		{
+			StartTime:   timestamppb.New(t0),
+			EndTime:     timestamppb.New(t0.Add(10 * time.Second)),
+			Duration:    10,
+			PacketDrift: drifts[1],
+		},
+	}
+
+	agg := AggregateRTPStats(statsList)
+	require.NotNil(t, agg.PacketDrift)
+	require.InDelta(t, 10.0, agg.PacketDrift.Duration, 1e-6)
+	require.InDelta(t, 1.0, agg.PacketDrift.DriftMs, 1e-6)
+}
+
+func TestNamedSnapshotId(t *testing.T) {
+	r := NewRTPStatsReceiver(RTPStatsParams{ClockRate: 90000, Logger: logger.GetLogger()})
+
+	unnamed := r.NewSnapshotId()
+	id, err := r.NewNamedSnapshotId("connection-quality")
+	require.NoError(t, err)
+	require.NotEqual(t, unnamed, id)
+
+	require.Equal(t, "connection-quality", r.GetSnapshotName(id))
+	require.Empty(t, r.GetSnapshotName(unnamed))
+
+	_, err = r.NewNamedSnapshotId("connection-quality")
+	require.ErrorIs(t, err, ErrSnapshotNameExists)
+
+	_, err = r.NewNamedSnapshotId("")
+	require.ErrorIs(t, err, ErrSnapshotNameEmpty)
+}
+
+func TestSnapshotHistory(t *testing.T) {
+	r := NewRTPStatsReceiver(RTPStatsParams{
+		ClockRate:            90000,
+		Logger:               logger.GetLogger(),
+		SnapshotHistoryDepth: 3,
+	})
+
+	sequenceNumber := uint16(1000)
+	r.Update(time.Now(), sequenceNumber, 1000, true, 12, 1000, 0)
+	id := r.NewSnapshotId()
+	require.Empty(t, r.GetSnapshotHistory(id))
+
+	for i := 1; i <= 5; i++ {
+		for j := 0; j < i; j++ {
+			sequenceNumber++
+			r.Update(time.Now(), sequenceNumber, 1000+uint32(sequenceNumber), true, 12, 1000, 0)
+		}
+		require.NotNil(t, r.DeltaInfo(id))
+	}
+
+	history := r.GetSnapshotHistory(id)
+	require.Len(t, history, 3)
+	for i, deltaInfo := range history {
+		require.Equal(t, uint32(i+3), deltaInfo.Packets)
+	}
+
+	// reading history does not consume the snapshot
+	sequenceNumber++
+	r.Update(time.Now(), sequenceNumber, 1000+uint32(sequenceNumber), true, 12, 1000, 0)
+	require.Len(t, r.GetSnapshotHistory(id), 3)
+	require.Equal(t, uint32(1), r.DeltaInfo(id).Packets)
+}
+
+func TestBandwidthUtilizationRatio(t *testing.T) {
+	r := NewRTPStatsSimulator(RTPStatsParams{ClockRate: 90000, Logger: logger.GetLogger()}, time.Unix(1700000000, 0))
+	require.Zero(t, r.GetBandwidthUtilizationRatio())
+
+	r.Update(r.Now(), 100, 9000, false, 12, 988, 0)
+	r.UpdateBandwidthEstimate(100_000)
+	require.Zero(t, r.GetBandwidthUtilizationRatio())
+
+	// 10 packets of 1000 bytes in one second -> 80 kbps
+	sn := uint16(100)
+	for i := 0; i < 10; i++ {
+		sn++
+		r.AdvanceClock(100 * time.Millisecond)
+		r.Update(r.Now(), sn, uint32(sn)*900, false, 12, 988, 0)
+	}
+	r.UpdateBandwidthEstimate(100_000)
+	require.InDelta(t, 0.8, r.GetBandwidthUtilizationRatio(), 1e-6)
+
+	for i := 0; i < 10; i++ {
+		sn++
+		r.AdvanceClock(100 * time.Millisecond)
+		r.Update(r.Now(), sn, uint32(sn)*900, false, 12, 988, 0)
+	}
+	r.UpdateBandwidthEstimate(40_000)
+	require.InDelta(t, 2.0, r.GetBandwidthUtilizationRatio(), 1e-6)
+}
+
+func TestEstimateE2EDelay(t *testing.T) {
+	t0 := time.Now()
+	publisherStats := &livekit.RTPStats{
+		ReportDrift: &livekit.RTPDrift{
+			EndTime: timestamppb.New(t0),
+		},
+		RebasedReportDrift: &livekit.RTPDrift{
+			EndTime: timestamppb.New(t0.Add(30 * time.Millisecond)),
+		},
+	}
+	subscriberStats := &livekit.RTPStats{
+		ReportDrift: &livekit.RTPDrift{
+			EndTime: timestamppb.New(t0),
+		},
+		RttCurrent: 40,
+	}
+
+	delay, err := EstimateE2EDelay(publisherStats, subscriberStats)
+	require.NoError(t, err)
+	require.Equal(t, 50*time.Millisecond, delay)
+
+	_, err = EstimateE2EDelay(&livekit.RTPStats{}, subscriberStats)
+	require.ErrorIs(t, err, ErrPublisherReportUnavailable)
+
+	_, err = EstimateE2EDelay(publisherStats, &livekit.RTPStats{RttCurrent: 40})
+	require.ErrorIs(t, err, ErrSubscriberReportUnavailable)
+
+	publisherStats.RebasedReportDrift.EndTime = timestamppb.New(t0.Add(-time.Second))
+	_, err = EstimateE2EDelay(publisherStats, subscriberStats)
+	require.ErrorIs(t, err, ErrE2EDelayInconsistent)
+}
+
+func TestKeyFrameIntervalStats(t *testing.T) {
+	r := NewRTPStatsSimulator(RTPStatsParams{ClockRate: 90000, Logger: logger.GetLogger()}, time.Unix(1700000000, 0))
+
+	p50, p95, p99 := r.GetKeyFrameIntervalStats()
+	require.Zero(t, p50)
+	require.Zero(t, p95)
+	require.Zero(t, p99)
+
+	r.UpdateKeyFrame(1)
+	// intervals of 1s .. 100s, older ones pushed out of history
+	for i := 0; i < 10; i++ {
+		r.AdvanceClock(time.Hour)
+		r.UpdateKeyFrame(1)
+	}
+	for i := 1; i <= 100; i++ {
+		r.AdvanceClock(time.Duration(i) * time.Second)
+		r.UpdateKeyFrame(1)
+	}
+
+	p50, p95, p99 = r.GetKeyFrameIntervalStats()
+	require.Equal(t, 50*time.Second, p50)
+	require.Equal(t, 95*time.Second, p95)
+	require.Equal(t, 99*time.Second, p99)
+}
+
+func TestBurstLoss(t *testing.T) {
+	r := NewRTPStatsSimulator(RTPStatsParams{ClockRate: 90000, Logger: logger.GetLogger()}, time.Unix(1700000000, 0))
+	require.Zero(t, r.GetBurstLossRate())
+
+	sn := uint16(100)
+	ts := uint32(9000)
+	packetTime := r.Now()
+	send := func(numLost int) {
+		sn += uint16(numLost) + 1
+		ts += uint32(numLost+1) * 3000
+		packetTime = packetTime.Add(time.Duration(numLost+1) * 33 * time.Millisecond)
+		r.Update(packetTime, sn, ts, false, 12, 988, 0)
+	}
+
+	r.Update(packetTime, sn, ts, false, 12, 988, 0)
+	r.AdvanceClock(30 * time.Second)
+	send(0)
+	send(1) // isolated losses are not bursts
+	send(cBurstLossMinPackets - 1)
+	count, _ := r.GetBurstLossStats()
+	require.Zero(t, count)
+
+	send(cBurstLossMinPackets)
+	r.AdvanceClock(30 * time.Second)
+	send(0)
+	send(10)
+
+	count, maxDuration := r.GetBurstLossStats()
+	require.Equal(t, uint32(2), count)
+	require.Equal(t, 11*33*time.Millisecond, maxDuration)
+	require.InDelta(t, 2.0, r.GetBurstLossRate(), 1e-6)
+
+	r.AdvanceClock(60 * time.Second)
+	require.InDelta(t, 1.0, r.GetBurstLossRate(), 1e-6)
+}
+
+func TestDeltaInfoRates(t *testing.T) {
+	now := time.Now()
+	deltaInfo := &RTPDeltaInfo{
+		StartTime: now,
+		EndTime:   now,
+		Packets:   100,
+		Bytes:     125_000,
+	}
+	require.Zero(t, deltaInfo.BitrateKbps())
+	require.Zero(t, deltaInfo.PacketRatePps())
+
+	deltaInfo.EndTime = now.Add(2 * time.Second)
+	require.Equal(t, 2*time.Second, deltaInfo.Duration())
+	require.InDelta(t, 500.0, deltaInfo.BitrateKbps(), 1e-6)
+	require.InDelta(t, 50.0, deltaInfo.PacketRatePps(), 1e-6)
+}
+
+func TestAggregateRTPDeltaInfoAverages(t *testing.T) {
+	t0 := time.Now()
+	agg := AggregateRTPDeltaInfo([]*RTPDeltaInfo{
+		{StartTime: t0, EndTime: t0.Add(3 * time.Second), RttMax: 100, JitterMax: 10},
+		{StartTime: t0.Add(3 * time.Second), EndTime: t0.Add(4 * time.Second), RttMax: 500, JitterMax: 50},
+		// aggregated delta contributes its average
+		{StartTime: t0.Add(4 * time.Second), EndTime: t0.Add(8 * time.Second), RttMax: 400, JitterMax: 40, RttAvg: 50, JitterAvg: 5},
+		// no duration, no weight
+		{StartTime: t0.Add(8 * time.Second), EndTime: t0.Add(8 * time.Second), RttMax: 1000, JitterMax: 100},
+	})
+	require.NotNil(t, agg)
+	require.EqualValues(t, 1000, agg.RttMax)
+	require.Equal(t, 100.0, agg.JitterMax)
+	// (100 * 3 + 500 * 1 + 50 * 4) / 8
+	require.EqualValues(t, 125, agg.RttAvg)
+	// (10 * 3 + 50 * 1 + 5 * 4) / 8
+	require.InDelta(t, 12.5, agg.JitterAvg, 1e-6)
+}
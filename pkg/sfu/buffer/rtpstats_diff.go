@@ -0,0 +1,150 @@
+// Copyright 2024 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package buffer
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"github.com/livekit/protocol/livekit"
+)
+
+// RTPStatsFieldDiff is the change of a numeric RTPStats field between two snapshots.
+type RTPStatsFieldDiff struct {
+	// proto field name
+	Field  string
+	Before float64
+	After  float64
+	// signed change, After - Before
+	Delta float64
+	// change relative to Before in percent, 0 when both are 0, +/-Inf when only Before is 0
+	PercentChange float64
+}
+
+// RTPStatsDiff holds the field level change between two RTPStats snapshots,
+// one entry for every numeric field in proto field order.
+type RTPStatsDiff struct {
+	Fields []RTPStatsFieldDiff
+}
+
+// DiffRTPStats returns the change of every numeric field from before to after.
+// A nil snapshot is treated as all zeroes, i. e. a nil before gives the first measurement as delta.
+func DiffRTPStats(before, after *livekit.RTPStats) *RTPStatsDiff {
+	if before == nil {
+		before = &livekit.RTPStats{}
+	}
+	if after == nil {
+		after = &livekit.RTPStats{}
+	}
+
+	b := before.ProtoReflect()
+	a := after.ProtoReflect()
+	fields := b.Descriptor().Fields()
+
+	diff := &RTPStatsDiff{}
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		if fd.IsList() || fd.IsMap() {
+			continue
+		}
+
+		bv, ok := numericValue(fd, b.Get(fd))
+		if !ok {
+			continue
+		}
+		av, _ := numericValue(fd, a.Get(fd))
+
+		diff.Fields = append(diff.Fields, RTPStatsFieldDiff{
+			Field:         string(fd.Name()),
+			Before:        bv,
+			After:         av,
+			Delta:         av - bv,
+			PercentChange: percentChange(bv, av),
+		})
+	}
+	return diff
+}
+
+// Get returns the diff of the named proto field.
+func (d *RTPStatsDiff) Get(field string) (RTPStatsFieldDiff, bool) {
+	for _, f := range d.Fields {
+		if f.Field == field {
+			return f, true
+		}
+	}
+	return RTPStatsFieldDiff{}, false
+}
+
+// String returns the changed fields, one per line, as "field: before -> after (delta, percent change)".
+func (d *RTPStatsDiff) String() string {
+	var sb strings.Builder
+	for _, f := range d.Fields {
+		if f.Delta == 0 {
+			continue
+		}
+
+		if sb.Len() != 0 {
+			sb.WriteString("\n")
+		}
+		delta := strconv.FormatFloat(f.Delta, 'f', -1, 64)
+		if f.Delta > 0 {
+			delta = "+" + delta
+		}
+		sb.WriteString(fmt.Sprintf(
+			"%s: %s -> %s (%s, %+.2f%%)",
+			f.Field,
+			strconv.FormatFloat(f.Before, 'f', -1, 64),
+			strconv.FormatFloat(f.After, 'f', -1, 64),
+			delta,
+			f.PercentChange,
+		))
+	}
+	if sb.Len() == 0 {
+		return "no change"
+	}
+	return sb.String()
+}
+
+func numericValue(fd protoreflect.FieldDescriptor, v protoreflect.Value) (float64, bool) {
+	switch fd.Kind() {
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind,
+		protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		return float64(v.Int()), true
+
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind,
+		protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return float64(v.Uint()), true
+
+	case protoreflect.FloatKind, protoreflect.DoubleKind:
+		return v.Float(), true
+	}
+
+	return 0, false
+}
+
+func percentChange(before, after float64) float64 {
+	switch {
+	case before == after:
+		return 0
+	case before == 0:
+		return math.Inf(int(math.Copysign(1, after)))
+	default:
+		return (after - before) / math.Abs(before) * 100.0
+	}
+}
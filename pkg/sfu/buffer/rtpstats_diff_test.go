@@ -0,0 +1,103 @@
+// Copyright 2024 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package buffer
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/livekit/protocol/livekit"
+)
+
+func TestDiffRTPStats(t *testing.T) {
+	before := &livekit.RTPStats{
+		Packets:              1000,
+		Bytes:                100000,
+		PacketsLost:          20,
+		PacketLossPercentage: 2.0,
+		JitterCurrent:        10.0,
+		RttCurrent:           50,
+	}
+	after := &livekit.RTPStats{
+		Packets:              1500,
+		Bytes:                1500000,
+		PacketsLost:          10,
+		PacketLossPercentage: 1.0,
+		JitterCurrent:        10.0,
+		RttCurrent:           40,
+	}
+
+	diff := DiffRTPStats(before, after)
+	require.NotEmpty(t, diff.Fields)
+	// every numeric field is present, non-numeric ones are not
+	_, ok := diff.Get("frames")
+	require.True(t, ok)
+	_, ok = diff.Get("start_time")
+	require.False(t, ok)
+	_, ok = diff.Get("gap_histogram")
+	require.False(t, ok)
+
+	packets, ok := diff.Get("packets")
+	require.True(t, ok)
+	require.Equal(t, RTPStatsFieldDiff{Field: "packets", Before: 1000, After: 1500, Delta: 500, PercentChange: 50}, packets)
+
+	lost, _ := diff.Get("packets_lost")
+	require.Equal(t, -10.0, lost.Delta)
+	require.Equal(t, -50.0, lost.PercentChange)
+
+	lossPercentage, _ := diff.Get("packet_loss_percentage")
+	require.InDelta(t, -1.0, lossPercentage.Delta, 1e-6)
+
+	rtt, _ := diff.Get("rtt_current")
+	require.Equal(t, -10.0, rtt.Delta)
+	require.Equal(t, -20.0, rtt.PercentChange)
+
+	jitter, _ := diff.Get("jitter_current")
+	require.Zero(t, jitter.Delta)
+	require.Zero(t, jitter.PercentChange)
+
+	require.Contains(t, diff.String(), "packets: 1000 -> 1500 (+500, +50.00%)")
+	require.Contains(t, diff.String(), "bytes: 100000 -> 1500000 (+1400000, +1400.00%)")
+	require.Contains(t, diff.String(), "rtt_current: 50 -> 40 (-10, -20.00%)")
+	require.NotContains(t, diff.String(), "jitter_current")
+
+	require.Equal(t, "no change", DiffRTPStats(after, after).String())
+}
+
+func TestDiffRTPStats_ZeroBefore(t *testing.T) {
+	after := &livekit.RTPStats{
+		Packets: 100,
+		Bytes:   10000,
+	}
+
+	// first measurement, nil and zero baselines are the same
+	for _, before := range []*livekit.RTPStats{nil, {}} {
+		diff := DiffRTPStats(before, after)
+
+		packets, ok := diff.Get("packets")
+		require.True(t, ok)
+		require.Equal(t, 0.0, packets.Before)
+		require.Equal(t, 100.0, packets.Delta)
+		require.True(t, math.IsInf(packets.PercentChange, 1))
+
+		lost, _ := diff.Get("packets_lost")
+		require.Zero(t, lost.Delta)
+		require.Zero(t, lost.PercentChange)
+
+		require.Contains(t, diff.String(), "bytes: 0 -> 10000 (+10000, +Inf%)")
+	}
+}
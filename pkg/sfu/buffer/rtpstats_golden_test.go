@@ -0,0 +1,193 @@
+// Copyright 2024 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package buffer
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/livekit/protocol/livekit"
+)
+
+// run with UPDATE_GOLDEN=1 to regenerate files in testdata/
+var updateGolden = os.Getenv("UPDATE_GOLDEN") != ""
+
+var goldenStartTime = time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+func checkGolden(t *testing.T, name string, actual []byte) {
+	t.Helper()
+
+	path := filepath.Join("testdata", name+".golden.json")
+	if updateGolden {
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, append(actual, '\n'), 0644))
+		return
+	}
+
+	expected, err := os.ReadFile(path)
+	require.NoError(t, err, "missing golden file, run with UPDATE_GOLDEN=1 to create")
+	require.JSONEq(t, string(expected), string(actual))
+}
+
+func goldenRTPStats(offset time.Duration, duration time.Duration, packets uint32, packetsLost uint32) *livekit.RTPStats {
+	start := goldenStartTime.Add(offset)
+	end := start.Add(duration)
+	return &livekit.RTPStats{
+		StartTime:        timestamppb.New(start),
+		EndTime:          timestamppb.New(end),
+		Duration:         duration.Seconds(),
+		Packets:          packets,
+		Bytes:            uint64(packets) * 1000,
+		HeaderBytes:      uint64(packets) * 12,
+		PacketsLost:      packetsLost,
+		Frames:           packets / 4,
+		KeyFrames:        1,
+		LastKeyFrame:     timestamppb.New(start),
+		JitterCurrent:    10,
+		JitterMax:        20,
+		GapHistogram:     map[int32]uint32{1: packetsLost},
+		Nacks:            packetsLost,
+		Plis:             1,
+		LastPli:          timestamppb.New(start.Add(duration / 2)),
+		LastLayerLockPli: timestamppb.New(start),
+		LastFir:          timestamppb.New(start),
+		RttCurrent:       50,
+		RttMax:           100,
+	}
+}
+
+func TestAggregateRTPStatsGolden(t *testing.T) {
+	testCases := []struct {
+		name      string
+		statsList []*livekit.RTPStats
+	}{
+		{
+			name: "single",
+			statsList: []*livekit.RTPStats{
+				goldenRTPStats(0, 10*time.Second, 1000, 10),
+			},
+		},
+		{
+			name: "non_overlapping",
+			statsList: []*livekit.RTPStats{
+				goldenRTPStats(0, 10*time.Second, 1000, 10),
+				goldenRTPStats(20*time.Second, 10*time.Second, 500, 0),
+			},
+		},
+		{
+			name: "zero_packets",
+			statsList: []*livekit.RTPStats{
+				goldenRTPStats(0, 10*time.Second, 0, 0),
+				goldenRTPStats(10*time.Second, 10*time.Second, 0, 0),
+			},
+		},
+		{
+			name: "last_fir",
+			statsList: func() []*livekit.RTPStats {
+				// second entry has the latest FIR, but an earlier PLI than the first entry
+				first := goldenRTPStats(0, 10*time.Second, 1000, 10)
+				first.Firs = 1
+				first.LastPli = timestamppb.New(goldenStartTime.Add(9 * time.Second))
+				first.LastFir = timestamppb.New(goldenStartTime.Add(2 * time.Second))
+
+				second := goldenRTPStats(5*time.Second, 10*time.Second, 1000, 10)
+				second.Firs = 2
+				second.LastPli = timestamppb.New(goldenStartTime.Add(6 * time.Second))
+				second.LastFir = timestamppb.New(goldenStartTime.Add(8 * time.Second))
+				return []*livekit.RTPStats{first, second}
+			}(),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			agg := AggregateRTPStats(tc.statsList)
+			require.NotNil(t, agg)
+
+			marshalled, err := protojson.Marshal(agg)
+			require.NoError(t, err)
+
+			// protojson output is deliberately unstable in whitespace, re-indent for readable golden files
+			var actual bytes.Buffer
+			require.NoError(t, json.Indent(&actual, marshalled, "", "  "))
+			checkGolden(t, "aggregate_rtp_stats_"+tc.name, actual.Bytes())
+		})
+	}
+}
+
+func goldenRTPDeltaInfo(offset time.Duration, duration time.Duration, packets uint32, packetsLost uint32) *RTPDeltaInfo {
+	start := goldenStartTime.Add(offset)
+	return &RTPDeltaInfo{
+		StartTime:   start,
+		EndTime:     start.Add(duration),
+		Packets:     packets,
+		Bytes:       uint64(packets) * 1000,
+		HeaderBytes: uint64(packets) * 12,
+		PacketsLost: packetsLost,
+		Frames:      packets / 4,
+		RttMax:      100,
+		JitterMax:   20,
+		Nacks:       packetsLost,
+		Plis:        1,
+	}
+}
+
+func TestAggregateRTPDeltaInfoGolden(t *testing.T) {
+	testCases := []struct {
+		name          string
+		deltaInfoList []*RTPDeltaInfo
+	}{
+		{
+			name: "single",
+			deltaInfoList: []*RTPDeltaInfo{
+				goldenRTPDeltaInfo(0, 5*time.Second, 250, 5),
+			},
+		},
+		{
+			name: "non_overlapping",
+			deltaInfoList: []*RTPDeltaInfo{
+				goldenRTPDeltaInfo(0, 5*time.Second, 250, 5),
+				goldenRTPDeltaInfo(10*time.Second, 5*time.Second, 100, 0),
+			},
+		},
+		{
+			name: "zero_packets",
+			deltaInfoList: []*RTPDeltaInfo{
+				goldenRTPDeltaInfo(0, 5*time.Second, 0, 0),
+				nil,
+				goldenRTPDeltaInfo(5*time.Second, 5*time.Second, 0, 0),
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			agg := AggregateRTPDeltaInfo(tc.deltaInfoList)
+			require.NotNil(t, agg)
+
+			actual, err := json.MarshalIndent(agg, "", "  ")
+			require.NoError(t, err)
+			checkGolden(t, "aggregate_rtp_delta_info_"+tc.name, actual)
+		})
+	}
+}
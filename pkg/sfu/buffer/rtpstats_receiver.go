@@ -23,6 +23,7 @@ import (
 	"go.uber.org/zap/zapcore"
 
 	"github.com/livekit/livekit-server/pkg/sfu/utils"
+	"github.com/livekit/mediatransportutil"
 	"github.com/livekit/protocol/livekit"
 	protoutils "github.com/livekit/protocol/utils"
 )
@@ -115,6 +116,9 @@ type RTPStatsReceiver struct {
 	outOfOrderSenderReportCount int
 	largeJumpCount              int
 	largeJumpNegativeCount      int
+
+	gapAlertThreshold uint64
+	onGapAlert        func(gap uint64)
 }
 
 func NewRTPStatsReceiver(params RTPStatsParams) *RTPStatsReceiver {
@@ -133,6 +137,24 @@ func (r *RTPStatsReceiver) NewSnapshotId() uint32 {
 	return r.newSnapshotID(r.sequenceNumber.GetExtendedHighest())
 }
 
+func (r *RTPStatsReceiver) NewNamedSnapshotId(name string) (uint32, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	return r.newNamedSnapshotID(name, r.sequenceNumber.GetExtendedHighest())
+}
+
+// SetGapAlertThreshold sets a callback which is invoked from Update when an in-order packet
+// arrives after more than gap missing sequence numbers. The callback receives the number of
+// missing sequence numbers and is invoked outside the stats lock. A nil callback disables the alert.
+func (r *RTPStatsReceiver) SetGapAlertThreshold(gap uint64, fn func(gap uint64)) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.gapAlertThreshold = gap
+	r.onGapAlert = fn
+}
+
 func (r *RTPStatsReceiver) Update(
 	packetTime time.Time,
 	sequenceNumber uint16,
@@ -142,6 +164,15 @@ func (r *RTPStatsReceiver) Update(
 	payloadSize int,
 	paddingSize int,
 ) (flowState RTPFlowState) {
+	var onGapAlert func(gap uint64)
+	var missing uint64
+	defer func() {
+		// notify after releasing the lock
+		if onGapAlert != nil {
+			onGapAlert(missing)
+		}
+	}()
+
 	r.lock.Lock()
 	defer r.lock.Unlock()
 
@@ -161,7 +192,7 @@ func (r *RTPStatsReceiver) Update(
 
 		r.initialized = true
 
-		r.startTime = time.Now()
+		r.startTime = r.now()
 
 		r.firstTime = packetTime
 		r.highestTime = packetTime
@@ -231,6 +262,7 @@ func (r *RTPStatsReceiver) Update(
 
 		if gapSN != 0 {
 			r.packetsOutOfOrder++
+			r.updateReorderHistogram(int(-gapSN))
 		}
 
 		if r.isInRange(resSN.ExtendedVal, resSN.PreExtendedHighest) {
@@ -268,6 +300,10 @@ func (r *RTPStatsReceiver) Update(
 
 		r.history.Set(resSN.ExtendedVal)
 
+		if gapSN > cBurstLossMinPackets {
+			r.updateBurstLossLocked(resSN.PreExtendedHighest+1, resSN.ExtendedVal-1, packetTime.Sub(r.highestTime))
+		}
+
 		if timestamp != uint32(resTS.PreExtendedHighest) {
 			// update only on first packet as same timestamp could be in multiple packets.
 			// NOTE: this may not be the first packet with this time stamp if there is packet loss.
@@ -278,6 +314,11 @@ func (r *RTPStatsReceiver) Update(
 			flowState.HasLoss = true
 			flowState.LossStartInclusive = resSN.PreExtendedHighest + 1
 			flowState.LossEndExclusive = resSN.ExtendedVal
+
+			if r.onGapAlert != nil && uint64(gapSN-1) > r.gapAlertThreshold {
+				onGapAlert = r.onGapAlert
+				missing = uint64(gapSN - 1)
+			}
 		}
 		flowState.ExtSequenceNumber = resSN.ExtendedVal
 		flowState.ExtTimestamp = resTS.ExtendedVal
@@ -302,14 +343,43 @@ func (r *RTPStatsReceiver) Update(
 	return
 }
 
-func (r *RTPStatsReceiver) SetRtcpSenderReportData(srData *RTCPSenderReportData) bool {
+// UpdateFromSenderReport processes a sender report received from the publisher at receivedAt.
+// It extends the RTP timestamp and packet count, correlates NTP time to arrival time to
+// estimate propagation delay and adjusts first packet time, all under a single lock.
+// Reports received before the first packet and anachronous/out-of-order reports are rejected.
+func (r *RTPStatsReceiver) UpdateFromSenderReport(sr *rtcp.SenderReport, receivedAt time.Time) error {
+	if sr == nil {
+		return ErrSenderReportInvalid
+	}
+
 	r.lock.Lock()
 	defer r.lock.Unlock()
 
-	if srData == nil || !r.initialized {
+	return r.updateFromSenderReportLocked(&RTCPSenderReportData{
+		RTPTimestamp: sr.RTPTime,
+		NTPTimestamp: mediatransportutil.NtpTime(sr.NTPTime),
+		PacketCount:  sr.PacketCount,
+		At:           receivedAt,
+	})
+}
+
+// Deprecated: use UpdateFromSenderReport, sender report data set here does not carry packet count.
+func (r *RTPStatsReceiver) SetRtcpSenderReportData(srData *RTCPSenderReportData) bool {
+	if srData == nil {
 		return false
 	}
 
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	return r.updateFromSenderReportLocked(srData) == nil
+}
+
+func (r *RTPStatsReceiver) updateFromSenderReportLocked(srData *RTCPSenderReportData) error {
+	if !r.initialized {
+		return ErrSenderReportBeforeFirstPacket
+	}
+
 	// prevent against extreme case of anachronous sender reports
 	if r.srNewest != nil && r.srNewest.NTPTimestamp > srData.NTPTimestamp {
 		r.logger.Infow(
@@ -318,7 +388,7 @@ func (r *RTPStatsReceiver) SetRtcpSenderReportData(srData *RTCPSenderReportData)
 			"last", r.srNewest,
 			"current", srData,
 		)
-		return false
+		return ErrSenderReportAnachronous
 	}
 
 	tsCycles := uint64(0)
@@ -329,7 +399,7 @@ func (r *RTPStatsReceiver) SetRtcpSenderReportData(srData *RTCPSenderReportData)
 		expectedRTPTimestampExt := r.srNewest.RTPTimestampExt + uint64(timeSinceLastReport.Nanoseconds()*int64(r.params.ClockRate)/1e9)
 		lbound := expectedRTPTimestampExt - uint64(cReportSlack*float64(r.params.ClockRate))
 		ubound := expectedRTPTimestampExt + uint64(cReportSlack*float64(r.params.ClockRate))
-		isInRange := (srData.RTPTimestamp-uint32(lbound) < (1 << 31)) && (uint32(ubound)-srData.RTPTimestamp < (1 << 31))
+		isInRange := utils.Distance(uint32(lbound), srData.RTPTimestamp) >= 0 && utils.Distance(srData.RTPTimestamp, uint32(ubound)) >= 0
 		if isInRange {
 			lbTSCycles := lbound & 0xFFFF_FFFF_0000_0000
 			ubTSCycles := ubound & 0xFFFF_FFFF_0000_0000
@@ -347,12 +417,12 @@ func (r *RTPStatsReceiver) SetRtcpSenderReportData(srData *RTCPSenderReportData)
 			// ideally this method should not be required, but there are clients
 			// negotiating one clock rate, but actually send media at a different rate.
 			tsCycles = r.srNewest.RTPTimestampExt & 0xFFFF_FFFF_0000_0000
-			if (srData.RTPTimestamp-r.srNewest.RTPTimestamp) < (1<<31) && srData.RTPTimestamp < r.srNewest.RTPTimestamp {
+			if utils.Distance(r.srNewest.RTPTimestamp, srData.RTPTimestamp) >= 0 && srData.RTPTimestamp < r.srNewest.RTPTimestamp {
 				tsCycles += (1 << 32)
 			}
 
 			if tsCycles >= (1 << 32) {
-				if (srData.RTPTimestamp-r.srNewest.RTPTimestamp) >= (1<<31) && srData.RTPTimestamp > r.srNewest.RTPTimestamp {
+				if utils.Distance(r.srNewest.RTPTimestamp, srData.RTPTimestamp) < 0 && srData.RTPTimestamp > r.srNewest.RTPTimestamp {
 					tsCycles -= (1 << 32)
 				}
 			}
@@ -361,6 +431,14 @@ func (r *RTPStatsReceiver) SetRtcpSenderReportData(srData *RTCPSenderReportData)
 
 	srDataCopy := *srData
 	srDataCopy.RTPTimestampExt = uint64(srDataCopy.RTPTimestamp) + tsCycles
+	srDataCopy.PacketCountExt = uint64(srDataCopy.PacketCount)
+	if r.srNewest != nil {
+		// packet count is monotonic, a smaller count is a roll over
+		srDataCopy.PacketCountExt += r.srNewest.PacketCountExt & 0xFFFF_FFFF_0000_0000
+		if srDataCopy.PacketCount < r.srNewest.PacketCount {
+			srDataCopy.PacketCountExt += (1 << 32)
+		}
+	}
 
 	if r.srNewest != nil && srDataCopy.RTPTimestampExt < r.srNewest.RTPTimestampExt {
 		// This can happen when a track is replaced with a null and then restored -
@@ -377,7 +455,7 @@ func (r *RTPStatsReceiver) SetRtcpSenderReportData(srData *RTCPSenderReportData)
 			)
 		}
 		r.outOfOrderSenderReportCount++
-		return false
+		return ErrSenderReportOutOfOrder
 	}
 
 	if r.srNewest != nil {
@@ -454,7 +532,7 @@ func (r *RTPStatsReceiver) SetRtcpSenderReportData(srData *RTCPSenderReportData)
 				r.logger.Debugw("sharp increase in propagation delay", getPropagationFields()...)
 				r.propagationDelayDeltaHighCount++
 				if r.propagationDelayDeltaHighStartTime.IsZero() {
-					r.propagationDelayDeltaHighStartTime = time.Now()
+					r.propagationDelayDeltaHighStartTime = r.now()
 				}
 				if r.propagationDelaySpike == 0 {
 					r.propagationDelaySpike = propagationDelay
@@ -496,7 +574,7 @@ func (r *RTPStatsReceiver) SetRtcpSenderReportData(srData *RTCPSenderReportData)
 	r.srNewest = &srDataCopy
 
 	r.maybeAdjustFirstPacketTime(r.srNewest, 0, r.timestamp.GetExtendedStart())
-	return true
+	return nil
 }
 
 func (r *RTPStatsReceiver) GetRtcpSenderReportData() *RTCPSenderReportData {
@@ -587,6 +665,33 @@ func (r *RTPStatsReceiver) DeltaInfo(snapshotID uint32) *RTPDeltaInfo {
 	return r.deltaInfo(snapshotID, r.sequenceNumber.GetExtendedStart(), r.sequenceNumber.GetExtendedHighest())
 }
 
+// GetSequenceNumberRange returns extended start and highest sequence numbers atomically
+func (r *RTPStatsReceiver) GetSequenceNumberRange() (extStart, extHighest uint64) {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	return r.sequenceNumber.GetExtendedStart(), r.sequenceNumber.GetExtendedHighest()
+}
+
+// GetPacketsExpected returns the number of packets expected from the sequence number range seen so far.
+func (r *RTPStatsReceiver) GetPacketsExpected() uint64 {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	if !r.initialized {
+		return 0
+	}
+	return r.getPacketsExpected(r.sequenceNumber.GetExtendedStart(), r.sequenceNumber.GetExtendedHighest())
+}
+
+// GetTimestampRange returns extended start and highest RTP timestamps atomically
+func (r *RTPStatsReceiver) GetTimestampRange() (extStart, extHighest uint64) {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	return r.timestamp.GetExtendedStart(), r.timestamp.GetExtendedHighest()
+}
+
 func (r *RTPStatsReceiver) MarshalLogObject(e zapcore.ObjectEncoder) error {
 	if r == nil {
 		return nil
@@ -629,6 +734,24 @@ func (r *RTPStatsReceiver) ToProto() *livekit.RTPStats {
 	)
 }
 
+// ToWebRTCStats returns a stats report keyed by stats id, as in W3C RTCStatsReport,
+// with an RTCInboundRtpStreamStats entry.
+func (r *RTPStatsReceiver) ToWebRTCStats() map[string]interface{} {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	inbound := r.toWebRTCStats(true, r.sequenceNumber.GetExtendedStart(), r.sequenceNumber.GetExtendedHighest())
+	if inbound == nil {
+		return nil
+	}
+	inbound["packetsLost"] = int64(r.packetsLost)
+	inbound["jitter"] = r.jitter / float64(r.params.ClockRate)
+
+	return map[string]interface{}{
+		cWebRTCStatsIDInboundRTP: inbound,
+	}
+}
+
 func (r *RTPStatsReceiver) isInRange(esn uint64, ehsn uint64) bool {
 	diff := int64(ehsn - esn)
 	return diff >= 0 && diff < cHistorySize
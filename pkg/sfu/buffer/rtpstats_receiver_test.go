@@ -15,14 +15,18 @@
 package buffer
 
 import (
+	"encoding/binary"
+	"encoding/json"
 	"fmt"
 	"math/rand"
 	"testing"
 	"time"
 
+	"github.com/pion/rtcp"
 	"github.com/pion/rtp"
 	"github.com/stretchr/testify/require"
 
+	"github.com/livekit/mediatransportutil"
 	"github.com/livekit/protocol/logger"
 )
 
@@ -38,10 +42,10 @@ func getPacket(sn uint16, ts uint32, payloadSize int) *rtp.Packet {
 
 func Test_RTPStatsReceiver(t *testing.T) {
 	clockRate := uint32(90000)
-	r := NewRTPStatsReceiver(RTPStatsParams{
+	r := NewRTPStatsSimulator(RTPStatsParams{
 		ClockRate: clockRate,
 		Logger:    logger.GetLogger(),
-	})
+	}, time.Unix(1700000000, 0))
 
 	totalDuration := 5 * time.Second
 	bitrate := 1000000
@@ -53,7 +57,7 @@ func Test_RTPStatsReceiver(t *testing.T) {
 
 	sequenceNumber := uint16(rand.Float64() * float64(1<<16))
 	timestamp := uint32(rand.Float64() * float64(1<<32))
-	now := time.Now()
+	now := r.Now()
 	startTime := now
 	lastFrameTime := now
 	for now.Sub(startTime) < totalDuration {
@@ -61,7 +65,7 @@ func Test_RTPStatsReceiver(t *testing.T) {
 		for i := 0; i < packetsPerFrame; i++ {
 			packet := getPacket(sequenceNumber, timestamp, packetSize)
 			r.Update(
-				time.Now(),
+				r.Now(),
 				packet.Header.SequenceNumber,
 				packet.Header.Timestamp,
 				packet.Header.Marker,
@@ -78,8 +82,8 @@ func Test_RTPStatsReceiver(t *testing.T) {
 		}
 
 		lastFrameTime = now
-		time.Sleep(time.Duration(sleep) * time.Millisecond)
-		now = time.Now()
+		r.AdvanceClock(time.Duration(sleep) * time.Millisecond)
+		now = r.Now()
 	}
 
 	r.Stop()
@@ -88,16 +92,16 @@ func Test_RTPStatsReceiver(t *testing.T) {
 
 func Test_RTPStatsReceiver_Update(t *testing.T) {
 	clockRate := uint32(90000)
-	r := NewRTPStatsReceiver(RTPStatsParams{
+	r := NewRTPStatsSimulator(RTPStatsParams{
 		ClockRate: clockRate,
 		Logger:    logger.GetLogger(),
-	})
+	}, time.Unix(1700000000, 0))
 
 	sequenceNumber := uint16(rand.Float64() * float64(1<<16))
 	timestamp := uint32(rand.Float64() * float64(1<<32))
 	packet := getPacket(sequenceNumber, timestamp, 1000)
 	flowState := r.Update(
-		time.Now(),
+		r.Now(),
 		packet.Header.SequenceNumber,
 		packet.Header.Timestamp,
 		packet.Header.Marker,
@@ -116,8 +120,9 @@ func Test_RTPStatsReceiver_Update(t *testing.T) {
 	sequenceNumber++
 	timestamp += 3000
 	packet = getPacket(sequenceNumber, timestamp, 1000)
+	r.AdvanceClock(33 * time.Millisecond)
 	flowState = r.Update(
-		time.Now(),
+		r.Now(),
 		packet.Header.SequenceNumber,
 		packet.Header.Timestamp,
 		packet.Header.Marker,
@@ -133,8 +138,9 @@ func Test_RTPStatsReceiver_Update(t *testing.T) {
 
 	// out-of-order, would cause a restart which is disallowed
 	packet = getPacket(sequenceNumber-10, timestamp-30000, 1000)
+	r.AdvanceClock(33 * time.Millisecond)
 	flowState = r.Update(
-		time.Now(),
+		r.Now(),
 		packet.Header.SequenceNumber,
 		packet.Header.Timestamp,
 		packet.Header.Marker,
@@ -153,8 +159,9 @@ func Test_RTPStatsReceiver_Update(t *testing.T) {
 
 	// duplicate of the above out-of-order packet, but would not be handled as it causes a restart
 	packet = getPacket(sequenceNumber-10, timestamp-30000, 1000)
+	r.AdvanceClock(33 * time.Millisecond)
 	flowState = r.Update(
-		time.Now(),
+		r.Now(),
 		packet.Header.SequenceNumber,
 		packet.Header.Timestamp,
 		packet.Header.Marker,
@@ -175,8 +182,9 @@ func Test_RTPStatsReceiver_Update(t *testing.T) {
 	sequenceNumber += 10
 	timestamp += 30000
 	packet = getPacket(sequenceNumber, timestamp, 1000)
+	r.AdvanceClock(33 * time.Millisecond)
 	flowState = r.Update(
-		time.Now(),
+		r.Now(),
 		packet.Header.SequenceNumber,
 		packet.Header.Timestamp,
 		packet.Header.Marker,
@@ -191,8 +199,9 @@ func Test_RTPStatsReceiver_Update(t *testing.T) {
 
 	// out-of-order should decrement number of lost packets
 	packet = getPacket(sequenceNumber-6, timestamp-45000, 1000)
+	r.AdvanceClock(33 * time.Millisecond)
 	flowState = r.Update(
-		time.Now(),
+		r.Now(),
 		packet.Header.SequenceNumber,
 		packet.Header.Timestamp,
 		packet.Header.Marker,
@@ -214,8 +223,9 @@ func Test_RTPStatsReceiver_Update(t *testing.T) {
 	sequenceNumber += 2
 	timestamp += 6000
 	packet = getPacket(sequenceNumber, timestamp, 1000)
+	r.AdvanceClock(33 * time.Millisecond)
 	flowState = r.Update(
-		time.Now(),
+		r.Now(),
 		packet.Header.SequenceNumber,
 		packet.Header.Timestamp,
 		packet.Header.Marker,
@@ -233,8 +243,9 @@ func Test_RTPStatsReceiver_Update(t *testing.T) {
 	sequenceNumber--
 	timestamp -= 3000
 	packet = getPacket(sequenceNumber, timestamp, 999)
+	r.AdvanceClock(33 * time.Millisecond)
 	flowState = r.Update(
-		time.Now(),
+		r.Now(),
 		packet.Header.SequenceNumber,
 		packet.Header.Timestamp,
 		packet.Header.Marker,
@@ -250,8 +261,9 @@ func Test_RTPStatsReceiver_Update(t *testing.T) {
 	// padding only
 	sequenceNumber += 2
 	packet = getPacket(sequenceNumber, timestamp, 0)
+	r.AdvanceClock(33 * time.Millisecond)
 	flowState = r.Update(
-		time.Now(),
+		r.Now(),
 		packet.Header.SequenceNumber,
 		packet.Header.Timestamp,
 		packet.Header.Marker,
@@ -268,3 +280,243 @@ func Test_RTPStatsReceiver_Update(t *testing.T) {
 
 	r.Stop()
 }
+
+// FuzzRTPStats feeds packets encoded as (sn uint16, ts uint32, payloadSize uint8, arrival delta ms uint8)
+// tuples and checks that every expected sequence number is accounted for as primary, padding or lost
+func FuzzRTPStats(f *testing.F) {
+	encode := func(packets ...[4]uint32) []byte {
+		var b []byte
+		for _, p := range packets {
+			b = binary.BigEndian.AppendUint16(b, uint16(p[0]))
+			b = binary.BigEndian.AppendUint32(b, p[1])
+			b = append(b, byte(p[2]), byte(p[3]))
+		}
+		return b
+	}
+	f.Add(encode([4]uint32{65534, 1000, 100, 0}, [4]uint32{65535, 4000, 100, 33}, [4]uint32{0, 7000, 100, 33}, [4]uint32{1, 10000, 100, 33}))
+	f.Add(encode([4]uint32{65534, 1000, 100, 0}, [4]uint32{1, 10000, 100, 33}, [4]uint32{65535, 4000, 100, 5}, [4]uint32{0, 7000, 0, 5}))
+	f.Add(encode([4]uint32{65535, 1000, 100, 0}, [4]uint32{65535, 1000, 100, 1}, [4]uint32{2, 1000, 0, 1}, [4]uint32{100, 90000, 100, 200}))
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		r := NewRTPStatsSimulator(RTPStatsParams{
+			ClockRate: 90000,
+			Logger:    logger.GetLogger(),
+		}, time.Unix(1700000000, 0))
+
+		for ; len(data) >= 8; data = data[8:] {
+			sn := binary.BigEndian.Uint16(data[0:2])
+			ts := binary.BigEndian.Uint32(data[2:6])
+			payloadSize := int(data[6])
+			r.AdvanceClock(time.Duration(data[7]) * time.Millisecond)
+
+			// packets older than history cannot be classified as recovered or duplicate, skip them
+			if r.initialized {
+				if age := uint16(r.sequenceNumber.GetHighest() - sn); age >= cHistorySize && age < 1<<15 {
+					continue
+				}
+			}
+
+			paddingSize := 0
+			if payloadSize == 0 {
+				paddingSize = 25
+			}
+			r.Update(r.Now(), sn, ts, false, 12, payloadSize, paddingSize)
+
+			r.lock.RLock()
+			extStartSN := r.sequenceNumber.GetExtendedStart()
+			extHighestSN := r.sequenceNumber.GetExtendedHighest()
+			packetsExpected := extHighestSN - extStartSN + 1
+			packetsPrimary := r.getTotalPacketsPrimary(extStartSN, extHighestSN)
+			packetsLost := r.packetsLost
+			packetsPadding := r.packetsPadding
+			r.lock.RUnlock()
+
+			// duplicates re-use sequence numbers already counted, so they are not part of expected
+			require.LessOrEqual(t, packetsLost, packetsExpected, "sn: %d", sn)
+			require.LessOrEqual(t, packetsPadding, packetsExpected-packetsLost, "sn: %d", sn)
+			require.Equal(t, packetsExpected, packetsLost+packetsPrimary+packetsPadding, "sn: %d", sn)
+		}
+	})
+}
+
+func Test_RTPStatsReceiver_ToWebRTCStats(t *testing.T) {
+	r := NewRTPStatsSimulator(RTPStatsParams{
+		ClockRate: 90000,
+		Logger:    logger.GetLogger(),
+	}, time.Unix(1700000000, 0))
+	require.Nil(t, r.ToWebRTCStats())
+
+	// 10 packets with one lost
+	for sn := uint16(100); sn < 110; sn++ {
+		if sn == 105 {
+			continue
+		}
+		r.AdvanceClock(10 * time.Millisecond)
+		r.Update(r.Now(), sn, uint32(sn)*900, false, 12, 1000, 0)
+	}
+	r.UpdateNack(2)
+	r.UpdatePli(1)
+
+	report := r.ToWebRTCStats()
+	require.Len(t, report, 1)
+	stats := report["inbound-rtp"].(map[string]interface{})
+	require.Equal(t, "inbound-rtp", stats["id"])
+	require.Equal(t, "inbound-rtp", stats["type"])
+	require.Equal(t, uint64(9), stats["packetsReceived"])
+	require.Equal(t, uint64(9000), stats["bytesReceived"])
+	require.Equal(t, uint64(108), stats["headerBytesReceived"])
+	require.Equal(t, int64(1), stats["packetsLost"])
+	require.NotContains(t, stats, "fractionLost")
+	require.Equal(t, uint32(2), stats["nackCount"])
+	require.Equal(t, uint32(1), stats["pliCount"])
+	require.InDelta(t, float64(r.Now().UnixMilli()), stats["timestamp"], 1e-3)
+
+	_, err := json.Marshal(report)
+	require.NoError(t, err)
+}
+
+func Test_RTPStatsReceiver_Ranges(t *testing.T) {
+	r := NewRTPStatsReceiver(RTPStatsParams{
+		ClockRate: 90000,
+		Logger:    logger.GetLogger(),
+	})
+
+	r.Update(time.Now(), 65534, 0xffffff00, false, 12, 1000, 0)
+	r.Update(time.Now(), 65535, 0xffffff00, false, 12, 1000, 0)
+	r.Update(time.Now(), 1, 100, false, 12, 1000, 0)
+
+	extStartSN, extHighestSN := r.GetSequenceNumberRange()
+	require.Equal(t, uint64(65534), extStartSN)
+	require.Equal(t, uint64(65536+1), extHighestSN)
+
+	extStartTS, extHighestTS := r.GetTimestampRange()
+	require.Equal(t, uint64(0xffffff00), extStartTS)
+	require.Equal(t, uint64(1<<32+100), extHighestTS)
+}
+
+func Test_RTPStatsReceiver_ReorderHistogram(t *testing.T) {
+	r := NewRTPStatsReceiver(RTPStatsParams{
+		ClockRate: 90000,
+		Logger:    logger.GetLogger(),
+	})
+
+	for sn := uint16(1000); sn <= 1100; sn++ {
+		switch sn {
+		case 1030, 1060, 1070, 1090:
+			// held back to arrive out-of-order
+			continue
+		}
+		r.Update(time.Now(), sn, uint32(sn)*900, false, 12, 1000, 0)
+	}
+	require.Equal(t, [cReorderHistogramNumBins]uint32{}, r.GetReorderHistogram())
+
+	// distances from highest (1100): 10, 30, 40, 70
+	for _, sn := range []uint16{1090, 1070, 1060, 1030} {
+		r.Update(time.Now(), sn, uint32(sn)*900, false, 12, 1000, 0)
+	}
+	// duplicate of highest is not out-of-order, duplicate of an older one is at distance 2
+	r.Update(time.Now(), 1100, 1100*900, false, 12, 1000, 0)
+	r.Update(time.Now(), 1098, 1098*900, false, 12, 1000, 0)
+	require.Equal(t, [cReorderHistogramNumBins]uint32{1, 1, 2, 1}, r.GetReorderHistogram())
+}
+
+func Test_RTPStatsReceiver_GapAlert(t *testing.T) {
+	r := NewRTPStatsReceiver(RTPStatsParams{
+		ClockRate: 90000,
+		Logger:    logger.GetLogger(),
+	})
+
+	var gaps []uint64
+	r.SetGapAlertThreshold(100, func(gap uint64) {
+		// invoked outside the lock, stats can be read back
+		_, _ = r.GetSequenceNumberRange()
+		gaps = append(gaps, gap)
+	})
+
+	r.Update(time.Now(), 65500, 0, false, 12, 1000, 0)
+	// 100 missing, not above threshold
+	r.Update(time.Now(), 65601-65536, 900, false, 12, 1000, 0)
+	require.Empty(t, gaps)
+
+	// 5000 missing
+	r.Update(time.Now(), 5066, 1800, false, 12, 1000, 0)
+	require.Equal(t, []uint64{5000}, gaps)
+
+	// out-of-order does not alert
+	r.Update(time.Now(), 100, 900, false, 12, 1000, 0)
+	require.Equal(t, []uint64{5000}, gaps)
+
+	r.SetGapAlertThreshold(0, nil)
+	r.Update(time.Now(), 6000, 2700, false, 12, 1000, 0)
+	require.Equal(t, []uint64{5000}, gaps)
+}
+
+func Test_RTPStatsReceiver_PacketCounts(t *testing.T) {
+	r := NewRTPStatsReceiver(RTPStatsParams{
+		ClockRate: 90000,
+		Logger:    logger.GetLogger(),
+	})
+	require.Zero(t, r.GetPacketsExpected())
+
+	r.Update(time.Now(), 100, 0, false, 12, 1000, 0)
+	r.Update(time.Now(), 101, 0, false, 12, 1000, 0)
+	r.Update(time.Now(), 104, 900, false, 12, 1000, 0)
+	r.Update(time.Now(), 101, 0, false, 12, 1000, 0)
+
+	require.Equal(t, uint64(5), r.GetPacketsExpected())
+	require.Equal(t, uint64(2), r.GetPacketsLostPublic())
+	require.Equal(t, uint64(1), r.GetPacketsDuplicate())
+
+	stats := r.ToProto()
+	require.Equal(t, uint32(r.GetPacketsLostPublic()), stats.PacketsLost)
+	require.Equal(t, uint32(r.GetPacketsDuplicate()), stats.PacketsDuplicate)
+}
+
+func Test_RTPStatsReceiver_UpdateFromSenderReport(t *testing.T) {
+	r := NewRTPStatsReceiver(RTPStatsParams{
+		ClockRate: 90000,
+		Logger:    logger.GetLogger(),
+	})
+
+	now := time.Now()
+	require.ErrorIs(t, r.UpdateFromSenderReport(nil, now), ErrSenderReportInvalid)
+	require.ErrorIs(t, r.UpdateFromSenderReport(&rtcp.SenderReport{}, now), ErrSenderReportBeforeFirstPacket)
+
+	r.Update(now, 100, 1000, false, 12, 1000, 0)
+
+	require.NoError(t, r.UpdateFromSenderReport(&rtcp.SenderReport{
+		NTPTime:     uint64(mediatransportutil.ToNtpTime(now)),
+		RTPTime:     1000,
+		PacketCount: 0xFFFF_FFF0,
+	}, now))
+	srData := r.GetRtcpSenderReportData()
+	require.EqualValues(t, 1000, srData.RTPTimestampExt)
+	require.EqualValues(t, 0xFFFF_FFF0, srData.PacketCountExt)
+	require.Equal(t, now, srData.At)
+
+	// packet count rolls over
+	require.NoError(t, r.UpdateFromSenderReport(&rtcp.SenderReport{
+		NTPTime:     uint64(mediatransportutil.ToNtpTime(now.Add(time.Second))),
+		RTPTime:     91000,
+		PacketCount: 5,
+	}, now.Add(time.Second)))
+	srData = r.GetRtcpSenderReportData()
+	require.EqualValues(t, 91000, srData.RTPTimestampExt)
+	require.EqualValues(t, (1<<32)+5, srData.PacketCountExt)
+
+	// NTP time going back
+	require.ErrorIs(t, r.UpdateFromSenderReport(&rtcp.SenderReport{
+		NTPTime: uint64(mediatransportutil.ToNtpTime(now.Add(500 * time.Millisecond))),
+		RTPTime: 46000,
+	}, now.Add(1100*time.Millisecond)), ErrSenderReportAnachronous)
+
+	// RTP time going back
+	require.ErrorIs(t, r.UpdateFromSenderReport(&rtcp.SenderReport{
+		NTPTime:     uint64(mediatransportutil.ToNtpTime(now.Add(1100 * time.Millisecond))),
+		RTPTime:     41000,
+		PacketCount: 10,
+	}, now.Add(1100*time.Millisecond)), ErrSenderReportOutOfOrder)
+
+	srData = r.GetRtcpSenderReportData()
+	require.EqualValues(t, 91000, srData.RTPTimestampExt)
+}
@@ -18,6 +18,7 @@ import (
 	"errors"
 	"fmt"
 	"math"
+	"slices"
 	"time"
 
 	"github.com/pion/rtcp"
@@ -25,11 +26,13 @@ import (
 
 	"github.com/livekit/mediatransportutil"
 	"github.com/livekit/protocol/livekit"
+
+	"github.com/livekit/livekit-server/pkg/sfu/utils"
 )
 
 const (
-	cSnInfoSize = 4096
-	cSnInfoMask = cSnInfoSize - 1
+	cDefaultSnInfoSize = 4096
+	cMaxSnInfoSize     = 32768
 
 	cSenderReportInitialWait = time.Second
 )
@@ -42,6 +45,7 @@ const (
 	snInfoFlagMarker snInfoFlag = 1 << iota
 	snInfoFlagPadding
 	snInfoFlagOutOfOrder
+	snInfoFlagDuplicate
 )
 
 type snInfo struct {
@@ -157,10 +161,14 @@ type RTPStatsSender struct {
 	jitterFromRR    float64
 	maxJitterFromRR float64
 
-	snInfos [cSnInfoSize]snInfo
+	snInfos    []snInfo
+	snInfoMask uint64
+
+	senderReportCallback func() *RTCPSenderReportData
 
-	nextSenderSnapshotID uint32
-	senderSnapshots      []senderSnapshot
+	nextSenderSnapshotID  uint32
+	senderSnapshots       []senderSnapshot
+	senderSnapshotHistory map[uint32][]*RTPDeltaInfo
 
 	clockSkewCount             int
 	metadataCacheOverflowCount int
@@ -169,11 +177,24 @@ type RTPStatsSender struct {
 }
 
 func NewRTPStatsSender(params RTPStatsParams) *RTPStatsSender {
-	return &RTPStatsSender{
-		rtpStatsBase:         newRTPStatsBase(params),
-		nextSenderSnapshotID: cFirstSnapshotID,
-		senderSnapshots:      make([]senderSnapshot, 2),
+	r := &RTPStatsSender{
+		rtpStatsBase:          newRTPStatsBase(params),
+		nextSenderSnapshotID:  cFirstSnapshotID,
+		senderSnapshots:       make([]senderSnapshot, 2),
+		senderSnapshotHistory: make(map[uint32][]*RTPDeltaInfo),
+	}
+
+	snInfoSize := getSnInfoSize(params.SnInfoSize)
+	if params.SnInfoSize != 0 && int(params.SnInfoSize) != snInfoSize && r.logger != nil {
+		r.logger.Warnw(
+			"invalid sequence number info size, adjusting", nil,
+			"requested", params.SnInfoSize,
+			"adjusted", snInfoSize,
+		)
 	}
+	r.snInfos = make([]snInfo, snInfoSize)
+	r.snInfoMask = uint64(snInfoSize - 1)
+	return r
 }
 
 func (r *RTPStatsSender) Seed(from *RTPStatsSender) {
@@ -199,7 +220,8 @@ func (r *RTPStatsSender) Seed(from *RTPStatsSender) {
 	r.jitterFromRR = from.jitterFromRR
 	r.maxJitterFromRR = from.maxJitterFromRR
 
-	r.snInfos = from.snInfos
+	r.snInfos = slices.Clone(from.snInfos)
+	r.snInfoMask = from.snInfoMask
 
 	r.nextSenderSnapshotID = from.nextSenderSnapshotID
 	r.senderSnapshots = make([]senderSnapshot, cap(from.senderSnapshots))
@@ -213,6 +235,13 @@ func (r *RTPStatsSender) NewSnapshotId() uint32 {
 	return r.newSnapshotID(r.extHighestSN)
 }
 
+func (r *RTPStatsSender) NewNamedSnapshotId(name string) (uint32, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	return r.newNamedSnapshotID(name, r.extHighestSN)
+}
+
 func (r *RTPStatsSender) NewSenderSnapshotId() uint32 {
 	r.lock.Lock()
 	defer r.lock.Unlock()
@@ -227,7 +256,7 @@ func (r *RTPStatsSender) NewSenderSnapshotId() uint32 {
 	}
 
 	if r.initialized {
-		r.senderSnapshots[id-cFirstSnapshotID] = r.initSenderSnapshot(time.Now(), r.extHighestSN)
+		r.senderSnapshots[id-cFirstSnapshotID] = r.initSenderSnapshot(r.now(), r.extHighestSN)
 	}
 	return id
 }
@@ -256,7 +285,7 @@ func (r *RTPStatsSender) Update(
 
 		r.initialized = true
 
-		r.startTime = time.Now()
+		r.startTime = r.now()
 
 		r.firstTime = packetTime
 		r.highestTime = packetTime
@@ -360,6 +389,7 @@ func (r *RTPStatsSender) Update(
 
 		if gapSN != 0 {
 			r.packetsOutOfOrder++
+			r.updateReorderHistogram(int(-gapSN))
 		}
 
 		if !r.isSnInfoLost(extSequenceNumber, r.extHighestSN) {
@@ -367,6 +397,9 @@ func (r *RTPStatsSender) Update(
 			r.headerBytesDuplicate += uint64(hdrSize)
 			r.packetsDuplicate++
 			isDuplicate = true
+			if slot := r.getSnInfoOutOfOrderSlot(extSequenceNumber, r.extHighestSN); slot >= 0 {
+				r.snInfos[slot].flags |= snInfoFlagDuplicate
+			}
 		} else {
 			r.packetsLost--
 			r.setSnInfo(extSequenceNumber, r.extHighestSN, uint16(pktSize), uint8(hdrSize), uint16(payloadSize), marker, true)
@@ -458,7 +491,7 @@ func (r *RTPStatsSender) UpdateFromReceiverReport(rr rtcp.ReceptionReport) (rtt
 
 	extHighestSNFromRR := r.extHighestSNFromRR&0xFFFF_FFFF_0000_0000 + uint64(rr.LastSequenceNumber)
 	if !r.lastRRTime.IsZero() {
-		if (rr.LastSequenceNumber-r.lastRR.LastSequenceNumber) < (1<<31) && rr.LastSequenceNumber < r.lastRR.LastSequenceNumber {
+		if utils.Distance(r.lastRR.LastSequenceNumber, rr.LastSequenceNumber) >= 0 && rr.LastSequenceNumber < r.lastRR.LastSequenceNumber {
 			extHighestSNFromRR += (1 << 32)
 		}
 	}
@@ -495,7 +528,7 @@ func (r *RTPStatsSender) UpdateFromReceiverReport(rr rtcp.ReceptionReport) (rtt
 
 	// This is 24-bit max in the protocol. So, technically doesn't need extended type. But, done for consistency.
 	packetsLostFromRR := r.packetsLostFromRR&0xFFFF_FFFF_0000_0000 + uint64(rr.TotalLost)
-	if (rr.TotalLost-r.lastRR.TotalLost) < (1<<31) && rr.TotalLost < r.lastRR.TotalLost {
+	if utils.Distance(r.lastRR.TotalLost, rr.TotalLost) >= 0 && rr.TotalLost < r.lastRR.TotalLost {
 		packetsLostFromRR += (1 << 32)
 	}
 	r.packetsLostFromRR = packetsLostFromRR
@@ -600,7 +633,7 @@ func (r *RTPStatsSender) UpdateFromReceiverReport(rr rtcp.ReceptionReport) (rtt
 		s.extLastRRSN = extReceivedRRSN
 	}
 
-	r.lastRRTime = time.Now()
+	r.lastRRTime = r.now()
 	r.lastRR = rr
 	return
 }
@@ -638,24 +671,55 @@ func (r *RTPStatsSender) GetExpectedRTPTimestamp(at time.Time) (expectedTSExt ui
 	return
 }
 
+// SetSenderReportCallback sets a callback which provides sender report data, for example from an external
+// time synchronization source, to be used in place of data derived from the publisher sender report when
+// generating RTCP sender reports. The callback is invoked with stats lock held and should not call back into stats.
+// If the callback returns nil, sender report data is derived from the publisher sender report.
+func (r *RTPStatsSender) SetSenderReportCallback(fn func() *RTCPSenderReportData) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.senderReportCallback = fn
+}
+
 func (r *RTPStatsSender) GetRtcpSenderReport(ssrc uint32, publisherSRData *RTCPSenderReportData, tsOffset uint64) *rtcp.SenderReport {
 	r.lock.Lock()
 	defer r.lock.Unlock()
 
-	if !r.initialized || publisherSRData == nil {
+	if !r.initialized {
+		return nil
+	}
+
+	var externalSRData *RTCPSenderReportData
+	if r.senderReportCallback != nil {
+		externalSRData = r.senderReportCallback()
+	}
+	if externalSRData == nil && publisherSRData == nil {
 		return nil
 	}
 
-	timeSincePublisherSRAdjusted := time.Since(publisherSRData.AtAdjusted)
-	now := publisherSRData.AtAdjusted.Add(timeSincePublisherSRAdjusted)
 	var (
-		nowNTP    mediatransportutil.NtpTime
-		nowRTPExt uint64
+		timeSincePublisherSRAdjusted time.Duration
+		now                          time.Time
+		nowNTP                       mediatransportutil.NtpTime
+		nowRTPExt                    uint64
 	)
-	if cPassthroughNTPTimestamp {
+	switch {
+	case externalSRData != nil:
+		timeSincePublisherSRAdjusted = time.Since(externalSRData.AtAdjusted)
+		now = externalSRData.AtAdjusted.Add(timeSincePublisherSRAdjusted)
+		nowNTP = mediatransportutil.ToNtpTime(externalSRData.NTPTimestamp.Time().Add(timeSincePublisherSRAdjusted))
+		nowRTPExt = externalSRData.RTPTimestampExt - tsOffset + uint64(timeSincePublisherSRAdjusted.Nanoseconds()*int64(r.params.ClockRate)/1e9)
+
+	case cPassthroughNTPTimestamp:
+		timeSincePublisherSRAdjusted = time.Since(publisherSRData.AtAdjusted)
+		now = publisherSRData.AtAdjusted.Add(timeSincePublisherSRAdjusted)
 		nowNTP = publisherSRData.NTPTimestamp
 		nowRTPExt = publisherSRData.RTPTimestampExt - tsOffset
-	} else {
+
+	default:
+		timeSincePublisherSRAdjusted = time.Since(publisherSRData.AtAdjusted)
+		now = publisherSRData.AtAdjusted.Add(timeSincePublisherSRAdjusted)
 		nowNTP = mediatransportutil.ToNtpTime(now)
 		nowRTPExt = publisherSRData.RTPTimestampExt - tsOffset + uint64(timeSincePublisherSRAdjusted.Nanoseconds()*int64(r.params.ClockRate)/1e9)
 	}
@@ -669,13 +733,14 @@ func (r *RTPStatsSender) GetRtcpSenderReport(ssrc uint32, publisherSRData *RTCPS
 	}
 
 	getFields := func() []interface{} {
-		return []interface{}{
+		fields := []interface{}{
 			"first", r.srFirst,
 			"last", r.srNewest,
 			"curr", srData,
 			"feed", publisherSRData,
+			"external", externalSRData,
 			"tsOffset", tsOffset,
-			"timeNow", time.Now().String(),
+			"timeNow", r.now().String(),
 			"now", now.String(),
 			"extStartTS", r.extStartTS,
 			"extHighestTS", r.extHighestTS,
@@ -684,9 +749,12 @@ func (r *RTPStatsSender) GetRtcpSenderReport(ssrc uint32, publisherSRData *RTCPS
 			"firstTime", r.firstTime.String(),
 			"timeSinceFirst", now.Sub(r.firstTime).String(),
 			"timeSincePublisherSRAdjusted", timeSincePublisherSRAdjusted.String(),
-			"timeSincePublisherSR", time.Since(publisherSRData.At).String(),
 			"nowRTPExt", nowRTPExt,
 		}
+		if publisherSRData != nil {
+			fields = append(fields, "timeSincePublisherSR", time.Since(publisherSRData.At).String())
+		}
+		return fields
 	}
 	if r.srNewest != nil && nowRTPExt >= r.srNewest.RTPTimestampExt {
 		timeSinceLastReport := nowNTP.Time().Sub(r.srNewest.NTPTimestamp.Time())
@@ -728,6 +796,53 @@ func (r *RTPStatsSender) GetRtcpSenderReport(ssrc uint32, publisherSRData *RTCPS
 	}
 }
 
+// GetRTCPExtendedReport returns a RFC 3611 extended report with Loss RLE and Duplicate RLE blocks
+// covering the sequence numbers still in history and a Receiver Reference Time block.
+func (r *RTPStatsSender) GetRTCPExtendedReport(ssrc uint32) *rtcp.ExtendedReport {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	if !r.initialized || r.extHighestSN < r.extStartSN {
+		return nil
+	}
+
+	extBeginSN := r.extStartSN
+	if snInfoSize := uint64(len(r.snInfos)); r.extHighestSN-extBeginSN >= snInfoSize {
+		extBeginSN = r.extHighestSN - snInfoSize + 1
+	}
+	extEndSN := r.extHighestSN + 1 // exclusive
+
+	lossChunks := getRLEChunks(extBeginSN, extEndSN, func(esn uint64) bool {
+		// run type 1 indicates received packets
+		return !r.isSnInfoLost(esn, r.extHighestSN)
+	})
+	duplicateChunks := getRLEChunks(extBeginSN, extEndSN, func(esn uint64) bool {
+		// run type 1 indicates duplicated packets
+		return r.snInfos[esn&r.snInfoMask].flags&snInfoFlagDuplicate != 0
+	})
+
+	return &rtcp.ExtendedReport{
+		SenderSSRC: ssrc,
+		Reports: []rtcp.ReportBlock{
+			&rtcp.LossRLEReportBlock{
+				SSRC:     ssrc,
+				BeginSeq: uint16(extBeginSN),
+				EndSeq:   uint16(extEndSN),
+				Chunks:   lossChunks,
+			},
+			&rtcp.DuplicateRLEReportBlock{
+				SSRC:     ssrc,
+				BeginSeq: uint16(extBeginSN),
+				EndSeq:   uint16(extEndSN),
+				Chunks:   duplicateChunks,
+			},
+			&rtcp.ReceiverReferenceTimeReportBlock{
+				NTPTimestamp: uint64(mediatransportutil.ToNtpTime(r.now())),
+			},
+		},
+	}
+}
+
 func (r *RTPStatsSender) DeltaInfo(snapshotID uint32) *RTPDeltaInfo {
 	r.lock.Lock()
 	defer r.lock.Unlock()
@@ -738,6 +853,21 @@ func (r *RTPStatsSender) DeltaInfo(snapshotID uint32) *RTPDeltaInfo {
 func (r *RTPStatsSender) DeltaInfoSender(senderSnapshotID uint32) *RTPDeltaInfo {
 	r.lock.Lock()
 	defer r.lock.Unlock()
+
+	deltaInfo := r.getDeltaInfoSender(senderSnapshotID)
+	r.addSnapshotHistory(r.senderSnapshotHistory, senderSnapshotID, deltaInfo)
+	return deltaInfo
+}
+
+// GetSenderSnapshotHistory returns the most recent delta infos of a sender snapshot, oldest first
+func (r *RTPStatsSender) GetSenderSnapshotHistory(senderSnapshotID uint32) []*RTPDeltaInfo {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	return slices.Clone(r.senderSnapshotHistory[senderSnapshotID])
+}
+
+func (r *RTPStatsSender) getDeltaInfoSender(senderSnapshotID uint32) *RTPDeltaInfo {
 	if r.lastRRTime.IsZero() {
 		return nil
 	}
@@ -824,6 +954,22 @@ func (r *RTPStatsSender) DeltaInfoSender(senderSnapshotID uint32) *RTPDeltaInfo
 	}
 }
 
+// GetSequenceNumberRange returns extended start and highest sequence numbers atomically
+func (r *RTPStatsSender) GetSequenceNumberRange() (extStart, extHighest uint64) {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	return r.extStartSN, r.extHighestSN
+}
+
+// GetTimestampRange returns extended start and highest RTP timestamps atomically
+func (r *RTPStatsSender) GetTimestampRange() (extStart, extHighest uint64) {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	return r.extStartTS, r.extHighestTS
+}
+
 func (r *RTPStatsSender) MarshalLogObject(e zapcore.ObjectEncoder) error {
 	if r == nil {
 		return nil
@@ -868,6 +1014,38 @@ func (r *RTPStatsSender) ToProto() *livekit.RTPStats {
 	)
 }
 
+// ToWebRTCStats returns a stats report keyed by stats id, as in W3C RTCStatsReport,
+// with an RTCOutboundRtpStreamStats entry and, once a receiver report has been received,
+// an RTCRemoteInboundRtpStreamStats entry with loss, jitter and round trip time reported by the remote receiver.
+func (r *RTPStatsSender) ToWebRTCStats() map[string]interface{} {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	outbound := r.toWebRTCStats(false, r.extStartSN, r.extHighestSN)
+	if outbound == nil {
+		return nil
+	}
+	report := map[string]interface{}{
+		cWebRTCStatsIDOutboundRTP: outbound,
+	}
+	if r.lastRRTime.IsZero() {
+		return report
+	}
+
+	outbound["remoteId"] = cWebRTCStatsIDRemoteInboundRTP
+	report[cWebRTCStatsIDRemoteInboundRTP] = map[string]interface{}{
+		"id":            cWebRTCStatsIDRemoteInboundRTP,
+		"type":          "remote-inbound-rtp",
+		"timestamp":     float64(r.lastRRTime.UnixNano()) / 1e6,
+		"localId":       cWebRTCStatsIDOutboundRTP,
+		"packetsLost":   int64(r.packetsLostFromRR),
+		"fractionLost":  float64(r.lastRR.FractionLost) / 256.0,
+		"jitter":        r.jitterFromRR / float64(r.params.ClockRate),
+		"roundTripTime": float64(r.rtt) / 1000.0,
+	}
+	return report
+}
+
 func (r *RTPStatsSender) getAndResetSenderSnapshot(senderSnapshotID uint32) (*senderSnapshot, *senderSnapshot) {
 	if !r.initialized || r.lastRRTime.IsZero() {
 		return nil, nil
@@ -927,12 +1105,12 @@ func (r *RTPStatsSender) getSenderSnapshot(startTime time.Time, s *senderSnapsho
 
 func (r *RTPStatsSender) getSnInfoOutOfOrderSlot(esn uint64, ehsn uint64) int {
 	offset := int64(ehsn - esn)
-	if offset >= cSnInfoSize || offset < 0 {
+	if offset >= int64(len(r.snInfos)) || offset < 0 {
 		// too old OR too new (i. e. ahead of highest)
 		return -1
 	}
 
-	return int(esn & cSnInfoMask)
+	return int(esn & r.snInfoMask)
 }
 
 func (r *RTPStatsSender) setSnInfo(esn uint64, ehsn uint64, pktSize uint16, hdrSize uint8, payloadSize uint16, marker bool, isOutOfOrder bool) {
@@ -943,7 +1121,7 @@ func (r *RTPStatsSender) setSnInfo(esn uint64, ehsn uint64, pktSize uint16, hdrS
 			return
 		}
 	} else {
-		slot = int(esn & cSnInfoMask)
+		slot = int(esn & r.snInfoMask)
 	}
 
 	snInfo := &r.snInfos[slot]
@@ -967,7 +1145,7 @@ func (r *RTPStatsSender) clearSnInfos(extStartInclusive uint64, extEndExclusive
 	}
 
 	for esn := extStartInclusive; esn != extEndExclusive; esn++ {
-		snInfo := &r.snInfos[esn&cSnInfoMask]
+		snInfo := &r.snInfos[esn&r.snInfoMask]
 		snInfo.pktSize = 0
 		snInfo.hdrSize = 0
 		snInfo.flags = 0
@@ -1026,3 +1204,54 @@ func (r *RTPStatsSender) getIntervalStats(
 }
 
 // -------------------------------------------------------------------
+
+// getRLEChunks run length encodes [extStartInclusive, extEndExclusive) into chunks of RFC 3611 section 4.1,
+// a terminating null chunk is added when needed to align to 32-bits.
+func getRLEChunks(extStartInclusive uint64, extEndExclusive uint64, isSet func(esn uint64) bool) []rtcp.Chunk {
+	const maxRunLength = 0x3FFF
+
+	var chunks []rtcp.Chunk
+	addRun := func(runType bool, runLength uint16) {
+		chunk := rtcp.Chunk(runLength)
+		if runType {
+			chunk |= 1 << 14
+		}
+		chunks = append(chunks, chunk)
+	}
+
+	runType := false
+	runLength := uint16(0)
+	for esn := extStartInclusive; esn != extEndExclusive; esn++ {
+		set := isSet(esn)
+		if runLength != 0 && (set != runType || runLength == maxRunLength) {
+			addRun(runType, runLength)
+			runLength = 0
+		}
+		runType = set
+		runLength++
+	}
+	if runLength != 0 {
+		addRun(runType, runLength)
+	}
+
+	if len(chunks)%2 != 0 {
+		chunks = append(chunks, 0)
+	}
+	return chunks
+}
+
+// -------------------------------------------------------------------
+
+// getSnInfoSize returns size of sequence number info ring, which should be a power of 2,
+// rounding up to the next power of 2 if necessary.
+func getSnInfoSize(requested uint16) int {
+	if requested == 0 {
+		return cDefaultSnInfoSize
+	}
+
+	size := 1
+	for size < int(requested) && size < cMaxSnInfoSize {
+		size <<= 1
+	}
+	return size
+}
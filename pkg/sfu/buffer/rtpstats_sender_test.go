@@ -0,0 +1,218 @@
+// Copyright 2024 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package buffer
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/pion/rtcp"
+	"github.com/stretchr/testify/require"
+
+	"github.com/livekit/mediatransportutil"
+	"github.com/livekit/protocol/logger"
+)
+
+func Test_RTPStatsSender_GetRTCPExtendedReport(t *testing.T) {
+	r := NewRTPStatsSender(RTPStatsParams{
+		ClockRate: 90000,
+		Logger:    logger.GetLogger(),
+	})
+	require.Nil(t, r.GetRTCPExtendedReport(1234))
+
+	// 1000 - 1004 received, 1005 - 1006 lost, 1007 - 1009 received, 1008 duplicated
+	for esn := uint64(1000); esn < 1010; esn++ {
+		if esn == 1005 || esn == 1006 {
+			continue
+		}
+		r.Update(time.Now(), esn, esn*900, false, 12, 1000, 0)
+	}
+	r.Update(time.Now(), 1008, 1008*900, false, 12, 1000, 0)
+
+	xr := r.GetRTCPExtendedReport(1234)
+	require.NotNil(t, xr)
+	require.Equal(t, uint32(1234), xr.SenderSSRC)
+	require.Len(t, xr.Reports, 3)
+
+	loss, ok := xr.Reports[0].(*rtcp.LossRLEReportBlock)
+	require.True(t, ok)
+	require.Equal(t, uint16(1000), loss.BeginSeq)
+	require.Equal(t, uint16(1010), loss.EndSeq)
+	require.Equal(t, []rtcp.Chunk{0x4000 | 5, 2, 0x4000 | 3, 0}, loss.Chunks)
+
+	duplicate, ok := xr.Reports[1].(*rtcp.DuplicateRLEReportBlock)
+	require.True(t, ok)
+	require.Equal(t, []rtcp.Chunk{8, 0x4000 | 1, 1, 0}, duplicate.Chunks)
+
+	_, ok = xr.Reports[2].(*rtcp.ReceiverReferenceTimeReportBlock)
+	require.True(t, ok)
+
+	marshalled, err := xr.Marshal()
+	require.NoError(t, err)
+
+	var unmarshalled rtcp.ExtendedReport
+	require.NoError(t, unmarshalled.Unmarshal(marshalled))
+	require.Len(t, unmarshalled.Reports, 3)
+}
+
+func Test_RTPStatsSender_SnInfoSize(t *testing.T) {
+	require.Equal(t, cDefaultSnInfoSize, getSnInfoSize(0))
+	require.Equal(t, 1024, getSnInfoSize(1024))
+	require.Equal(t, 1024, getSnInfoSize(1000))
+	require.Equal(t, cMaxSnInfoSize, getSnInfoSize(65535))
+
+	r := NewRTPStatsSender(RTPStatsParams{
+		ClockRate:  90000,
+		Logger:     logger.GetLogger(),
+		SnInfoSize: 64,
+	})
+	require.Len(t, r.snInfos, 64)
+
+	for esn := uint64(1000); esn < 1100; esn++ {
+		r.Update(time.Now(), esn, esn*900, false, 12, 1000, 0)
+	}
+
+	// report is limited to the sequence numbers held in the ring
+	xr := r.GetRTCPExtendedReport(1234)
+	require.NotNil(t, xr)
+	loss, ok := xr.Reports[0].(*rtcp.LossRLEReportBlock)
+	require.True(t, ok)
+	require.Equal(t, uint16(1100-64), loss.BeginSeq)
+	require.Equal(t, uint16(1100), loss.EndSeq)
+}
+
+func Test_RTPStatsSender_SenderReportCallback(t *testing.T) {
+	r := NewRTPStatsSender(RTPStatsParams{
+		ClockRate: 90000,
+		Logger:    logger.GetLogger(),
+	})
+	now := time.Now()
+	r.Update(now, 1000, 90000, false, 12, 1000, 0)
+
+	// no publisher sender report and no callback
+	require.Nil(t, r.GetRtcpSenderReport(1234, nil, 0))
+
+	publisherSRData := &RTCPSenderReportData{
+		NTPTimestamp:    mediatransportutil.ToNtpTime(now),
+		RTPTimestamp:    180000,
+		RTPTimestampExt: 180000,
+		At:              now,
+		AtAdjusted:      now,
+	}
+	sr := r.GetRtcpSenderReport(1234, publisherSRData, 0)
+	require.NotNil(t, sr)
+	require.Equal(t, uint32(180000), sr.RTPTime)
+
+	// external data is offset and advanced to the time of sending
+	externalAt := time.Now().Add(-time.Second)
+	externalSRData := &RTCPSenderReportData{
+		NTPTimestamp:    mediatransportutil.ToNtpTime(externalAt),
+		RTPTimestamp:    270000,
+		RTPTimestampExt: 270000,
+		At:              externalAt,
+		AtAdjusted:      externalAt,
+	}
+	r.SetSenderReportCallback(func() *RTCPSenderReportData {
+		return externalSRData
+	})
+	sr = r.GetRtcpSenderReport(1234, nil, 1000)
+	require.NotNil(t, sr)
+	require.InDelta(t, 270000-1000+90000, sr.RTPTime, 9000)
+	elapsed := mediatransportutil.NtpTime(sr.NTPTime).Time().Sub(externalAt)
+	require.InDelta(t, time.Second, elapsed, float64(100*time.Millisecond))
+
+	// falls back to publisher sender report when callback does not provide data
+	externalSRData = nil
+	publisherSRData.RTPTimestamp = 360000
+	publisherSRData.RTPTimestampExt = 360000
+	sr = r.GetRtcpSenderReport(1234, publisherSRData, 0)
+	require.NotNil(t, sr)
+	require.Equal(t, uint32(360000), sr.RTPTime)
+}
+
+func Test_RTPStatsSender_SenderSnapshotHistory(t *testing.T) {
+	r := NewRTPStatsSender(RTPStatsParams{
+		ClockRate:            90000,
+		Logger:               logger.GetLogger(),
+		SnapshotHistoryDepth: 2,
+	})
+
+	esn := uint64(1000)
+	r.Update(time.Now(), esn, esn*900, false, 12, 1000, 0)
+	id := r.NewSenderSnapshotId()
+	require.Empty(t, r.GetSenderSnapshotHistory(id))
+
+	for i := 1; i <= 3; i++ {
+		for j := 0; j < i; j++ {
+			esn++
+			r.Update(time.Now(), esn, esn*900, false, 12, 1000, 0)
+		}
+		r.UpdateFromReceiverReport(rtcp.ReceptionReport{SSRC: 1234, LastSequenceNumber: uint32(esn)})
+		require.NotNil(t, r.DeltaInfoSender(id))
+	}
+
+	history := r.GetSenderSnapshotHistory(id)
+	require.Len(t, history, 2)
+	for i, deltaInfo := range history {
+		require.Equal(t, uint32(i+2), deltaInfo.Packets)
+	}
+}
+
+func Test_RTPStatsSender_ToWebRTCStats(t *testing.T) {
+	r := NewRTPStatsSender(RTPStatsParams{
+		ClockRate: 90000,
+		Logger:    logger.GetLogger(),
+	})
+	require.Nil(t, r.ToWebRTCStats())
+
+	for esn := uint64(1000); esn < 1010; esn++ {
+		r.Update(time.Now(), esn, esn*900, false, 12, 1000, 0)
+	}
+
+	// no receiver report, only local stats
+	report := r.ToWebRTCStats()
+	require.Len(t, report, 1)
+	outbound := report["outbound-rtp"].(map[string]interface{})
+	require.Equal(t, "outbound-rtp", outbound["type"])
+	require.Equal(t, uint64(10), outbound["packetsSent"])
+	require.Equal(t, uint64(10000), outbound["bytesSent"])
+	require.NotContains(t, outbound, "packetsLost")
+	require.NotContains(t, outbound, "remoteId")
+
+	r.UpdateFromReceiverReport(rtcp.ReceptionReport{
+		SSRC:               1234,
+		FractionLost:       64,
+		TotalLost:          2,
+		LastSequenceNumber: 1009,
+		Jitter:             900,
+	})
+	report = r.ToWebRTCStats()
+	require.Len(t, report, 2)
+	outbound = report["outbound-rtp"].(map[string]interface{})
+	require.Equal(t, "remote-inbound-rtp", outbound["remoteId"])
+	require.NotContains(t, outbound, "roundTripTime")
+
+	remoteInbound := report["remote-inbound-rtp"].(map[string]interface{})
+	require.Equal(t, "remote-inbound-rtp", remoteInbound["type"])
+	require.Equal(t, "outbound-rtp", remoteInbound["localId"])
+	require.Equal(t, int64(2), remoteInbound["packetsLost"])
+	require.InDelta(t, 0.25, remoteInbound["fractionLost"], 1e-6)
+	require.InDelta(t, 0.01, remoteInbound["jitter"], 1e-6)
+	require.Contains(t, remoteInbound, "roundTripTime")
+
+	_, err := json.Marshal(report)
+	require.NoError(t, err)
+}
@@ -0,0 +1,53 @@
+// Copyright 2024 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package buffer
+
+import (
+	"sync"
+	"time"
+)
+
+// RTPStatsSimulator is a receiver side RTP stats driven by a manually advanced clock,
+// used to test time dependent behaviour deterministically
+type RTPStatsSimulator struct {
+	*RTPStatsReceiver
+
+	clockLock sync.Mutex
+	clock     time.Time
+}
+
+func NewRTPStatsSimulator(params RTPStatsParams, startTime time.Time) *RTPStatsSimulator {
+	s := &RTPStatsSimulator{
+		clock: startTime,
+	}
+	params.ClockFunc = s.Now
+	s.RTPStatsReceiver = NewRTPStatsReceiver(params)
+	return s
+}
+
+// Now returns the simulated time
+func (s *RTPStatsSimulator) Now() time.Time {
+	s.clockLock.Lock()
+	defer s.clockLock.Unlock()
+
+	return s.clock
+}
+
+func (s *RTPStatsSimulator) AdvanceClock(d time.Duration) {
+	s.clockLock.Lock()
+	defer s.clockLock.Unlock()
+
+	s.clock = s.clock.Add(d)
+}
@@ -33,8 +33,62 @@ import (
 const (
 	UpdateInterval                   = 5 * time.Second
 	noReceiverReportTooLongThreshold = 30 * time.Second
+	qualityHistorySize               = 32
 )
 
+// ConnectionQualityPoint is the score and quality at the time quality changed.
+type ConnectionQualityPoint struct {
+	Time    time.Time
+	Score   float32
+	Quality livekit.ConnectionQuality
+}
+
+// qualityHistory is a ring buffer of the most recent quality changes.
+type qualityHistory struct {
+	points []ConnectionQualityPoint
+	next   int
+	full   bool
+}
+
+func newQualityHistory(size int) *qualityHistory {
+	return &qualityHistory{
+		points: make([]ConnectionQualityPoint, size),
+	}
+}
+
+func (q *qualityHistory) add(point ConnectionQualityPoint) {
+	q.points[q.next] = point
+	q.next++
+	if q.next == len(q.points) {
+		q.next = 0
+		q.full = true
+	}
+}
+
+func (q *qualityHistory) last() (ConnectionQualityPoint, bool) {
+	if !q.full && q.next == 0 {
+		return ConnectionQualityPoint{}, false
+	}
+
+	return q.points[(q.next+len(q.points)-1)%len(q.points)], true
+}
+
+// get returns up to n most recent points from oldest to newest, all points if n is not positive
+func (q *qualityHistory) get(n int) []ConnectionQualityPoint {
+	var points []ConnectionQualityPoint
+	if q.full {
+		points = make([]ConnectionQualityPoint, 0, len(q.points))
+		points = append(points, q.points[q.next:]...)
+	} else {
+		points = make([]ConnectionQualityPoint, 0, q.next)
+	}
+	points = append(points, q.points[:q.next]...)
+	if n > 0 && n < len(points) {
+		points = points[len(points)-n:]
+	}
+	return points
+}
+
 type ConnectionStatsReceiverProvider interface {
 	GetDeltaStats() map[uint32]*buffer.StreamStatsWithLayers
 	GetLastSenderReportTime() time.Time
@@ -72,6 +126,9 @@ type ConnectionStats struct {
 
 	scorer *qualityScorer
 
+	historyLock    sync.Mutex
+	qualityHistory *qualityHistory
+
 	done core.Fuse
 }
 
@@ -85,6 +142,7 @@ func NewConnectionStats(params ConnectionStatsParams) *ConnectionStats {
 			EnableBitrateScore: params.EnableBitrateScore,
 			Logger:             params.Logger,
 		}),
+		qualityHistory: newQualityHistory(qualityHistorySize),
 	}
 }
 
@@ -203,11 +261,38 @@ func (cs *ConnectionStats) GetScoreAndQuality() (float32, livekit.ConnectionQual
 	return cs.scorer.GetMOSAndQuality()
 }
 
+// GetQualityHistory returns up to n most recent quality changes from oldest to newest, all available if n is not positive.
+// It helps to tell consistently poor quality from momentary degradation.
+func (cs *ConnectionStats) GetQualityHistory(n int) []ConnectionQualityPoint {
+	cs.historyLock.Lock()
+	defer cs.historyLock.Unlock()
+
+	return cs.qualityHistory.get(n)
+}
+
+func (cs *ConnectionStats) updateQualityHistory(mos float32, quality livekit.ConnectionQuality, at time.Time) {
+	cs.historyLock.Lock()
+	defer cs.historyLock.Unlock()
+
+	if last, ok := cs.qualityHistory.last(); ok && last.Quality == quality {
+		return
+	}
+
+	if at.IsZero() {
+		at = time.Now()
+	}
+	cs.qualityHistory.add(ConnectionQualityPoint{
+		Time:    at,
+		Score:   mos,
+		Quality: quality,
+	})
+}
+
 func (cs *ConnectionStats) updateScoreWithAggregate(agg *buffer.RTPDeltaInfo, lastRTCPAt time.Time, at time.Time) float32 {
 	var stat windowStat
 	if agg != nil {
 		stat.startedAt = agg.StartTime
-		stat.duration = agg.EndTime.Sub(agg.StartTime)
+		stat.duration = agg.Duration()
 		stat.packetsExpected = agg.Packets + agg.PacketsPadding
 		stat.packetsLost = agg.PacketsLost
 		stat.packetsMissing = agg.PacketsMissing
@@ -224,7 +309,8 @@ func (cs *ConnectionStats) updateScoreWithAggregate(agg *buffer.RTPDeltaInfo, la
 		cs.scorer.UpdateAt(&stat, at)
 	}
 
-	mos, _ := cs.scorer.GetMOSAndQuality()
+	mos, quality := cs.scorer.GetMOSAndQuality()
+	cs.updateQualityHistory(mos, quality, at)
 	return mos
 }
 
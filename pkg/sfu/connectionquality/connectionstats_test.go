@@ -911,3 +911,71 @@ func TestConnectionQuality(t *testing.T) {
 		}
 	})
 }
+
+func TestConnectionQualityHistory(t *testing.T) {
+	trp := newTestReceiverProvider()
+	cs := NewConnectionStats(ConnectionStatsParams{
+		MimeType:         "audio/opus",
+		ReceiverProvider: trp,
+		Logger:           logger.GetLogger(),
+	})
+	require.Empty(t, cs.GetQualityHistory(0))
+
+	duration := 5 * time.Second
+	now := time.Now()
+	cs.StartAt(&livekit.TrackInfo{Type: livekit.TrackType_AUDIO}, now.Add(-duration))
+
+	update := func(packetsLost uint32) {
+		trp.setStreams(map[uint32]*buffer.StreamStatsWithLayers{
+			1: {
+				RTPStats: &buffer.RTPDeltaInfo{
+					StartTime:   now,
+					EndTime:     now.Add(duration),
+					Packets:     250,
+					PacketsLost: packetsLost,
+				},
+			},
+		})
+		now = now.Add(duration)
+		cs.updateScoreAt(now)
+	}
+
+	update(0)
+	first := now
+	// no change in quality, not recorded
+	update(0)
+	update(60)
+
+	history := cs.GetQualityHistory(0)
+	require.Len(t, history, 2)
+	require.Equal(t, first, history[0].Time)
+	require.Equal(t, livekit.ConnectionQuality_EXCELLENT, history[0].Quality)
+	require.Equal(t, now, history[1].Time)
+	require.Equal(t, livekit.ConnectionQuality_POOR, history[1].Quality)
+	_, quality := cs.GetScoreAndQuality()
+	require.Equal(t, quality, history[1].Quality)
+
+	require.Equal(t, history[1:], cs.GetQualityHistory(1))
+}
+
+func TestQualityHistory(t *testing.T) {
+	q := newQualityHistory(3)
+	_, ok := q.last()
+	require.False(t, ok)
+
+	for i := 1; i <= 4; i++ {
+		q.add(ConnectionQualityPoint{Score: float32(i)})
+	}
+	last, ok := q.last()
+	require.True(t, ok)
+	require.Equal(t, float32(4), last.Score)
+
+	var scores []float32
+	for _, p := range q.get(0) {
+		scores = append(scores, p.Score)
+	}
+	require.Equal(t, []float32{2, 3, 4}, scores)
+	require.Len(t, q.get(2), 2)
+	require.Equal(t, float32(3), q.get(2)[0].Score)
+	require.Len(t, q.get(10), 3)
+}
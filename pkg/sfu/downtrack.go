@@ -64,6 +64,9 @@ type TrackSender interface {
 		publisherSRData *buffer.RTCPSenderReportData,
 	) error
 	Resync()
+	SetBandwidthOverride(bps int64)
+	SetTemporalLayerCap(temporalLayer int32)
+	SetPacingRate(bytesPerSecond int64)
 }
 
 // -------------------------------------------------------------------
@@ -82,6 +85,8 @@ const (
 
 	waitBeforeSendPaddingOnMute = 100 * time.Millisecond
 	maxPaddingOnMuteDuration    = 5 * time.Second
+
+	mediaPacerInterval = 5 * time.Millisecond
 )
 
 // -------------------------------------------------------------------
@@ -201,6 +206,17 @@ type DownTrackStreamAllocatorListener interface {
 
 type ReceiverReportListener func(dt *DownTrack, report *rtcp.ReceiverReport)
 
+type DownTrackOpts func(d *DownTrack) *DownTrack
+
+// WithSequencerMaxRetransmissions sets the maximum number of times a packet can be retransmitted,
+// useful for lossy links where the default is not sufficient.
+func WithSequencerMaxRetransmissions(n int) DownTrackOpts {
+	return func(d *DownTrack) *DownTrack {
+		d.sequencerMaxAck = n
+		return d
+	}
+}
+
 type DowntrackParams struct {
 	Codecs            []webrtc.RTPCodecParameters
 	Source            livekit.TrackSource
@@ -233,6 +249,8 @@ type DownTrack struct {
 	payloadType uint8
 	sequencer   *sequencer
 
+	sequencerMaxAck int
+
 	forwarder *Forwarder
 
 	upstreamCodecs            []webrtc.RTPCodecParameters
@@ -271,6 +289,9 @@ type DownTrack struct {
 
 	activePaddingOnMuteUpTrack atomic.Bool
 
+	// bandwidth granted to this down track regardless of channel estimate, 0 when not overridden
+	bandwidthOverride atomic.Int64
+
 	streamAllocatorLock             sync.RWMutex
 	streamAllocatorListener         DownTrackStreamAllocatorListener
 	streamAllocatorReportGeneration int
@@ -284,6 +305,10 @@ type DownTrack struct {
 
 	pacer pacer.Pacer
 
+	// paces media packets of this down track when a pacing rate is set
+	mediaPacerLock sync.RWMutex
+	mediaPacer     pacer.Pacer
+
 	maxLayerNotifierChMu     sync.RWMutex
 	maxLayerNotifierCh       chan string
 	maxLayerNotifierChClosed bool
@@ -302,7 +327,7 @@ type DownTrack struct {
 }
 
 // NewDownTrack returns a DownTrack.
-func NewDownTrack(params DowntrackParams) (*DownTrack, error) {
+func NewDownTrack(params DowntrackParams, opts ...DownTrackOpts) (*DownTrack, error) {
 	codecs := params.Codecs
 	var kind webrtc.RTPCodecType
 	switch {
@@ -325,6 +350,9 @@ func NewDownTrack(params DowntrackParams) (*DownTrack, error) {
 		keyFrameRequesterCh: make(chan struct{}, 1),
 		createdAt:           time.Now().UnixNano(),
 	}
+	for _, opt := range opts {
+		d = opt(d)
+	}
 	d.forwarder = NewForwarder(
 		d.kind,
 		params.Logger,
@@ -414,7 +442,9 @@ func (d *DownTrack) Bind(t webrtc.TrackLocalContext) (webrtc.RTPCodecParameters,
 		d.rtcpReader = rr
 	}
 
-	d.sequencer = newSequencer(d.params.MaxTrack, d.kind == webrtc.RTPCodecTypeVideo, d.params.Logger)
+	d.sequencer = newSequencer(d.params.MaxTrack, d.kind == webrtc.RTPCodecTypeVideo, d.sequencerMaxAck, d.params.Logger)
+	d.sequencer.setH264(d.mime == "video/h264")
+	d.sequencer.setRetransmissionTimeout(d.params.Receiver.GetRetransmissionTimeout())
 
 	d.codec = codec.RTPCodecCapability
 	if d.onBinding != nil {
@@ -793,11 +823,13 @@ func (d *DownTrack) WriteRTP(extPkt *buffer.ExtPacket, layer int32) error {
 			tp.rtp.extSequenceNumber,
 			tp.rtp.extTimestamp,
 			hdr.Marker,
+			extPkt.KeyFrame,
 			int8(layer),
 			payload[:len(tp.codecBytes)],
 			tp.incomingHeaderSize,
 			tp.ddBytes,
 			actBytes,
+			payload,
 		)
 	}
 
@@ -813,7 +845,7 @@ func (d *DownTrack) WriteRTP(extPkt *buffer.ExtPacket, layer int32) error {
 			tp:                &tp,
 		},
 	)
-	d.pacer.Enqueue(pacer.Packet{
+	d.getMediaPacer(extPkt.KeyFrame).Enqueue(pacer.Packet{
 		Header:             hdr,
 		Extensions:         extensions,
 		Payload:            payload,
@@ -826,6 +858,21 @@ func (d *DownTrack) WriteRTP(extPkt *buffer.ExtPacket, layer int32) error {
 	return nil
 }
 
+// key frame packets bypass media pacing so that layer switches are not delayed
+func (d *DownTrack) getMediaPacer(isKeyFrame bool) pacer.Pacer {
+	if isKeyFrame {
+		return d.pacer
+	}
+
+	d.mediaPacerLock.RLock()
+	defer d.mediaPacerLock.RUnlock()
+
+	if d.mediaPacer != nil {
+		return d.mediaPacer
+	}
+	return d.pacer
+}
+
 // WritePaddingRTP tries to write as many padding only RTP packets as necessary
 // to satisfy given size to the DownTrack
 func (d *DownTrack) WritePaddingRTP(bytesToSend int, paddingOnMute bool, forceMarker bool) int {
@@ -1048,6 +1095,7 @@ func (d *DownTrack) CloseWithFlush(flush bool) {
 	d.bindLock.Unlock()
 
 	d.connectionStats.Close()
+	d.SetPacingRate(0)
 
 	d.rtpStats.Stop()
 	d.params.Logger.Debugw("rtp stats",
@@ -1099,6 +1147,62 @@ func (d *DownTrack) SetMaxTemporalLayer(temporalLayer int32) {
 	}
 }
 
+// SetTemporalLayerCap caps the forwarded temporal layer irrespective of subscribed max temporal layer,
+// used by the receiver to limit frame rate or to reduce bitrate while the room bitrate cap is exceeded.
+func (d *DownTrack) SetTemporalLayerCap(temporalLayer int32) {
+	changed, maxLayer := d.forwarder.SetTemporalLayerCap(temporalLayer)
+	if !changed {
+		return
+	}
+
+	if sal := d.getStreamAllocatorListener(); sal != nil {
+		sal.OnSubscribedLayerChanged(d, maxLayer)
+	}
+}
+
+// SetPacingRate paces media packets of this down track with a leaky bucket at the given rate in bytes per second
+// to smooth out bursts of packets of a frame. Packets are queued and sent from the pacer's own goroutine,
+// so forwarding to other down tracks is not held up. 0 disables pacing.
+func (d *DownTrack) SetPacingRate(bytesPerSecond int64) {
+	d.mediaPacerLock.Lock()
+	defer d.mediaPacerLock.Unlock()
+
+	switch {
+	case bytesPerSecond <= 0 || d.isClosed.Load():
+		if d.mediaPacer != nil {
+			d.mediaPacer.Stop()
+			d.mediaPacer = nil
+		}
+
+	case d.mediaPacer != nil:
+		d.mediaPacer.SetBitrate(int(bytesPerSecond * 8))
+
+	default:
+		d.mediaPacer = pacer.NewLeakyBucket(d.params.Logger, mediaPacerInterval, int(bytesPerSecond*8))
+	}
+}
+
+// SetBandwidthOverride grants this down track the given bandwidth regardless of channel estimate.
+// An overridden down track is exempted from stream allocation and forwards the highest layer fitting
+// in the given bandwidth. A value <= 0 clears the override.
+func (d *DownTrack) SetBandwidthOverride(bps int64) {
+	if bps < 0 {
+		bps = 0
+	}
+	if d.bandwidthOverride.Swap(bps) == bps {
+		return
+	}
+
+	d.params.Logger.Debugw("bandwidth override changed", "bps", bps)
+	if sal := d.getStreamAllocatorListener(); sal != nil {
+		sal.OnSubscriptionChanged(d)
+	}
+}
+
+func (d *DownTrack) GetBandwidthOverride() int64 {
+	return d.bandwidthOverride.Load()
+}
+
 func (d *DownTrack) MaxLayer() buffer.VideoLayer {
 	return d.forwarder.MaxLayer()
 }
@@ -1255,6 +1359,9 @@ func (d *DownTrack) DistanceToDesired() float64 {
 
 func (d *DownTrack) AllocateOptimal(allowOvershoot bool) VideoAllocation {
 	al, brs := d.params.Receiver.GetLayeredBitrate()
+	if bps := d.bandwidthOverride.Load(); bps > 0 {
+		al = filterLayersByBitrate(al, brs, bps)
+	}
 	allocation := d.forwarder.AllocateOptimal(al, brs, allowOvershoot)
 	d.postKeyFrameRequestEvent()
 	d.maybeAddTransition(allocation.BandwidthNeeded, allocation.DistanceToDesired, allocation.PauseReason)
@@ -1858,6 +1965,12 @@ func (d *DownTrack) DebugInfo() map[string]interface{} {
 		"LastPli": d.rtpStats.LastPli(),
 	}
 	stats["RTPMunger"] = d.forwarder.RTPMungerDebugInfo()
+	if ratio := d.rtpStats.GetBandwidthUtilizationRatio(); ratio != 0 {
+		stats["BandwidthUtilizationRatio"] = ratio
+	}
+	if d.sequencer != nil {
+		stats["Sequencer"] = d.sequencer.GetDiagnostics()
+	}
 
 	senderReport := d.CreateSenderReport()
 	if senderReport != nil {
@@ -1880,6 +1993,14 @@ func (d *DownTrack) DebugInfo() map[string]interface{} {
 	}
 }
 
+// DumpSequencerCache returns the packets cached for retransmission, nil if retransmission is not supported
+func (d *DownTrack) DumpSequencerCache() []PacketMetaSummary {
+	if d.sequencer == nil {
+		return nil
+	}
+	return d.sequencer.Dump()
+}
+
 func (d *DownTrack) getExpectedRTPTimestamp(at time.Time) (uint64, error) {
 	return d.rtpStats.GetExpectedRTPTimestamp(at)
 }
@@ -1926,6 +2047,16 @@ func (d *DownTrack) GetNackStats() (totalPackets uint32, totalRepeatedNACKs uint
 	return
 }
 
+// UpdateBandwidthEstimate updates the estimated available bandwidth (in bps) from congestion control,
+// used to track the share of available bandwidth used by this down track.
+func (d *DownTrack) UpdateBandwidthEstimate(estimatedBitrate int64) {
+	d.rtpStats.UpdateBandwidthEstimate(estimatedBitrate)
+}
+
+func (d *DownTrack) GetBandwidthUtilizationRatio() float64 {
+	return d.rtpStats.GetBandwidthUtilizationRatio()
+}
+
 /* STREAM-ALLOCATOR-DATA
 func (d *DownTrack) GetAndResetBytesSent() (uint32, uint32) {
 	return d.bytesSent.Swap(0), d.bytesRetransmitted.Swap(0)
@@ -2109,3 +2240,30 @@ func (d *DownTrack) sendingPacket(hdr *rtp.Header, payloadSize int, spmd *sendPa
 }
 
 // -------------------------------------------------------------------------------
+
+// filterLayersByBitrate returns available layers with a bitrate not exceeding bps.
+// Layers without a measured bitrate are kept and the lowest available layer is kept if none fit.
+func filterLayersByBitrate(availableLayers []int32, brs Bitrates, bps int64) []int32 {
+	filtered := make([]int32, 0, len(availableLayers))
+	lowest := buffer.InvalidLayerSpatial
+	for _, al := range availableLayers {
+		if al < 0 || int(al) >= len(brs) {
+			continue
+		}
+		if lowest == buffer.InvalidLayerSpatial || al < lowest {
+			lowest = al
+		}
+
+		layerBitrate := int64(0)
+		for _, br := range brs[al] {
+			layerBitrate = max(layerBitrate, br)
+		}
+		if layerBitrate <= bps {
+			filtered = append(filtered, al)
+		}
+	}
+	if len(filtered) == 0 && lowest != buffer.InvalidLayerSpatial {
+		filtered = append(filtered, lowest)
+	}
+	return filtered
+}
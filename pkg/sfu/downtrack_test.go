@@ -0,0 +1,38 @@
+// Copyright 2024 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sfu
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilterLayersByBitrate(t *testing.T) {
+	brs := Bitrates{
+		{100_000, 150_000, 200_000},
+		{300_000, 450_000, 600_000},
+		{1_000_000, 0, 0},
+	}
+
+	require.Equal(t, []int32{0, 1, 2}, filterLayersByBitrate([]int32{0, 1, 2}, brs, 2_000_000))
+	require.Equal(t, []int32{0, 1}, filterLayersByBitrate([]int32{0, 1, 2}, brs, 600_000))
+	// lowest available layer is kept if none fit
+	require.Equal(t, []int32{1}, filterLayersByBitrate([]int32{1, 2}, brs, 50_000))
+	// layers without measured bitrate are kept
+	brs[2] = [len(brs[2])]int64{}
+	require.Equal(t, []int32{0, 2}, filterLayersByBitrate([]int32{0, 1, 2}, brs, 200_000))
+	require.Empty(t, filterLayersByBitrate(nil, brs, 150_000))
+}
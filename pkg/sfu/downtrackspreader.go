@@ -86,6 +86,13 @@ func (d *DownTrackSpreader) HasDownTrack(subscriberID livekit.ParticipantID) boo
 	return ok
 }
 
+func (d *DownTrackSpreader) GetDownTrack(subscriberID livekit.ParticipantID) TrackSender {
+	d.downTrackMu.RLock()
+	defer d.downTrackMu.RUnlock()
+
+	return d.downTracks[subscriberID]
+}
+
 func (d *DownTrackSpreader) Broadcast(writer func(TrackSender)) int {
 	downTracks := d.GetDownTracks()
 	if len(downTracks) == 0 {
@@ -32,6 +32,7 @@ import (
 	"github.com/livekit/livekit-server/pkg/sfu/buffer"
 	"github.com/livekit/livekit-server/pkg/sfu/codecmunger"
 	dd "github.com/livekit/livekit-server/pkg/sfu/rtpextension/dependencydescriptor"
+	"github.com/livekit/livekit-server/pkg/sfu/utils"
 	"github.com/livekit/livekit-server/pkg/sfu/videolayerselector"
 	"github.com/livekit/livekit-server/pkg/sfu/videolayerselector/temporallayerselector"
 )
@@ -250,6 +251,9 @@ type Forwarder struct {
 	rtpMunger *RTPMunger
 
 	vls videolayerselector.VideoLayerSelector
+	// max temporal layer of selector is the lower of subscribed max and cap
+	maxTemporalSubscribed int32
+	temporalLayerCap      int32
 
 	codecMunger codecmunger.CodecMunger
 }
@@ -270,6 +274,8 @@ func NewForwarder(
 		rtpMunger:               NewRTPMunger(logger),
 		vls:                     videolayerselector.NewNull(logger),
 		codecMunger:             codecmunger.NewNull(logger),
+		maxTemporalSubscribed:   buffer.DefaultMaxLayerTemporal,
+		temporalLayerCap:        buffer.DefaultMaxLayerTemporal,
 	}
 
 	if f.kind == webrtc.RTPCodecTypeVideo {
@@ -519,12 +525,31 @@ func (f *Forwarder) SetMaxTemporalLayer(temporalLayer int32) (bool, buffer.Video
 		return false, buffer.InvalidLayer
 	}
 
+	f.maxTemporalSubscribed = temporalLayer
+	return f.updateMaxTemporalLocked()
+}
+
+// SetTemporalLayerCap caps the max temporal layer irrespective of subscribed max temporal layer.
+func (f *Forwarder) SetTemporalLayerCap(temporalLayer int32) (bool, buffer.VideoLayer) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	if f.kind == webrtc.RTPCodecTypeAudio {
+		return false, buffer.InvalidLayer
+	}
+
+	f.temporalLayerCap = temporalLayer
+	return f.updateMaxTemporalLocked()
+}
+
+func (f *Forwarder) updateMaxTemporalLocked() (bool, buffer.VideoLayer) {
+	temporalLayer := min(f.maxTemporalSubscribed, f.temporalLayerCap)
 	existingMax := f.vls.GetMax()
 	if temporalLayer == existingMax.Temporal {
 		return false, existingMax
 	}
 
-	f.logger.Debugw("setting max temporal layer", "layer", temporalLayer)
+	f.logger.Debugw("setting max temporal layer", "layer", temporalLayer, "subscribed", f.maxTemporalSubscribed, "cap", f.temporalLayerCap)
 	f.vls.SetMaxTemporal(temporalLayer)
 	return true, f.vls.GetMax()
 }
@@ -1567,13 +1592,15 @@ func (f *Forwarder) GetTranslationParams(extPkt *buffer.ExtPacket, layer int32)
 	}, ErrUnknownKind
 }
 
-func (f *Forwarder) getRefLayerRTPTimestamp(ts uint32, refLayer, targetLayer int32) (uint32, error) {
+// getRefLayerRTPTimestamp maps an extended timestamp of target layer to the reference layer.
+// Offset between layers is calculated in 32-bit modular space and applied as a signed offset to the extended timestamp.
+func (f *Forwarder) getRefLayerRTPTimestamp(ets uint64, refLayer, targetLayer int32) (uint64, error) {
 	if refLayer < 0 || int(refLayer) > len(f.refInfos) || targetLayer < 0 || int(targetLayer) > len(f.refInfos) {
 		return 0, fmt.Errorf("invalid layer(s), refLayer: %d, targetLayer: %d", refLayer, targetLayer)
 	}
 
 	if refLayer == targetLayer || f.refIsSVC {
-		return ts, nil
+		return ets, nil
 	}
 
 	srRef := f.refInfos[refLayer].senderReport
@@ -1591,7 +1618,7 @@ func (f *Forwarder) getRefLayerRTPTimestamp(ts uint32, refLayer, targetLayer int
 	// now both layers' time stamp refer to the same NTP time and the diff is the offset between the layers
 	offset := srRef.RTPTimestamp - normalizedOtherTS
 
-	return ts + offset, nil
+	return uint64(int64(ets) + int64(int32(offset))), nil
 }
 
 func (f *Forwarder) processSourceSwitch(extPkt *buffer.ExtPacket, layer int32) error {
@@ -1670,8 +1697,7 @@ func (f *Forwarder) processSourceSwitch(extPkt *buffer.ExtPacket, layer int32) e
 	refTS := uint32(extRefTS)
 	switchingAt := time.Now()
 	if !f.skipReferenceTS {
-		var err error
-		refTS, err = f.getRefLayerRTPTimestamp(extPkt.Packet.Timestamp, f.referenceLayerSpatial, layer)
+		refExtTS, err := f.getRefLayerRTPTimestamp(extPkt.ExtTimestamp, f.referenceLayerSpatial, layer)
 		if err != nil {
 			// error out if refTS is not available. It can happen when there is no sender report
 			// for the layer being switched to. Can especially happen at the start of the track when layer switches are
@@ -1680,16 +1706,18 @@ func (f *Forwarder) processSourceSwitch(extPkt *buffer.ExtPacket, layer int32) e
 			// on how often publisher/remote side sends RTCP sender report.
 			return err
 		}
+		// extended timestamps of layers are not in the same space, only the lower 32 bits are aligned
+		refTS = uint32(refExtTS)
 	}
 
 	// adjust extRefTS to current packet's timestamp mapped to that of reference layer's
 	extRefTS = (extRefTS & 0xFFFF_FFFF_0000_0000) + uint64(refTS) + f.dummyStartTSOffset
 	lastTS := uint32(extLastTS)
 	refTS = uint32(extRefTS)
-	if (refTS-lastTS) < 1<<31 && refTS < lastTS {
+	if utils.Distance(lastTS, refTS) >= 0 && refTS < lastTS {
 		extRefTS += (1 << 32)
 	}
-	if (lastTS-refTS) < 1<<31 && lastTS < refTS && extRefTS >= 1<<32 {
+	if utils.Distance(refTS, lastTS) >= 0 && lastTS < refTS && extRefTS >= 1<<32 {
 		extRefTS -= (1 << 32)
 	}
 
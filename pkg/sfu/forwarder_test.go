@@ -16,10 +16,12 @@ package sfu
 
 import (
 	"testing"
+	"time"
 
 	"github.com/pion/webrtc/v3"
 	"github.com/stretchr/testify/require"
 
+	"github.com/livekit/mediatransportutil"
 	"github.com/livekit/protocol/logger"
 
 	"github.com/livekit/livekit-server/pkg/sfu/buffer"
@@ -123,6 +125,32 @@ func TestForwarderLayersVideo(t *testing.T) {
 	require.Equal(t, expectedLayers, f.MaxLayer())
 }
 
+func TestForwarderTemporalLayerCap(t *testing.T) {
+	f := newForwarder(testutils.TestVP8Codec, webrtc.RTPCodecTypeVideo)
+	f.SetMaxSpatialLayer(buffer.DefaultMaxLayerSpatial)
+
+	// cap lowers max temporal layer
+	changed, maxLayer := f.SetTemporalLayerCap(0)
+	require.True(t, changed)
+	require.Equal(t, int32(0), maxLayer.Temporal)
+
+	// subscription change above cap does not lift it
+	changed, maxLayer = f.SetMaxTemporalLayer(1)
+	require.False(t, changed)
+	require.Equal(t, int32(0), maxLayer.Temporal)
+
+	// removing cap restores subscribed max temporal layer
+	changed, maxLayer = f.SetTemporalLayerCap(buffer.DefaultMaxLayerTemporal)
+	require.True(t, changed)
+	require.Equal(t, int32(1), maxLayer.Temporal)
+
+	// audio is not capped
+	f = newForwarder(testutils.TestOpusCodec, webrtc.RTPCodecTypeAudio)
+	changed, maxLayer = f.SetTemporalLayerCap(0)
+	require.False(t, changed)
+	require.Equal(t, buffer.InvalidLayer, maxLayer)
+}
+
 func TestForwarderAllocateOptimal(t *testing.T) {
 	f := newForwarder(testutils.TestVP8Codec, webrtc.RTPCodecTypeVideo)
 
@@ -2007,3 +2035,33 @@ func TestForwarderGetPaddingVP8(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, marshalledVP8, buf)
 }
+
+func TestForwarderGetRefLayerRTPTimestamp(t *testing.T) {
+	f := newForwarder(testutils.TestVP8Codec, webrtc.RTPCodecTypeVideo)
+
+	// same layer is passed through
+	ets, err := f.getRefLayerRTPTimestamp(0x1_0000_1000, 1, 1)
+	require.NoError(t, err)
+	require.Equal(t, uint64(0x1_0000_1000), ets)
+
+	// sender reports not available
+	_, err = f.getRefLayerRTPTimestamp(0x1_0000_1000, 0, 1)
+	require.Error(t, err)
+
+	_, err = f.getRefLayerRTPTimestamp(0x1_0000_1000, -1, 1)
+	require.Error(t, err)
+
+	// layer 1 is 3000 ticks behind layer 0 at the same NTP time
+	ntp := mediatransportutil.ToNtpTime(time.Now())
+	f.refInfos[0].senderReport = &buffer.RTCPSenderReportData{NTPTimestamp: ntp, RTPTimestamp: 10000}
+	f.refInfos[1].senderReport = &buffer.RTCPSenderReportData{NTPTimestamp: ntp, RTPTimestamp: 7000}
+	ets, err = f.getRefLayerRTPTimestamp(0x1_0000_1000, 0, 1)
+	require.NoError(t, err)
+	require.Equal(t, uint64(0x1_0000_1000+3000), ets)
+
+	// negative offset across a wrap boundary stays in the extended space
+	f.refInfos[1].senderReport = &buffer.RTCPSenderReportData{NTPTimestamp: ntp, RTPTimestamp: 13000}
+	ets, err = f.getRefLayerRTPTimestamp(0x1_0000_0100, 0, 1)
+	require.NoError(t, err)
+	require.Equal(t, uint64(0x1_0000_0100-3000), ets)
+}
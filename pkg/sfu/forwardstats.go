@@ -2,6 +2,8 @@ package sfu
 
 import (
 	"fmt"
+	"math/rand"
+	"sort"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -11,24 +13,58 @@ import (
 	"github.com/livekit/protocol/utils"
 )
 
+const (
+	latencyReservoirSize = 1024
+)
+
 type ForwardStats struct {
 	lock       sync.Mutex
 	lastLeftMs atomic.Int64
 	latency    *utils.LatencyAggregate
 	closeCh    chan struct{}
+
+	// LatencyAggregate does not support percentiles, use sampled latencies for those.
+	// Samples are kept for two windows, rotating every window length so that
+	// percentiles cover between one and two windows of history.
+	latencyUpdateInterval time.Duration
+	latencyWindowLength   time.Duration
+	samplesStart          time.Time
+	samples               *latencyReservoir
+	prevSamples           *latencyReservoir
+
+	// throughput is measured over windows of latency window length, rotated on read
+	bytesForwarded         atomic.Uint64
+	packetsForwarded       atomic.Uint64
+	throughputStart        time.Time
+	throughputStartBytes   uint64
+	throughputStartPackets uint64
+	isThroughputValid      bool
+	bytesPerSec            float64
+	packetsPerSec          float64
 }
 
 func NewForwardStats(latencyUpdateInterval, reportInterval, latencyWindowLength time.Duration) *ForwardStats {
 	s := &ForwardStats{
-		latency: utils.NewLatencyAggregate(latencyUpdateInterval, latencyWindowLength),
-		closeCh: make(chan struct{}),
+		latency:               utils.NewLatencyAggregate(latencyUpdateInterval, latencyWindowLength),
+		closeCh:               make(chan struct{}),
+		latencyUpdateInterval: latencyUpdateInterval,
+		latencyWindowLength:   latencyWindowLength,
+		samplesStart:          time.Now(),
+		samples:               newLatencyReservoir(latencyReservoirSize),
+		prevSamples:           newLatencyReservoir(latencyReservoirSize),
+		throughputStart:       time.Now(),
 	}
 
-	go s.report(reportInterval)
+	if reportInterval > 0 {
+		go s.report(reportInterval)
+	}
 	return s
 }
 
-func (s *ForwardStats) Update(arrival, left time.Time) {
+func (s *ForwardStats) Update(arrival, left time.Time, size int) {
+	s.bytesForwarded.Add(uint64(size))
+	s.packetsForwarded.Add(1)
+
 	transit := left.Sub(arrival)
 
 	// ignore if transit is too large or negative, this could happen if system time is adjusted
@@ -44,6 +80,13 @@ func (s *ForwardStats) Update(arrival, left time.Time) {
 	s.lock.Lock()
 	defer s.lock.Unlock()
 	s.latency.Update(time.Duration(arrival.UnixNano()), float64(transit))
+
+	if left.Sub(s.samplesStart) > s.latencyWindowLength {
+		s.samples, s.prevSamples = s.prevSamples, s.samples
+		s.samples.reset()
+		s.samplesStart = left
+	}
+	s.samples.add(transit)
 }
 
 func (s *ForwardStats) GetStats() (latency, jitter time.Duration) {
@@ -68,6 +111,67 @@ func (s *ForwardStats) GetLastStats(duration time.Duration) (latency, jitter tim
 	return time.Duration(w.Mean()), time.Duration(w.StdDev())
 }
 
+// GetPercentileLatency returns the latency at given percentile (in the range [0, 100]).
+func (s *ForwardStats) GetPercentileLatency(p float64) time.Duration {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	samples := make([]time.Duration, 0, len(s.samples.samples)+len(s.prevSamples.samples))
+	samples = append(samples, s.samples.samples...)
+	samples = append(samples, s.prevSamples.samples...)
+	return getPercentile(samples, p)
+}
+
+// GetThroughput returns bytes and packets forwarded per second over the last completed window of
+// latency window length, or over the elapsed part of the first window till one completes.
+func (s *ForwardStats) GetThroughput() (bytesPerSec float64, packetsPerSec float64) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	now := time.Now()
+	elapsed := now.Sub(s.throughputStart)
+	bytes := s.bytesForwarded.Load()
+	packets := s.packetsForwarded.Load()
+	if elapsed >= s.latencyWindowLength {
+		s.bytesPerSec = float64(bytes-s.throughputStartBytes) / elapsed.Seconds()
+		s.packetsPerSec = float64(packets-s.throughputStartPackets) / elapsed.Seconds()
+		s.isThroughputValid = true
+
+		s.throughputStart = now
+		s.throughputStartBytes = bytes
+		s.throughputStartPackets = packets
+	}
+	if s.isThroughputValid {
+		return s.bytesPerSec, s.packetsPerSec
+	}
+
+	if elapsed <= 0 {
+		return 0, 0
+	}
+	return float64(bytes-s.throughputStartBytes) / elapsed.Seconds(), float64(packets-s.throughputStartPackets) / elapsed.Seconds()
+}
+
+// Reset clears all accumulated latency and throughput history.
+func (s *ForwardStats) Reset() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.latency = utils.NewLatencyAggregate(s.latencyUpdateInterval, s.latencyWindowLength)
+	s.lastLeftMs.Store(0)
+	s.samples.reset()
+	s.prevSamples.reset()
+	s.samplesStart = time.Now()
+
+	s.bytesForwarded.Store(0)
+	s.packetsForwarded.Store(0)
+	s.throughputStart = s.samplesStart
+	s.throughputStartBytes = 0
+	s.throughputStartPackets = 0
+	s.isThroughputValid = false
+	s.bytesPerSec = 0
+	s.packetsPerSec = 0
+}
+
 func (s *ForwardStats) Stop() {
 	close(s.closeCh)
 }
@@ -84,6 +188,59 @@ func (s *ForwardStats) report(reportInterval time.Duration) {
 			latencySlow, jitterSlow := s.GetStats()
 			prometheus.RecordForwardJitter(uint32(jitter/time.Millisecond), uint32(jitterSlow/time.Millisecond))
 			prometheus.RecordForwardLatency(uint32(latency/time.Millisecond), uint32(latencySlow/time.Millisecond))
+			prometheus.RecordForwardLatencyPercentiles(
+				uint32(s.GetPercentileLatency(50)/time.Millisecond),
+				uint32(s.GetPercentileLatency(95)/time.Millisecond),
+				uint32(s.GetPercentileLatency(99)/time.Millisecond),
+			)
+			prometheus.RecordForwardThroughput(s.GetThroughput())
 		}
 	}
 }
+
+// ------------------------------------------------
+
+type latencyReservoir struct {
+	size    int
+	seen    int
+	samples []time.Duration
+}
+
+func newLatencyReservoir(size int) *latencyReservoir {
+	return &latencyReservoir{
+		size:    size,
+		samples: make([]time.Duration, 0, size),
+	}
+}
+
+func (l *latencyReservoir) add(latency time.Duration) {
+	l.seen++
+	if len(l.samples) < l.size {
+		l.samples = append(l.samples, latency)
+		return
+	}
+
+	if idx := rand.Intn(l.seen); idx < l.size {
+		l.samples[idx] = latency
+	}
+}
+
+func (l *latencyReservoir) reset() {
+	l.seen = 0
+	l.samples = l.samples[:0]
+}
+
+func getPercentile(samples []time.Duration, p float64) time.Duration {
+	if len(samples) == 0 {
+		return 0
+	}
+
+	sort.Slice(samples, func(i, j int) bool { return samples[i] < samples[j] })
+	idx := int(p / 100 * float64(len(samples)-1))
+	if idx < 0 {
+		idx = 0
+	} else if idx >= len(samples) {
+		idx = len(samples) - 1
+	}
+	return samples[idx]
+}
@@ -0,0 +1,95 @@
+// Copyright 2024 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sfu
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestForwardStatsPercentileLatency(t *testing.T) {
+	s := NewForwardStats(time.Second, time.Hour, 10*time.Second)
+	defer s.Stop()
+
+	require.Zero(t, s.GetPercentileLatency(50))
+
+	now := time.Now()
+	for i := 1; i <= 100; i++ {
+		left := now.Add(time.Duration(i) * time.Millisecond)
+		s.Update(left.Add(-time.Duration(i)*time.Millisecond), left, 100)
+	}
+
+	require.Equal(t, 50*time.Millisecond, s.GetPercentileLatency(50))
+	require.Equal(t, 95*time.Millisecond, s.GetPercentileLatency(95))
+	require.Equal(t, 99*time.Millisecond, s.GetPercentileLatency(99))
+	require.Equal(t, 100*time.Millisecond, s.GetPercentileLatency(100))
+}
+
+func TestForwardStatsReset(t *testing.T) {
+	s := NewForwardStats(time.Second, 0, 10*time.Second)
+
+	now := time.Now()
+	s.Update(now.Add(-20*time.Millisecond), now, 100)
+	latency, _ := s.GetStats()
+	require.Equal(t, 20*time.Millisecond, latency)
+	require.Equal(t, 20*time.Millisecond, s.GetPercentileLatency(50))
+
+	s.Reset()
+	latency, _ = s.GetStats()
+	require.Zero(t, latency)
+	require.Zero(t, s.GetPercentileLatency(50))
+
+	// updates older than the last one before reset are accepted again
+	s.Update(now.Add(-10*time.Millisecond), now.Add(-5*time.Millisecond), 100)
+	require.Equal(t, 5*time.Millisecond, s.GetPercentileLatency(50))
+}
+
+func TestForwardStatsThroughput(t *testing.T) {
+	s := NewForwardStats(10*time.Millisecond, 0, 50*time.Millisecond)
+
+	now := time.Now()
+	for i := 0; i < 10; i++ {
+		s.Update(now, now, 100)
+	}
+
+	// first window not complete, rate over elapsed part
+	bytesPerSec, packetsPerSec := s.GetThroughput()
+	require.Greater(t, packetsPerSec, 0.0)
+	require.InDelta(t, 100*packetsPerSec, bytesPerSec, 1e-6)
+
+	time.Sleep(60 * time.Millisecond)
+	bytesPerSec, packetsPerSec = s.GetThroughput()
+	require.Greater(t, packetsPerSec, 0.0)
+	require.LessOrEqual(t, packetsPerSec, 10/0.06)
+	require.InDelta(t, 100*packetsPerSec, bytesPerSec, 1e-6)
+
+	// rate of last completed window is kept till the next one completes
+	again, _ := s.GetThroughput()
+	require.Equal(t, bytesPerSec, again)
+
+	// nothing forwarded in the next window
+	time.Sleep(60 * time.Millisecond)
+	bytesPerSec, packetsPerSec = s.GetThroughput()
+	require.Zero(t, bytesPerSec)
+	require.Zero(t, packetsPerSec)
+
+	s.Update(now, now, 100)
+	s.Reset()
+	bytesPerSec, packetsPerSec = s.GetThroughput()
+	require.Zero(t, bytesPerSec)
+	require.Zero(t, packetsPerSec)
+}
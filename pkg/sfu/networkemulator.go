@@ -0,0 +1,170 @@
+// Copyright 2024 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sfu
+
+import (
+	"io"
+	"math"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/livekit/mediatransportutil/pkg/bucket"
+
+	"github.com/livekit/livekit-server/pkg/sfu/buffer"
+)
+
+const (
+	networkEmulatorQueueSize = 1024
+)
+
+type NetworkEmulatorParams struct {
+	// probability [0, 1] of dropping a packet
+	LossProbability float64
+	// number of packets held back and released in random order, 0 disables reordering
+	ReorderDepth int
+	// median and shape of log-normal per packet latency, 0 median disables latency
+	LatencyMedian time.Duration
+	LatencySigma  float64
+	// seed for random decisions, 0 uses current time
+	Seed int64
+}
+
+// ExtPacketReader is the read side of a buffer.Buffer
+type ExtPacketReader interface {
+	ReadExtended(buf []byte) (*buffer.ExtPacket, error)
+}
+
+// NetworkEmulator impairs packets read from a buffer with loss, reordering and latency, for testing only
+type NetworkEmulator struct {
+	params NetworkEmulatorParams
+
+	lock sync.Mutex
+	rng  *rand.Rand
+}
+
+func NewNetworkEmulator(params NetworkEmulatorParams) *NetworkEmulator {
+	seed := params.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	return &NetworkEmulator{
+		params: params,
+		rng:    rand.New(rand.NewSource(seed)),
+	}
+}
+
+// Wrap returns a reader delivering packets of the given reader after applying impairments
+func (n *NetworkEmulator) Wrap(reader ExtPacketReader) ExtPacketReader {
+	e := &emulatedReader{
+		emulator: n,
+		source:   reader,
+		packets:  make(chan emulatedPacket, networkEmulatorQueueSize),
+	}
+	go e.run()
+	return e
+}
+
+func (n *NetworkEmulator) shouldDrop() bool {
+	if n.params.LossProbability <= 0 {
+		return false
+	}
+
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	return n.rng.Float64() < n.params.LossProbability
+}
+
+func (n *NetworkEmulator) pickHeld(numHeld int) int {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	return n.rng.Intn(numHeld)
+}
+
+func (n *NetworkEmulator) latency() time.Duration {
+	if n.params.LatencyMedian <= 0 {
+		return 0
+	}
+
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	return time.Duration(float64(n.params.LatencyMedian) * math.Exp(n.params.LatencySigma*n.rng.NormFloat64()))
+}
+
+// ------------------------------------------------
+
+type emulatedPacket struct {
+	pkt       *buffer.ExtPacket
+	deliverAt time.Time
+}
+
+type emulatedReader struct {
+	emulator *NetworkEmulator
+	source   ExtPacketReader
+	packets  chan emulatedPacket
+}
+
+func (e *emulatedReader) run() {
+	defer close(e.packets)
+
+	var held []*buffer.ExtPacket
+	for {
+		pkt, err := e.source.ReadExtended(make([]byte, bucket.MaxPktSize))
+		if err != nil {
+			for _, h := range held {
+				e.deliver(h)
+			}
+			return
+		}
+
+		if e.emulator.shouldDrop() {
+			continue
+		}
+
+		if e.emulator.params.ReorderDepth <= 0 {
+			e.deliver(pkt)
+			continue
+		}
+
+		held = append(held, pkt)
+		if len(held) > e.emulator.params.ReorderDepth {
+			idx := e.emulator.pickHeld(len(held))
+			e.deliver(held[idx])
+			held = append(held[:idx], held[idx+1:]...)
+		}
+	}
+}
+
+func (e *emulatedReader) deliver(pkt *buffer.ExtPacket) {
+	e.packets <- emulatedPacket{
+		pkt:       pkt,
+		deliverAt: time.Now().Add(e.emulator.latency()),
+	}
+}
+
+func (e *emulatedReader) ReadExtended(_ []byte) (*buffer.ExtPacket, error) {
+	ep, ok := <-e.packets
+	if !ok {
+		return nil, io.EOF
+	}
+
+	if wait := time.Until(ep.deliverAt); wait > 0 {
+		time.Sleep(wait)
+	}
+	return ep.pkt, nil
+}
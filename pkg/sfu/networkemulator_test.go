@@ -0,0 +1,98 @@
+// Copyright 2024 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sfu
+
+import (
+	"io"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/livekit/livekit-server/pkg/sfu/buffer"
+)
+
+type sliceReader struct {
+	packets []*buffer.ExtPacket
+}
+
+func (s *sliceReader) ReadExtended(_ []byte) (*buffer.ExtPacket, error) {
+	if len(s.packets) == 0 {
+		return nil, io.EOF
+	}
+	pkt := s.packets[0]
+	s.packets = s.packets[1:]
+	return pkt, nil
+}
+
+func newSliceReader(numPackets int) *sliceReader {
+	s := &sliceReader{}
+	for i := 0; i < numPackets; i++ {
+		s.packets = append(s.packets, &buffer.ExtPacket{ExtSequenceNumber: uint64(i)})
+	}
+	return s
+}
+
+func readAll(t *testing.T, reader ExtPacketReader) []uint64 {
+	var sns []uint64
+	for {
+		pkt, err := reader.ReadExtended(nil)
+		if err == io.EOF {
+			return sns
+		}
+		require.NoError(t, err)
+		sns = append(sns, pkt.ExtSequenceNumber)
+	}
+}
+
+func TestNetworkEmulator(t *testing.T) {
+	t.Run("passthrough", func(t *testing.T) {
+		ne := NewNetworkEmulator(NetworkEmulatorParams{})
+		sns := readAll(t, ne.Wrap(newSliceReader(100)))
+		require.Len(t, sns, 100)
+		require.True(t, sort.SliceIsSorted(sns, func(i, j int) bool { return sns[i] < sns[j] }))
+	})
+
+	t.Run("loss", func(t *testing.T) {
+		ne := NewNetworkEmulator(NetworkEmulatorParams{LossProbability: 1.0})
+		require.Empty(t, readAll(t, ne.Wrap(newSliceReader(100))))
+
+		ne = NewNetworkEmulator(NetworkEmulatorParams{LossProbability: 0.5, Seed: 1})
+		sns := readAll(t, ne.Wrap(newSliceReader(1000)))
+		require.Greater(t, len(sns), 400)
+		require.Less(t, len(sns), 600)
+	})
+
+	t.Run("reorder", func(t *testing.T) {
+		ne := NewNetworkEmulator(NetworkEmulatorParams{ReorderDepth: 4, Seed: 1})
+		sns := readAll(t, ne.Wrap(newSliceReader(100)))
+		require.Len(t, sns, 100)
+		require.False(t, sort.SliceIsSorted(sns, func(i, j int) bool { return sns[i] < sns[j] }))
+
+		// every packet is delivered exactly once
+		sort.Slice(sns, func(i, j int) bool { return sns[i] < sns[j] })
+		for i, sn := range sns {
+			require.Equal(t, uint64(i), sn)
+		}
+	})
+
+	t.Run("latency", func(t *testing.T) {
+		ne := NewNetworkEmulator(NetworkEmulatorParams{LatencyMedian: 20 * time.Millisecond, LatencySigma: 0.1, Seed: 1})
+		start := time.Now()
+		require.Len(t, readAll(t, ne.Wrap(newSliceReader(1))), 1)
+		require.GreaterOrEqual(t, time.Since(start), 10*time.Millisecond)
+	})
+}
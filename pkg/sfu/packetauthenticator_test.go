@@ -0,0 +1,72 @@
+// Copyright 2024 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sfu
+
+import (
+	"testing"
+
+	"github.com/pion/rtp"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPacketAuthenticator(t *testing.T) {
+	const extensionID = 5
+	key := []byte("test-key")
+	signer := newPacketAuthenticator(key, extensionID)
+
+	newPacket := func() *rtp.Packet {
+		return &rtp.Packet{
+			Header: rtp.Header{
+				Version:        2,
+				PayloadType:    96,
+				SequenceNumber: 1234,
+				Timestamp:      567890,
+				SSRC:           0xdeadbeef,
+			},
+			Payload: []byte{0x01, 0x02, 0x03, 0x04},
+		}
+	}
+
+	pkt := newPacket()
+	require.NoError(t, pkt.SetExtension(extensionID, signer.computeTag(pkt)))
+
+	verifier := newPacketAuthenticator(key, extensionID)
+	require.True(t, verifier.Verify(pkt))
+
+	// survives marshal/unmarshal
+	marshalled, err := pkt.Marshal()
+	require.NoError(t, err)
+	var unmarshalled rtp.Packet
+	require.NoError(t, unmarshalled.Unmarshal(marshalled))
+	require.True(t, verifier.Verify(&unmarshalled))
+
+	// missing tag
+	require.False(t, verifier.Verify(newPacket()))
+
+	// tampered payload
+	pkt.Payload[0] = 0xff
+	require.False(t, verifier.Verify(pkt))
+
+	// tampered header
+	pkt = newPacket()
+	require.NoError(t, pkt.SetExtension(extensionID, signer.computeTag(pkt)))
+	pkt.SequenceNumber++
+	require.False(t, verifier.Verify(pkt))
+
+	// wrong key
+	pkt = newPacket()
+	require.NoError(t, pkt.SetExtension(extensionID, signer.computeTag(pkt)))
+	require.False(t, newPacketAuthenticator([]byte("other-key"), extensionID).Verify(pkt))
+}
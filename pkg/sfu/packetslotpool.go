@@ -0,0 +1,61 @@
+// Copyright 2024 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sfu
+
+import (
+	"github.com/livekit/mediatransportutil/pkg/bucket"
+)
+
+// packetSlotPool is a fixed set of packet buffers for readers which hold packets read from a buffer,
+// buffers are allocated once and reused so that nothing is allocated per packet.
+type packetSlotPool struct {
+	bufs [][]byte
+	free chan int
+}
+
+func newPacketSlotPool(numSlots int) *packetSlotPool {
+	p := &packetSlotPool{
+		bufs: make([][]byte, numSlots),
+		free: make(chan int, numSlots),
+	}
+	storage := make([]byte, numSlots*bucket.MaxPktSize)
+	for slot := range p.bufs {
+		p.bufs[slot] = storage[slot*bucket.MaxPktSize : (slot+1)*bucket.MaxPktSize]
+		p.free <- slot
+	}
+	return p
+}
+
+// get waits for a free slot, returns false if done is closed while waiting
+func (p *packetSlotPool) get(done <-chan struct{}) (int, bool) {
+	select {
+	case slot := <-p.free:
+		return slot, true
+	case <-done:
+		return 0, false
+	}
+}
+
+func (p *packetSlotPool) put(slot int) {
+	p.free <- slot
+}
+
+func (p *packetSlotPool) buf(slot int) []byte {
+	return p.bufs[slot]
+}
+
+func (p *packetSlotPool) numFree() int {
+	return len(p.free)
+}
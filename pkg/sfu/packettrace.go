@@ -0,0 +1,56 @@
+// Copyright 2024 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sfu
+
+import (
+	"time"
+
+	"github.com/livekit/protocol/logger"
+)
+
+const (
+	// a traced packet is retransmitted from the buffer on NACK till it ages out of down track sequencers,
+	// the trace is kept long enough to cover that
+	packetTraceTimeout = 10 * time.Second
+)
+
+// packetTrace logs the path of a single packet, identified by SSRC and sequence number, through the receiver.
+type packetTrace struct {
+	ssrc      uint32
+	sn        uint16
+	expiresAt time.Time
+	logger    logger.Logger
+}
+
+func newPacketTrace(ssrc uint32, sn uint16, l logger.Logger) *packetTrace {
+	return &packetTrace{
+		ssrc:      ssrc,
+		sn:        sn,
+		expiresAt: time.Now().Add(packetTraceTimeout),
+		logger:    l.WithValues("traceSSRC", ssrc, "traceSN", sn),
+	}
+}
+
+func (p *packetTrace) matches(ssrc uint32, sn uint16) bool {
+	return p.ssrc == ssrc && p.sn == sn
+}
+
+func (p *packetTrace) isExpired(now time.Time) bool {
+	return now.After(p.expiresAt)
+}
+
+func (p *packetTrace) Log(step string, keysAndValues ...interface{}) {
+	p.logger.Infow("packet trace: "+step, keysAndValues...)
+}
@@ -21,6 +21,7 @@ import (
 
 	"github.com/livekit/livekit-server/pkg/sfu/buffer"
 	pd "github.com/livekit/livekit-server/pkg/sfu/rtpextension/playoutdelay"
+	"github.com/livekit/livekit-server/pkg/sfu/utils"
 	"github.com/livekit/protocol/logger"
 )
 
@@ -130,7 +131,7 @@ func (c *PlayoutDelayController) SetJitter(jitter uint32) {
 func (c *PlayoutDelayController) OnSeqAcked(seq uint16) {
 	c.lock.Lock()
 	defer c.lock.Unlock()
-	if PlayoutDelayState(c.state.Load()) == PlayoutDelaySending && (seq-c.sendingAtSeq) < 0x8000 {
+	if PlayoutDelayState(c.state.Load()) == PlayoutDelaySending && utils.Distance(c.sendingAtSeq, seq) >= 0 {
 		c.state.Store(int32(PlayoutDelayAcked))
 	}
 }
@@ -0,0 +1,169 @@
+// Copyright 2024 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sfu
+
+import (
+	"io"
+	"time"
+
+	"go.uber.org/atomic"
+
+	"github.com/livekit/protocol/livekit"
+
+	"github.com/livekit/livekit-server/pkg/sfu/buffer"
+)
+
+const (
+	// bounds memory held per layer, packets are released early when all slots are in use
+	playoutDelayNumSlots = 256
+)
+
+type playoutDelayPacket struct {
+	slot int
+	pkt  *buffer.ExtPacket
+}
+
+// playoutDelayReader is a jitter buffer holding packets read from a buffer for the minimum playout delay
+// before releasing them. When held packets span more than the maximum playout delay, packets are
+// released without waiting so that delay stays bounded.
+//
+// Packets are read into a fixed pool of slots and released from a timer driven goroutine,
+// reader only waits for released packets. All goroutines stop when done is closed.
+type playoutDelayReader struct {
+	source   ExtPacketReader
+	minDelay time.Duration
+	maxDelay time.Duration
+	done     <-chan struct{}
+
+	slots         *packetSlotPool
+	pending       chan playoutDelayPacket
+	released      chan playoutDelayPacket
+	arrived       chan struct{}
+	latestArrival atomic.Int64
+
+	// accessed only by reader
+	lastSlot int
+}
+
+func newPlayoutDelayReader(source ExtPacketReader, pd *livekit.PlayoutDelay, done <-chan struct{}) *playoutDelayReader {
+	minDelay := time.Duration(pd.Min) * time.Millisecond
+	maxDelay := time.Duration(pd.Max) * time.Millisecond
+	if maxDelay < minDelay {
+		maxDelay = minDelay
+	}
+	p := &playoutDelayReader{
+		source:   source,
+		minDelay: minDelay,
+		maxDelay: maxDelay,
+		done:     done,
+		slots:    newPacketSlotPool(playoutDelayNumSlots),
+		pending:  make(chan playoutDelayPacket, playoutDelayNumSlots),
+		released: make(chan playoutDelayPacket, playoutDelayNumSlots),
+		arrived:  make(chan struct{}, 1),
+		lastSlot: -1,
+	}
+	go p.readWorker()
+	go p.releaseWorker()
+	return p
+}
+
+func (p *playoutDelayReader) readWorker() {
+	defer close(p.pending)
+
+	for {
+		slot, ok := p.slots.get(p.done)
+		if !ok {
+			return
+		}
+
+		pkt, err := p.source.ReadExtended(p.slots.buf(slot))
+		if err != nil {
+			p.slots.put(slot)
+			return
+		}
+
+		p.latestArrival.Store(pkt.Arrival.UnixNano())
+		// cannot block, there are only as many packets in flight as there are slots
+		p.pending <- playoutDelayPacket{slot: slot, pkt: pkt}
+
+		select {
+		case p.arrived <- struct{}{}:
+		default:
+		}
+	}
+}
+
+func (p *playoutDelayReader) releaseWorker() {
+	defer close(p.released)
+
+	timer := time.NewTimer(p.minDelay)
+	timer.Stop()
+	defer timer.Stop()
+
+	for {
+		var hp playoutDelayPacket
+		select {
+		case <-p.done:
+			return
+		case pending, ok := <-p.pending:
+			if !ok {
+				return
+			}
+			hp = pending
+		}
+
+		// wait is re-evaluated on arrivals as the packet may have to be released early
+		for wait := p.getReleaseWait(hp.pkt); wait > 0; wait = p.getReleaseWait(hp.pkt) {
+			timer.Reset(wait)
+			select {
+			case <-p.done:
+				return
+			case <-p.arrived:
+				if !timer.Stop() {
+					<-timer.C
+				}
+			case <-timer.C:
+			}
+		}
+
+		p.released <- hp
+	}
+}
+
+func (p *playoutDelayReader) getReleaseWait(pkt *buffer.ExtPacket) time.Duration {
+	if p.slots.numFree() == 0 || time.Duration(p.latestArrival.Load()-pkt.Arrival.UnixNano()) >= p.maxDelay {
+		return 0
+	}
+	return time.Until(pkt.Arrival.Add(p.minDelay))
+}
+
+func (p *playoutDelayReader) ReadExtended(_ []byte) (*buffer.ExtPacket, error) {
+	// previously returned packet has been consumed, its slot can be reused
+	if p.lastSlot >= 0 {
+		p.slots.put(p.lastSlot)
+		p.lastSlot = -1
+	}
+
+	select {
+	case <-p.done:
+		return nil, io.EOF
+	case hp, ok := <-p.released:
+		if !ok {
+			return nil, io.EOF
+		}
+		p.lastSlot = hp.slot
+		return hp.pkt, nil
+	}
+}
@@ -0,0 +1,94 @@
+// Copyright 2024 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sfu
+
+import (
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/livekit/protocol/livekit"
+
+	"github.com/livekit/livekit-server/pkg/sfu/buffer"
+)
+
+func TestPlayoutDelayReader(t *testing.T) {
+	t.Run("holds for min delay", func(t *testing.T) {
+		source := newSliceReader(10)
+		now := time.Now()
+		for _, pkt := range source.packets {
+			pkt.Arrival = now
+		}
+
+		p := newPlayoutDelayReader(source, &livekit.PlayoutDelay{Enabled: true, Min: 50, Max: 200}, make(chan struct{}))
+		pkt, err := p.ReadExtended(nil)
+		require.NoError(t, err)
+		require.Equal(t, uint64(0), pkt.ExtSequenceNumber)
+		require.GreaterOrEqual(t, time.Since(now), 50*time.Millisecond)
+
+		require.Len(t, readAll(t, p), 9)
+	})
+
+	t.Run("releases when span exceeds max delay", func(t *testing.T) {
+		now := time.Now()
+		source := &sliceReader{
+			packets: []*buffer.ExtPacket{
+				{ExtSequenceNumber: 0, Arrival: now},
+				{ExtSequenceNumber: 1, Arrival: now.Add(time.Second)},
+			},
+		}
+
+		p := newPlayoutDelayReader(source, &livekit.PlayoutDelay{Enabled: true, Min: 500, Max: 600}, make(chan struct{}))
+
+		pkt, err := p.ReadExtended(nil)
+		require.NoError(t, err)
+		require.Equal(t, uint64(0), pkt.ExtSequenceNumber)
+		require.Less(t, time.Since(now), 500*time.Millisecond)
+	})
+	t.Run("reuses slots", func(t *testing.T) {
+		source := newSliceReader(4 * playoutDelayNumSlots)
+		for _, pkt := range source.packets {
+			pkt.Arrival = time.Now().Add(-time.Second)
+		}
+
+		p := newPlayoutDelayReader(source, &livekit.PlayoutDelay{Enabled: true, Min: 50, Max: 200}, make(chan struct{}))
+		require.Len(t, readAll(t, p), 4*playoutDelayNumSlots)
+	})
+
+	t.Run("stops when done", func(t *testing.T) {
+		source := &chanReader{packets: make(chan *buffer.ExtPacket, 10)}
+		source.packets <- &buffer.ExtPacket{ExtSequenceNumber: 0, Arrival: time.Now()}
+
+		done := make(chan struct{})
+		p := newPlayoutDelayReader(source, &livekit.PlayoutDelay{Enabled: true, Min: 5000, Max: 10000}, done)
+
+		readErr := make(chan error, 1)
+		go func() {
+			_, err := p.ReadExtended(nil)
+			readErr <- err
+		}()
+
+		// held packet is not released, reader is unblocked by done
+		close(done)
+		select {
+		case err := <-readErr:
+			require.ErrorIs(t, err, io.EOF)
+		case <-time.After(time.Second):
+			t.Fatal("reader not unblocked")
+		}
+	})
+}
@@ -0,0 +1,123 @@
+// Copyright 2024 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sfu
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	// deferred requests are flushed at the latest after this many windows from the first deferred request
+	pliCoalesceMaxWindows = 2
+)
+
+// pliCoalescer coalesces PLI requests from many subscribers into a single PLI to the publisher.
+//
+// The first request is sent right away and opens a window. Requests arriving while the window is open
+// are deferred and extend the window. When the window closes, one PLI is sent on behalf of all deferred
+// requests, which opens a new window. The window closes without sending when no requests were deferred.
+type pliCoalescer struct {
+	send      func()
+	getWindow func() time.Duration
+
+	lock       sync.Mutex
+	pending    bool
+	requested  bool
+	deferredAt time.Time
+	timer      *time.Timer
+	closed     bool
+}
+
+func newPLICoalescer(send func(), getWindow func() time.Duration) *pliCoalescer {
+	return &pliCoalescer{
+		send:      send,
+		getWindow: getWindow,
+	}
+}
+
+func (p *pliCoalescer) Request() {
+	window := p.getWindow()
+
+	p.lock.Lock()
+	if p.closed {
+		p.lock.Unlock()
+		return
+	}
+
+	if window <= 0 {
+		p.lock.Unlock()
+		p.send()
+		return
+	}
+
+	if p.pending {
+		now := time.Now()
+		if !p.requested {
+			p.requested = true
+			p.deferredAt = now
+		}
+		// extend the window, but do not hold deferred requests indefinitely
+		delay := min(window, p.deferredAt.Add(pliCoalesceMaxWindows*window).Sub(now))
+		p.timer.Reset(max(delay, 0))
+		p.lock.Unlock()
+		return
+	}
+
+	p.pending = true
+	p.startTimerLocked(window)
+	p.lock.Unlock()
+
+	p.send()
+}
+
+func (p *pliCoalescer) Close() {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	p.closed = true
+	if p.timer != nil {
+		p.timer.Stop()
+	}
+}
+
+func (p *pliCoalescer) startTimerLocked(window time.Duration) {
+	if p.timer == nil {
+		p.timer = time.AfterFunc(window, p.flush)
+	} else {
+		p.timer.Reset(window)
+	}
+}
+
+func (p *pliCoalescer) flush() {
+	window := p.getWindow()
+
+	p.lock.Lock()
+	if p.closed || !p.requested {
+		p.pending = false
+		p.lock.Unlock()
+		return
+	}
+
+	p.requested = false
+	if window > 0 {
+		p.startTimerLocked(window)
+	} else {
+		p.pending = false
+	}
+	p.lock.Unlock()
+
+	p.send()
+}
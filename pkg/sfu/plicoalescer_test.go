@@ -0,0 +1,89 @@
+// Copyright 2024 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sfu
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/atomic"
+)
+
+func TestPLICoalescer(t *testing.T) {
+	window := 50 * time.Millisecond
+	newCoalescer := func() (*pliCoalescer, *atomic.Int32) {
+		var numSent atomic.Int32
+		return newPLICoalescer(
+			func() { numSent.Inc() },
+			func() time.Duration { return window },
+		), &numSent
+	}
+
+	t.Run("first request is sent right away", func(t *testing.T) {
+		p, numSent := newCoalescer()
+		defer p.Close()
+
+		p.Request()
+		require.Equal(t, int32(1), numSent.Load())
+
+		// window closes without sending if nothing was deferred
+		time.Sleep(2 * window)
+		require.Equal(t, int32(1), numSent.Load())
+
+		p.Request()
+		require.Equal(t, int32(2), numSent.Load())
+	})
+
+	t.Run("requests in window are coalesced", func(t *testing.T) {
+		p, numSent := newCoalescer()
+		defer p.Close()
+
+		p.Request()
+		for i := 0; i < 50; i++ {
+			p.Request()
+		}
+		require.Equal(t, int32(1), numSent.Load())
+
+		require.Eventually(t, func() bool { return numSent.Load() == 2 }, 4*window, 5*time.Millisecond)
+		time.Sleep(2 * window)
+		require.Equal(t, int32(2), numSent.Load())
+	})
+
+	t.Run("deferred requests are not held indefinitely", func(t *testing.T) {
+		p, numSent := newCoalescer()
+		defer p.Close()
+
+		p.Request()
+		start := time.Now()
+		for numSent.Load() < 2 && time.Since(start) < 10*window {
+			p.Request()
+			time.Sleep(window / 5)
+		}
+		require.Equal(t, int32(2), numSent.Load())
+		require.Less(t, time.Since(start), time.Duration(pliCoalesceMaxWindows+1)*window)
+	})
+
+	t.Run("closed", func(t *testing.T) {
+		p, numSent := newCoalescer()
+		p.Request()
+		p.Request()
+		p.Close()
+
+		time.Sleep(2 * window)
+		p.Request()
+		require.Equal(t, int32(1), numSent.Load())
+	})
+}
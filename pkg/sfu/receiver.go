@@ -15,7 +15,9 @@
 package sfu
 
 import (
+	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
 	"strings"
 	"sync"
@@ -24,6 +26,7 @@ import (
 	"github.com/pion/rtcp"
 	"github.com/pion/webrtc/v3"
 	"go.uber.org/atomic"
+	"google.golang.org/protobuf/encoding/protojson"
 	"google.golang.org/protobuf/proto"
 
 	"github.com/livekit/mediatransportutil/pkg/bucket"
@@ -44,10 +47,59 @@ var (
 	ErrDuplicateLayer        = errors.New("duplicate layer")
 )
 
+const (
+	trackForwardStatsUpdateInterval = time.Second
+	trackForwardStatsWindowLength   = 10 * time.Second
+
+	rtcpChSize = 32
+
+	silenceCheckInterval = 100 * time.Millisecond
+)
+
 type AudioLevelHandle func(level uint8, duration uint32)
 
 type Bitrates [buffer.DefaultMaxLayerSpatial + 1][buffer.DefaultMaxLayerTemporal + 1]int64
 
+// ReceiverExtendedStats is a snapshot of receiver health for diagnostics
+type ReceiverExtendedStats struct {
+	TrackStats        *livekit.RTPStats
+	ConnectionScore   float32
+	ConnectionQuality livekit.ConnectionQuality
+	AvailableLayers   []int32
+	Bitrates          Bitrates
+	DebugInfo         map[string]interface{}
+}
+
+// String returns a JSON representation of the stats, suitable for logging
+func (r ReceiverExtendedStats) String() string {
+	var trackStats json.RawMessage
+	if r.TrackStats != nil {
+		if b, err := protojson.Marshal(r.TrackStats); err == nil {
+			trackStats = b
+		}
+	}
+
+	b, err := json.Marshal(struct {
+		TrackStats        json.RawMessage        `json:"trackStats,omitempty"`
+		ConnectionScore   float32                `json:"connectionScore"`
+		ConnectionQuality string                 `json:"connectionQuality"`
+		AvailableLayers   []int32                `json:"availableLayers"`
+		Bitrates          Bitrates               `json:"bitrates"`
+		DebugInfo         map[string]interface{} `json:"debugInfo"`
+	}{
+		TrackStats:        trackStats,
+		ConnectionScore:   r.ConnectionScore,
+		ConnectionQuality: r.ConnectionQuality.String(),
+		AvailableLayers:   r.AvailableLayers,
+		Bitrates:          r.Bitrates,
+		DebugInfo:         r.DebugInfo,
+	})
+	if err != nil {
+		return fmt.Sprintf("{\"error\": %q}", err.Error())
+	}
+	return string(b)
+}
+
 // TrackReceiver defines an interface receive media from remote peer
 type TrackReceiver interface {
 	TrackID() livekit.TrackID
@@ -83,13 +135,23 @@ type TrackReceiver interface {
 	GetTemporalLayerFpsForSpatial(layer int32) []float32
 
 	GetTrackStats() *livekit.RTPStats
+
+	// GetSSRCForLayer returns the SSRC of the stream carrying a spatial layer, 0 if not known
+	GetSSRCForLayer(layer int32) uint32
+
+	// IsSVC returns true if all spatial layers are carried by a single stream using a scalable video codec
+	IsSVC() bool
+
+	// GetRetransmissionTimeout returns the minimum interval between retransmissions of a packet
+	// to down tracks of this receiver, 0 if down tracks should use the default
+	GetRetransmissionTimeout() time.Duration
 }
 
 // WebRTCReceiver receives a media track
 type WebRTCReceiver struct {
 	logger logger.Logger
 
-	pliThrottleConfig config.PLIThrottleConfig
+	pliThrottleConfig atomic.Pointer[config.PLIThrottleConfig]
 	audioConfig       config.AudioConfig
 
 	trackID        livekit.TrackID
@@ -102,15 +164,23 @@ type WebRTCReceiver struct {
 	onCloseHandler func()
 	closeOnce      sync.Once
 	closed         atomic.Bool
+	createdAt      time.Time
+	lastPacketAt   atomic.Int64
 	useTrackers    bool
 	trackInfo      atomic.Pointer[livekit.TrackInfo]
 
 	onRTCP func([]rtcp.Packet)
+	// RTCP from publisher
+	rtcpCh   chan []rtcp.Packet
+	closedCh chan struct{}
 
 	bufferMu sync.RWMutex
 	buffers  [buffer.DefaultMaxLayerSpatial + 1]*buffer.Buffer
 	upTracks [buffer.DefaultMaxLayerSpatial + 1]*webrtc.TrackRemote
 	rtt      uint32
+	// time with at least one up track not paused
+	activeDuration time.Duration
+	activeSince    time.Time
 
 	lbThreshold int
 
@@ -118,6 +188,9 @@ type WebRTCReceiver struct {
 
 	downTrackSpreader *DownTrackSpreader
 
+	bandwidthOverridesLock sync.RWMutex
+	bandwidthOverrides     map[livekit.ParticipantID]int64
+
 	connectionStats *connectionquality.ConnectionStats
 
 	onStatsUpdate    func(w *WebRTCReceiver, stat *livekit.AnalyticsStat)
@@ -128,6 +201,56 @@ type WebRTCReceiver struct {
 	redPktWriter    func(pkt *buffer.ExtPacket, spatialLayer int32) int
 
 	forwardStats *ForwardStats
+	// latest track stats are published to this streamer on every stats update
+	rtpStatsStreamer *RTPStatsStreamer
+	// per layer forward stats of this track, not reported, available for debugging
+	isTrackForwardStatsEnabled bool
+	trackForwardStats          [buffer.DefaultMaxLayerSpatial + 1]*ForwardStats
+
+	networkEmulator *NetworkEmulator
+	playoutDelay    *livekit.PlayoutDelay
+	reorderDepth    int
+
+	maxPacketRate int
+	pliCoalescers [buffer.DefaultMaxLayerSpatial + 1]*pliCoalescer
+
+	packetInterceptor PacketInterceptor
+
+	roomBitrateLimiter *RoomBitrateLimiter
+	isBitrateCapped    atomic.Bool
+	numBitrateCaps     atomic.Uint64
+
+	temporalLayerCapLock      sync.Mutex
+	maxFrameRates             [buffer.DefaultMaxLayerSpatial + 1]float32
+	hasMaxFrameRate           atomic.Bool
+	frameRateTemporalLayerCap int32
+	temporalLayerCap          int32
+
+	packetAuthKey         []byte
+	packetAuthExtensionID uint8
+	packetsAuthFailed     atomic.Uint64
+
+	packetTrace atomic.Pointer[packetTrace]
+
+	// forwarding rate in bytes per second non-key frame packets are paced at, 0 disables pacing
+	pacingRate int64
+
+	// interval of periodic receiver reports to publisher, 0 sends reports only on packet arrival
+	rtcpInterval time.Duration
+
+	// per codec type minimum interval between retransmissions in down tracks, 0 uses the sequencer default
+	videoRetransmissionTimeout time.Duration
+	audioRetransmissionTimeout time.Duration
+
+	silenceLock      sync.Mutex
+	silenceThreshold time.Duration
+	onSilence        func()
+	silenceStart     time.Time
+	silenceFired     bool
+	silenceChecking  bool
+
+	// per layer reader of packets to forward, a buffer possibly wrapped by network emulator, reorder buffer and/or jitter buffer
+	readers [buffer.DefaultMaxLayerSpatial + 1]ExtPacketReader
 }
 
 // SVC-TODO: Have to use more conditions to differentiate between
@@ -153,7 +276,7 @@ type ReceiverOpts func(w *WebRTCReceiver) *WebRTCReceiver
 // WithPliThrottleConfig indicates minimum time(ms) between sending PLIs
 func WithPliThrottleConfig(pliThrottleConfig config.PLIThrottleConfig) ReceiverOpts {
 	return func(w *WebRTCReceiver) *WebRTCReceiver {
-		w.pliThrottleConfig = pliThrottleConfig
+		w.pliThrottleConfig.Store(&pliThrottleConfig)
 		return w
 	}
 }
@@ -193,6 +316,108 @@ func WithForwardStats(forwardStats *ForwardStats) ReceiverOpts {
 	}
 }
 
+// WithTrackForwardStats enables per layer forward stats of the track, available in debug info.
+func WithTrackForwardStats(enabled bool) ReceiverOpts {
+	return func(w *WebRTCReceiver) *WebRTCReceiver {
+		w.isTrackForwardStatsEnabled = enabled
+		return w
+	}
+}
+
+// WithRTPStatsStreamer publishes RTP stats of the track to the streamer on every stats update.
+func WithRTPStatsStreamer(rtpStatsStreamer *RTPStatsStreamer) ReceiverOpts {
+	return func(w *WebRTCReceiver) *WebRTCReceiver {
+		w.rtpStatsStreamer = rtpStatsStreamer
+		return w
+	}
+}
+
+// WithRoomBitrateLimiter caps video down tracks to the base temporal layer while the aggregate bitrate
+// of the room exceeds the cap of the limiter. The limiter is shared by all receivers in the room.
+func WithRoomBitrateLimiter(limiter *RoomBitrateLimiter) ReceiverOpts {
+	return func(w *WebRTCReceiver) *WebRTCReceiver {
+		w.roomBitrateLimiter = limiter
+		return w
+	}
+}
+
+// WithPlayoutDelay holds packets in a jitter buffer for the minimum playout delay before forwarding,
+// bounded by the maximum playout delay. Disabled playout delay has no effect.
+func WithPlayoutDelay(pd *livekit.PlayoutDelay) ReceiverOpts {
+	return func(w *WebRTCReceiver) *WebRTCReceiver {
+		if pd != nil && pd.Enabled && pd.Min > 0 {
+			w.playoutDelay = pd
+		}
+		return w
+	}
+}
+
+// WithReorderBufferDepth releases packets to down tracks in sequence number order, holding up to depth
+// sequence numbers while waiting for missing ones for at most one RTT.
+func WithReorderBufferDepth(depth int) ReceiverOpts {
+	return func(w *WebRTCReceiver) *WebRTCReceiver {
+		w.reorderDepth = depth
+		return w
+	}
+}
+
+// PacketInterceptor transforms packets before they are forwarded
+type PacketInterceptor interface {
+	// Intercept returns the packet to forward, which could be the given packet modified in place
+	// or a different packet, nil drops the packet.
+	// Called concurrently from the forwarding goroutine of each layer.
+	Intercept(pkt *buffer.ExtPacket, layer int32) *buffer.ExtPacket
+}
+
+// WithPacketInterceptor passes packets through the interceptor before forwarding them
+func WithPacketInterceptor(interceptor PacketInterceptor) ReceiverOpts {
+	return func(w *WebRTCReceiver) *WebRTCReceiver {
+		w.packetInterceptor = interceptor
+		return w
+	}
+}
+
+// WithPacketAuthentication drops packets which do not carry a valid HMAC-SHA256 tag, keyed with key,
+// in the RTP header extension with the given ID.
+func WithPacketAuthentication(key []byte, extensionID uint8) ReceiverOpts {
+	return func(w *WebRTCReceiver) *WebRTCReceiver {
+		if len(key) != 0 && extensionID != 0 {
+			w.packetAuthKey = append([]byte{}, key...)
+			w.packetAuthExtensionID = extensionID
+		}
+		return w
+	}
+}
+
+// WithRTCPInterval sends receiver reports of all layers to the publisher every interval,
+// in addition to the reports sent on packet arrival. Disabled if interval is not positive.
+func WithRTCPInterval(interval time.Duration) ReceiverOpts {
+	return func(w *WebRTCReceiver) *WebRTCReceiver {
+		w.rtcpInterval = interval
+		return w
+	}
+}
+
+// WithPacingRate smooths out bursts of forwarded packets by pacing them at the given rate in bytes per second.
+// Each down track paces its own packets, key frame packets bypass pacing so that layer switches are not delayed.
+func WithPacingRate(bytesPerSecond int64) ReceiverOpts {
+	return func(w *WebRTCReceiver) *WebRTCReceiver {
+		w.pacingRate = bytesPerSecond
+		return w
+	}
+}
+
+// WithRetransmissionTimeouts sets the minimum interval between retransmissions of a packet in down tracks
+// by codec type, e. g. video with infrequent key frames benefits from a longer retransmission window
+// while audio needs a shorter one. A non-positive timeout uses the default.
+func WithRetransmissionTimeouts(video time.Duration, audio time.Duration) ReceiverOpts {
+	return func(w *WebRTCReceiver) *WebRTCReceiver {
+		w.videoRetransmissionTimeout = video
+		w.audioRetransmissionTimeout = audio
+		return w
+	}
+}
+
 // NewWebRTCReceiver creates a new webrtc track receiver
 func NewWebRTCReceiver(
 	receiver *webrtc.RTPReceiver,
@@ -204,15 +429,23 @@ func NewWebRTCReceiver(
 	opts ...ReceiverOpts,
 ) *WebRTCReceiver {
 	w := &WebRTCReceiver{
-		logger:   logger,
-		receiver: receiver,
-		trackID:  livekit.TrackID(track.ID()),
-		streamID: track.StreamID(),
-		codec:    track.Codec(),
-		kind:     track.Kind(),
-		onRTCP:   onRTCP,
-		isSVC:    IsSvcCodec(track.Codec().MimeType),
-		isRED:    IsRedCodec(track.Codec().MimeType),
+		logger:    logger,
+		receiver:  receiver,
+		trackID:   livekit.TrackID(track.ID()),
+		streamID:  track.StreamID(),
+		codec:     track.Codec(),
+		kind:      track.Kind(),
+		onRTCP:    onRTCP,
+		isSVC:     IsSvcCodec(track.Codec().MimeType),
+		isRED:     IsRedCodec(track.Codec().MimeType),
+		rtcpCh:    make(chan []rtcp.Packet, rtcpChSize),
+		closedCh:  make(chan struct{}),
+		createdAt: time.Now(),
+
+		bandwidthOverrides: make(map[livekit.ParticipantID]int64),
+
+		frameRateTemporalLayerCap: buffer.DefaultMaxLayerTemporal,
+		temporalLayerCap:          buffer.DefaultMaxLayerTemporal,
 	}
 
 	for _, opt := range opts {
@@ -235,6 +468,9 @@ func NewWebRTCReceiver(
 		if w.onStatsUpdate != nil {
 			w.onStatsUpdate(w, stat)
 		}
+		if w.rtpStatsStreamer != nil {
+			w.rtpStatsStreamer.Update(w.rtpStatsStreamKey(), w.GetTrackStats())
+		}
 	})
 	w.connectionStats.Start(trackInfo)
 
@@ -250,6 +486,11 @@ func NewWebRTCReceiver(
 		}
 	}
 
+	go w.processRTCP()
+	if w.rtcpInterval > 0 {
+		go w.rtcpReportWorker()
+	}
+
 	return w
 }
 
@@ -307,6 +548,209 @@ func (w *WebRTCReceiver) SetRTT(rtt uint32) {
 	}
 }
 
+// SetMaxFrameRate limits frame rate forwarded from a layer. 0 fps removes the limit.
+//
+// Only non-reference frames can be dropped without breaking decoding, so the limit is applied by
+// capping the temporal layer of down tracks to the highest temporal layer whose frame rate is within the limit.
+// Down tracks drop frames of higher temporal layers and translate sequence numbers.
+// The cap applies to all spatial layers. Streams without temporal layers are not limited.
+func (w *WebRTCReceiver) SetMaxFrameRate(layer int32, fps float32) {
+	if w.kind != webrtc.RTPCodecTypeVideo || layer < 0 || int(layer) >= len(w.maxFrameRates) {
+		return
+	}
+
+	w.temporalLayerCapLock.Lock()
+	w.maxFrameRates[layer] = max(fps, 0)
+	hasMaxFrameRate := false
+	for _, maxFrameRate := range w.maxFrameRates {
+		if maxFrameRate > 0 {
+			hasMaxFrameRate = true
+		}
+	}
+	w.hasMaxFrameRate.Store(hasMaxFrameRate)
+	w.temporalLayerCapLock.Unlock()
+
+	w.updateFrameRateCap()
+}
+
+// updateFrameRateCap re-evaluates temporal layer cap for max frame rate,
+// frame rates of temporal layers are measured, so it is evaluated again on key frames
+func (w *WebRTCReceiver) updateFrameRateCap() {
+	w.temporalLayerCapLock.Lock()
+	maxFrameRates := w.maxFrameRates
+	w.temporalLayerCapLock.Unlock()
+
+	frameRateCap := int32(buffer.DefaultMaxLayerTemporal)
+	for layer, maxFrameRate := range maxFrameRates {
+		if maxFrameRate > 0 {
+			frameRateCap = min(frameRateCap, getTemporalLayerForMaxFps(w.GetTemporalLayerFpsForSpatial(int32(layer)), maxFrameRate))
+		}
+	}
+
+	w.temporalLayerCapLock.Lock()
+	w.frameRateTemporalLayerCap = frameRateCap
+	w.temporalLayerCapLock.Unlock()
+
+	w.updateTemporalLayerCap()
+}
+
+// getTemporalLayerForMaxFps returns highest temporal layer with frame rate not exceeding max fps,
+// no cap if frame rates are not known yet
+func getTemporalLayerForMaxFps(layerFps []float32, maxFps float32) int32 {
+	temporalLayer := int32(buffer.DefaultMaxLayerTemporal)
+	for i, fps := range layerFps {
+		if fps == 0 {
+			continue
+		}
+		if fps > maxFps {
+			return max(int32(i)-1, 0)
+		}
+		temporalLayer = int32(i)
+	}
+	return temporalLayer
+}
+
+// SetMaxPacketRate limits the packet rate of each up track (SSRC) by dropping packets over the limit
+// when they are written to the buffer, before they are NACK tracked or counted in stats.
+// 0 removes the limit.
+func (w *WebRTCReceiver) SetMaxPacketRate(pps int) {
+	w.bufferMu.Lock()
+	defer w.bufferMu.Unlock()
+
+	w.maxPacketRate = pps
+	for _, buff := range w.buffers {
+		if buff != nil {
+			buff.SetMaxPacketRate(pps)
+		}
+	}
+}
+
+// GetPacketsRateLimited returns number of packets dropped due to packet rate limit
+func (w *WebRTCReceiver) GetPacketsRateLimited() uint64 {
+	w.bufferMu.RLock()
+	defer w.bufferMu.RUnlock()
+
+	return w.getPacketsRateLimitedLocked()
+}
+
+func (w *WebRTCReceiver) getPacketsRateLimitedLocked() uint64 {
+	packetsRateLimited := uint64(0)
+	for _, buff := range w.buffers {
+		if buff != nil {
+			packetsRateLimited += buff.GetPacketsRateLimited()
+		}
+	}
+	return packetsRateLimited
+}
+
+// GetBitrateCapCount returns number of times down tracks were capped due to the room bitrate cap
+func (w *WebRTCReceiver) GetBitrateCapCount() uint64 {
+	return w.numBitrateCaps.Load()
+}
+
+func (w *WebRTCReceiver) getTemporalLayerCap() int32 {
+	w.temporalLayerCapLock.Lock()
+	defer w.temporalLayerCapLock.Unlock()
+
+	return w.temporalLayerCap
+}
+
+// updateBitrateCap caps temporal layers of down tracks while the room is over its bitrate budget,
+// down tracks drop whole non-reference frames above the cap and translate sequence numbers
+func (w *WebRTCReceiver) updateBitrateCap(isCapped bool) {
+	if w.kind != webrtc.RTPCodecTypeVideo || w.isBitrateCapped.Swap(isCapped) == isCapped {
+		return
+	}
+
+	if isCapped {
+		w.numBitrateCaps.Inc()
+	}
+	w.logger.Debugw("room bitrate cap changed", "capped", isCapped)
+	w.updateTemporalLayerCap()
+}
+
+func (w *WebRTCReceiver) updateTemporalLayerCap() {
+	w.temporalLayerCapLock.Lock()
+	defer w.temporalLayerCapLock.Unlock()
+
+	temporalLayerCap := w.frameRateTemporalLayerCap
+	if w.isBitrateCapped.Load() {
+		temporalLayerCap = 0
+	}
+	if temporalLayerCap == w.temporalLayerCap {
+		return
+	}
+
+	w.temporalLayerCap = temporalLayerCap
+	w.logger.Debugw("temporal layer cap changed", "temporalLayerCap", temporalLayerCap)
+	w.downTrackSpreader.Broadcast(func(dt TrackSender) {
+		dt.SetTemporalLayerCap(temporalLayerCap)
+	})
+}
+
+// IsActive returns true if the receiver is open and has received at least one packet
+func (w *WebRTCReceiver) IsActive() bool {
+	return !w.closed.Load() && w.lastPacketAt.Load() != 0
+}
+
+// LastPacketTime returns arrival time of the last packet accepted for forwarding, zero time if none
+func (w *WebRTCReceiver) LastPacketTime() time.Time {
+	if lastPacketAt := w.lastPacketAt.Load(); lastPacketAt != 0 {
+		return time.Unix(0, lastPacketAt)
+	}
+
+	return time.Time{}
+}
+
+// GetInactiveDuration returns time elapsed since the last packet, or since creation if no packet has been received
+func (w *WebRTCReceiver) GetInactiveDuration() time.Duration {
+	lastActivity := w.LastPacketTime()
+	if lastActivity.IsZero() {
+		lastActivity = w.createdAt
+	}
+
+	return time.Since(lastActivity)
+}
+
+// GetActiveDuration returns cumulative time during which up tracks were published and not paused
+func (w *WebRTCReceiver) GetActiveDuration() time.Duration {
+	w.bufferMu.RLock()
+	defer w.bufferMu.RUnlock()
+
+	activeDuration := w.activeDuration
+	if !w.activeSince.IsZero() {
+		activeDuration += time.Since(w.activeSince)
+	}
+	return activeDuration
+}
+
+func (w *WebRTCReceiver) startActiveLocked() {
+	if !w.activeSince.IsZero() || w.closed.Load() {
+		return
+	}
+
+	for _, buff := range w.buffers {
+		if buff != nil {
+			w.activeSince = time.Now()
+			return
+		}
+	}
+}
+
+func (w *WebRTCReceiver) stopActiveLocked() {
+	if !w.activeSince.IsZero() {
+		w.activeDuration += time.Since(w.activeSince)
+		w.activeSince = time.Time{}
+	}
+}
+
+func (w *WebRTCReceiver) getRTT() time.Duration {
+	w.bufferMu.RLock()
+	defer w.bufferMu.RUnlock()
+
+	return time.Duration(w.rtt) * time.Millisecond
+}
+
 func (w *WebRTCReceiver) StreamID() string {
 	return w.streamID
 }
@@ -359,20 +803,15 @@ func (w *WebRTCReceiver) AddUpTrack(track *webrtc.TrackRemote, buff *buffer.Buff
 		})
 	})
 
-	var duration time.Duration
-	switch layer {
-	case 2:
-		duration = w.pliThrottleConfig.HighQuality
-	case 1:
-		duration = w.pliThrottleConfig.MidQuality
-	case 0:
-		duration = w.pliThrottleConfig.LowQuality
-	default:
-		duration = w.pliThrottleConfig.MidQuality
-	}
-	if duration != 0 {
+	if duration := w.getPLIThrottle(layer); duration != 0 {
 		buff.SetPLIThrottle(duration.Nanoseconds())
 	}
+	pliCoalescer := newPLICoalescer(
+		func() { buff.SendPLI(false) },
+		func() time.Duration { return w.getPLICoalesceWindow(layer) },
+	)
+
+	isPaused := w.streamTrackerManager.IsPaused()
 
 	w.bufferMu.Lock()
 	if w.upTracks[layer] != nil {
@@ -381,11 +820,32 @@ func (w *WebRTCReceiver) AddUpTrack(track *webrtc.TrackRemote, buff *buffer.Buff
 	}
 	w.upTracks[layer] = track
 	w.buffers[layer] = buff
+	w.pliCoalescers[layer] = pliCoalescer
+	if w.maxPacketRate > 0 {
+		buff.SetMaxPacketRate(w.maxPacketRate)
+	}
+	if w.isTrackForwardStatsEnabled {
+		w.trackForwardStats[layer] = NewForwardStats(trackForwardStatsUpdateInterval, 0, trackForwardStatsWindowLength)
+	}
+	var reader ExtPacketReader = buff
+	if w.networkEmulator != nil {
+		reader = w.networkEmulator.Wrap(reader)
+	}
+	if w.reorderDepth > 0 {
+		reader = newReorderBuffer(reader, w.reorderDepth, w.getRTT, w.closedCh)
+	}
+	if w.playoutDelay != nil {
+		reader = newPlayoutDelayReader(reader, w.playoutDelay, w.closedCh)
+	}
+	w.readers[layer] = reader
 	rtt := w.rtt
+	if !isPaused {
+		w.startActiveLocked()
+	}
 	w.bufferMu.Unlock()
 
 	buff.SetRTT(rtt)
-	buff.SetPaused(w.streamTrackerManager.IsPaused())
+	buff.SetPaused(isPaused)
 
 	if w.Kind() == webrtc.RTPCodecTypeVideo && w.useTrackers {
 		w.streamTrackerManager.AddTracker(layer)
@@ -401,7 +861,7 @@ func (w *WebRTCReceiver) AddUpTrack(track *webrtc.TrackRemote, buff *buffer.Buff
 func (w *WebRTCReceiver) SetUpTrackPaused(paused bool) {
 	w.streamTrackerManager.SetPaused(paused)
 
-	w.bufferMu.RLock()
+	w.bufferMu.Lock()
 	for _, buff := range w.buffers {
 		if buff == nil {
 			continue
@@ -409,7 +869,12 @@ func (w *WebRTCReceiver) SetUpTrackPaused(paused bool) {
 
 		buff.SetPaused(paused)
 	}
-	w.bufferMu.RUnlock()
+	if paused {
+		w.stopActiveLocked()
+	} else {
+		w.startActiveLocked()
+	}
+	w.bufferMu.Unlock()
 
 	w.connectionStats.UpdateMute(paused)
 }
@@ -427,6 +892,19 @@ func (w *WebRTCReceiver) AddDownTrack(track TrackSender) error {
 	track.UpTrackMaxPublishedLayerChange(w.streamTrackerManager.GetMaxPublishedLayer())
 	track.UpTrackMaxTemporalLayerSeenChange(w.streamTrackerManager.GetMaxTemporalLayerSeen())
 
+	if w.kind == webrtc.RTPCodecTypeVideo {
+		track.SetTemporalLayerCap(w.getTemporalLayerCap())
+	}
+	if w.pacingRate > 0 {
+		track.SetPacingRate(w.pacingRate)
+	}
+
+	w.bandwidthOverridesLock.RLock()
+	if bps, ok := w.bandwidthOverrides[track.SubscriberID()]; ok {
+		track.SetBandwidthOverride(bps)
+	}
+	w.bandwidthOverridesLock.RUnlock()
+
 	w.downTrackSpreader.Store(track)
 	w.logger.Debugw("downtrack added", "subscriberID", track.SubscriberID())
 	return nil
@@ -517,8 +995,29 @@ func (w *WebRTCReceiver) OnBitrateReport(availableLayers []int32, bitrates Bitra
 	w.connectionStats.AddLayerTransition(w.streamTrackerManager.DistanceToDesired())
 }
 
+// GetLayeredBitrate returns the layered bitrates used for allocation, layers pending measurement are estimated
+// to avoid unnecessary layer switches due to unknown bitrates
 func (w *WebRTCReceiver) GetLayeredBitrate() ([]int32, Bitrates) {
-	return w.streamTrackerManager.GetLayeredBitrate()
+	return w.streamTrackerManager.GetEstimatedLayeredBitrate()
+}
+
+// GetActiveLayers returns the sorted spatial layers which have non-zero bitrate
+func (w *WebRTCReceiver) GetActiveLayers() []int32 {
+	_, bitrates := w.streamTrackerManager.GetLayeredBitrate()
+	return getActiveLayers(bitrates)
+}
+
+func getActiveLayers(bitrates Bitrates) []int32 {
+	activeLayers := make([]int32, 0, len(bitrates))
+	for spatial, temporalBitrates := range bitrates {
+		for _, bitrate := range temporalBitrates {
+			if bitrate != 0 {
+				activeLayers = append(activeLayers, int32(spatial))
+				break
+			}
+		}
+	}
+	return activeLayers
 }
 
 // OnCloseHandler method to be called on remote tracked removed
@@ -536,6 +1035,36 @@ func (w *WebRTCReceiver) DeleteDownTrack(subscriberID livekit.ParticipantID) {
 	w.logger.Debugw("downtrack deleted", "subscriberID", subscriberID)
 }
 
+// SetSubscriberBandwidthOverride grants a subscriber the given bandwidth for this track regardless of
+// its channel estimate, i. e. the subscriber gets the highest layer fitting in bps irrespective of
+// adaptive stream allocation. The override is retained across down track replacement.
+// A value <= 0 clears the override.
+func (w *WebRTCReceiver) SetSubscriberBandwidthOverride(subscriberID livekit.ParticipantID, bps int64) {
+	if bps < 0 {
+		bps = 0
+	}
+
+	w.bandwidthOverridesLock.Lock()
+	if bps == 0 {
+		delete(w.bandwidthOverrides, subscriberID)
+	} else {
+		w.bandwidthOverrides[subscriberID] = bps
+	}
+	w.bandwidthOverridesLock.Unlock()
+
+	if dt := w.downTrackSpreader.GetDownTrack(subscriberID); dt != nil {
+		dt.SetBandwidthOverride(bps)
+	}
+	w.logger.Debugw("subscriber bandwidth override", "subscriberID", subscriberID, "bps", bps)
+}
+
+func (w *WebRTCReceiver) GetSubscriberBandwidthOverride(subscriberID livekit.ParticipantID) int64 {
+	w.bandwidthOverridesLock.RLock()
+	defer w.bandwidthOverridesLock.RUnlock()
+
+	return w.bandwidthOverrides[subscriberID]
+}
+
 func (w *WebRTCReceiver) sendRTCP(packets []rtcp.Packet) {
 	if packets == nil || w.closed.Load() {
 		return
@@ -553,9 +1082,76 @@ func (w *WebRTCReceiver) SendPLI(layer int32, force bool) {
 		return
 	}
 
+	if !force {
+		// coalesce requests from subscribers switching layers around the same time
+		if pliCoalescer := w.getPLICoalescer(layer); pliCoalescer != nil {
+			pliCoalescer.Request()
+			return
+		}
+	}
+
 	buff.SendPLI(force)
 }
 
+func (w *WebRTCReceiver) getPLICoalescer(layer int32) *pliCoalescer {
+	w.bufferMu.RLock()
+	defer w.bufferMu.RUnlock()
+
+	if w.isSVC {
+		layer = 0
+	}
+	if layer < 0 || int(layer) >= len(w.pliCoalescers) {
+		return nil
+	}
+	return w.pliCoalescers[layer]
+}
+
+// UpdatePliThrottleConfig changes minimum time between PLIs sent to the publisher, applied to existing layers as well.
+// Layers with a zero duration keep their current throttle.
+func (w *WebRTCReceiver) UpdatePliThrottleConfig(pliThrottleConfig config.PLIThrottleConfig) {
+	w.pliThrottleConfig.Store(&pliThrottleConfig)
+
+	w.bufferMu.RLock()
+	defer w.bufferMu.RUnlock()
+
+	for layer, buff := range w.buffers {
+		if buff == nil {
+			continue
+		}
+
+		if duration := w.getPLIThrottle(int32(layer)); duration != 0 {
+			buff.SetPLIThrottle(duration.Nanoseconds())
+		}
+	}
+}
+
+func (w *WebRTCReceiver) getPLIThrottle(layer int32) time.Duration {
+	pliThrottleConfig := w.pliThrottleConfig.Load()
+	if pliThrottleConfig == nil {
+		return 0
+	}
+
+	switch layer {
+	case 2:
+		return pliThrottleConfig.HighQuality
+	case 1:
+		return pliThrottleConfig.MidQuality
+	case 0:
+		return pliThrottleConfig.LowQuality
+	default:
+		return pliThrottleConfig.MidQuality
+	}
+}
+
+// getPLICoalesceWindow returns the time over which PLI requests of a layer are coalesced, max(RTT, PLI throttle)
+func (w *WebRTCReceiver) getPLICoalesceWindow(layer int32) time.Duration {
+	w.bufferMu.RLock()
+	rtt := time.Duration(w.rtt) * time.Millisecond
+	w.bufferMu.RUnlock()
+
+	return max(rtt, w.getPLIThrottle(layer))
+}
+
 func (w *WebRTCReceiver) getBuffer(layer int32) *buffer.Buffer {
 	w.bufferMu.RLock()
 	defer w.bufferMu.RUnlock()
@@ -563,6 +1159,33 @@ func (w *WebRTCReceiver) getBuffer(layer int32) *buffer.Buffer {
 	return w.getBufferLocked(layer)
 }
 
+// GetConnectionQualityHistory returns up to n most recent connection quality changes from oldest to newest.
+func (w *WebRTCReceiver) GetConnectionQualityHistory(n int) []connectionquality.ConnectionQualityPoint {
+	return w.connectionStats.GetQualityHistory(n)
+}
+
+func (w *WebRTCReceiver) IsSVC() bool {
+	return w.isSVC
+}
+
+func (w *WebRTCReceiver) GetRetransmissionTimeout() time.Duration {
+	if w.kind == webrtc.RTPCodecTypeAudio {
+		return w.audioRetransmissionTimeout
+	}
+	return w.videoRetransmissionTimeout
+}
+
+// GetSSRCForLayer returns the SSRC of the stream carrying a spatial layer, 0 if not known.
+// All layers of SVC codecs are carried by a single stream.
+func (w *WebRTCReceiver) GetSSRCForLayer(layer int32) uint32 {
+	b := w.getBuffer(layer)
+	if b == nil {
+		return 0
+	}
+
+	return b.GetMediaSSRC()
+}
+
 func (w *WebRTCReceiver) getBufferLocked(layer int32) *buffer.Buffer {
 	// for svc codecs, use layer = 0 always.
 	// spatial layers are in-built and handled by single buffer
@@ -583,13 +1206,52 @@ func (w *WebRTCReceiver) ReadRTP(buf []byte, layer uint8, sn uint16) (int, error
 		return 0, ErrBufferNotFound
 	}
 
-	return b.GetPacket(buf, sn)
+	n, err := b.GetPacket(buf, sn)
+	if trace := w.getPacketTrace(b.GetMediaSSRC(), sn); trace != nil {
+		trace.Log("read for retransmission", "layer", layer, "size", n, "error", err)
+	}
+	return n, err
+}
+
+// EnablePacketTrace logs every step of the packet with the given SSRC and sequence number through the receiver,
+// i. e. arrival, drops, writes to down tracks and reads for retransmission on NACK. Tracing stops on timeout or
+// when another packet is traced.
+func (w *WebRTCReceiver) EnablePacketTrace(ssrc uint32, sn uint16) {
+	w.packetTrace.Store(newPacketTrace(ssrc, sn, w.logger))
+	w.logger.Infow("packet trace enabled", "ssrc", ssrc, "sn", sn, "timeout", packetTraceTimeout)
+}
+
+func (w *WebRTCReceiver) DisablePacketTrace() {
+	w.packetTrace.Store(nil)
+}
+
+func (w *WebRTCReceiver) getPacketTrace(ssrc uint32, sn uint16) *packetTrace {
+	trace := w.packetTrace.Load()
+	if trace == nil {
+		return nil
+	}
+
+	if trace.isExpired(time.Now()) {
+		if w.packetTrace.CompareAndSwap(trace, nil) {
+			trace.Log("trace expired")
+		}
+		return nil
+	}
+
+	if !trace.matches(ssrc, sn) {
+		return nil
+	}
+	return trace
 }
 
 func (w *WebRTCReceiver) GetTrackStats() *livekit.RTPStats {
 	w.bufferMu.RLock()
 	defer w.bufferMu.RUnlock()
 
+	return w.getTrackStatsLocked()
+}
+
+func (w *WebRTCReceiver) getTrackStatsLocked() *livekit.RTPStats {
 	stats := make([]*livekit.RTPStats, 0, len(w.buffers))
 	for _, buff := range w.buffers {
 		if buff == nil {
@@ -626,6 +1288,100 @@ func (w *WebRTCReceiver) GetAudioLevel() (float64, bool) {
 	return 0, false
 }
 
+// GetAudioLevelHistory returns up to n most recent per frame audio levels, oldest first
+func (w *WebRTCReceiver) GetAudioLevelHistory(n int) []audio.AudioLevelSample {
+	if w.Kind() == webrtc.RTPCodecTypeVideo {
+		return nil
+	}
+
+	w.bufferMu.RLock()
+	defer w.bufferMu.RUnlock()
+
+	for _, buff := range w.buffers {
+		if buff == nil {
+			continue
+		}
+
+		return buff.GetAudioLevelHistory(n)
+	}
+
+	return nil
+}
+
+// SetSilenceCallback sets a callback fired once audio has been silent for longer than threshold,
+// it is armed again when audio is not silent. nil callback removes it.
+func (w *WebRTCReceiver) SetSilenceCallback(threshold time.Duration, fn func()) {
+	if w.Kind() == webrtc.RTPCodecTypeVideo {
+		return
+	}
+
+	w.silenceLock.Lock()
+	w.silenceThreshold = threshold
+	w.onSilence = fn
+	w.silenceStart = time.Time{}
+	w.silenceFired = false
+	startChecking := fn != nil && !w.silenceChecking
+	if startChecking {
+		w.silenceChecking = true
+	}
+	w.silenceLock.Unlock()
+
+	if startChecking {
+		go w.silenceWorker()
+	}
+}
+
+func (w *WebRTCReceiver) silenceWorker() {
+	ticker := time.NewTicker(silenceCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-w.closedCh:
+			return
+
+		case <-ticker.C:
+			level, active := w.GetAudioLevel()
+			if !w.checkSilence(time.Now(), level == 0 && !active) {
+				return
+			}
+		}
+	}
+}
+
+// checkSilence updates silence state and fires callback if needed, returns false if there is no callback
+func (w *WebRTCReceiver) checkSilence(now time.Time, isSilent bool) bool {
+	w.silenceLock.Lock()
+	if w.onSilence == nil {
+		w.silenceChecking = false
+		w.silenceLock.Unlock()
+		return false
+	}
+
+	if !isSilent {
+		w.silenceStart = time.Time{}
+		w.silenceFired = false
+		w.silenceLock.Unlock()
+		return true
+	}
+
+	if w.silenceStart.IsZero() {
+		w.silenceStart = now
+	}
+
+	var onSilence func()
+	if !w.silenceFired && now.Sub(w.silenceStart) > w.silenceThreshold {
+		w.silenceFired = true
+		onSilence = w.onSilence
+	}
+	w.silenceLock.Unlock()
+
+	if onSilence != nil {
+		onSilence()
+	}
+	return true
+}
+
 func (w *WebRTCReceiver) GetDeltaStats() map[uint32]*buffer.StreamStatsWithLayers {
 	w.bufferMu.RLock()
 	defer w.bufferMu.RUnlock()
@@ -676,17 +1432,18 @@ func (w *WebRTCReceiver) forwardRTP(layer int32) {
 	pktBuf := make([]byte, bucket.MaxPktSize)
 	tracker := w.streamTrackerManager.GetTracker(layer)
 
+	var authenticator *packetAuthenticator
+	if len(w.packetAuthKey) != 0 {
+		authenticator = newPacketAuthenticator(w.packetAuthKey, w.packetAuthExtensionID)
+	}
+
+	var vp9SS *vp9ScalabilityStructure
+	if w.isSVC && strings.EqualFold(w.codec.MimeType, webrtc.MimeTypeVP9) {
+		vp9SS = newVP9ScalabilityStructure()
+	}
+
 	defer func() {
-		w.closeOnce.Do(func() {
-			w.closed.Store(true)
-			w.closeTracks()
-			if pr := w.primaryReceiver.Load(); pr != nil {
-				pr.Close()
-			}
-			if pr := w.redReceiver.Load(); pr != nil {
-				pr.Close()
-			}
-		})
+		w.close()
 
 		w.streamTrackerManager.RemoveTracker(layer)
 		if w.isSVC {
@@ -696,14 +1453,40 @@ func (w *WebRTCReceiver) forwardRTP(layer int32) {
 
 	for {
 		w.bufferMu.RLock()
-		buf := w.buffers[layer]
+		reader := w.readers[layer]
 		redPktWriter := w.redPktWriter
+		trackForwardStats := w.trackForwardStats[layer]
 		w.bufferMu.RUnlock()
-		pkt, err := buf.ReadExtended(pktBuf)
+		pkt, err := reader.ReadExtended(pktBuf)
 		if err == io.EOF {
 			return
 		}
 
+		trace := w.getPacketTrace(pkt.Packet.SSRC, pkt.Packet.SequenceNumber)
+		if trace != nil {
+			trace.Log("read from buffer", "layer", layer, "esn", pkt.ExtSequenceNumber, "arrival", pkt.Arrival, "keyFrame", pkt.KeyFrame)
+		}
+
+		if authenticator != nil && !authenticator.Verify(pkt.Packet) {
+			if trace != nil {
+				trace.Log("dropped on authentication failure")
+			}
+			if w.packetsAuthFailed.Inc()%100 == 1 {
+				w.logger.Warnw(
+					"packet authentication failed", nil,
+					"layer", layer,
+					"sn", pkt.Packet.SequenceNumber,
+					"count", w.packetsAuthFailed.Load(),
+				)
+			}
+			continue
+		}
+		w.lastPacketAt.Store(pkt.Arrival.UnixNano())
+
+		if vp9SS != nil {
+			vp9SS.Update(pkt)
+		}
+
 		spatialTracker := tracker
 		spatialLayer := layer
 		if pkt.Spatial >= 0 {
@@ -731,16 +1514,46 @@ func (w *WebRTCReceiver) forwardRTP(layer int32) {
 			)
 		}
 
-		writeCount := w.downTrackSpreader.Broadcast(func(dt TrackSender) {
-			_ = dt.WriteRTP(pkt, spatialLayer)
-		})
+		if w.packetInterceptor != nil {
+			if pkt = w.packetInterceptor.Intercept(pkt, spatialLayer); pkt == nil {
+				if trace != nil {
+					trace.Log("dropped by packet interceptor")
+				}
+				continue
+			}
+		}
+
+		if pkt.KeyFrame && w.hasMaxFrameRate.Load() {
+			w.updateFrameRateCap()
+		}
+		if w.roomBitrateLimiter != nil {
+			w.updateBitrateCap(w.roomBitrateLimiter.Add(len(pkt.RawPacket), pkt.Arrival))
+		}
+
+		var writeCount int
+		if trace != nil {
+			writeCount = w.downTrackSpreader.Broadcast(func(dt TrackSender) {
+				err := dt.WriteRTP(pkt, spatialLayer)
+				trace.Log("written to down track", "spatialLayer", spatialLayer, "subscriberID", dt.SubscriberID(), "error", err)
+			})
+		} else {
+			writeCount = w.downTrackSpreader.Broadcast(func(dt TrackSender) {
+				_ = dt.WriteRTP(pkt, spatialLayer)
+			})
+		}
 
 		if redPktWriter != nil {
 			writeCount += redPktWriter(pkt, spatialLayer)
 		}
 
-		if writeCount > 0 && w.forwardStats != nil {
-			w.forwardStats.Update(pkt.Arrival, time.Now())
+		if writeCount > 0 && (w.forwardStats != nil || trackForwardStats != nil) {
+			now := time.Now()
+			if w.forwardStats != nil {
+				w.forwardStats.Update(pkt.Arrival, now, len(pkt.RawPacket))
+			}
+			if trackForwardStats != nil {
+				trackForwardStats.Update(pkt.Arrival, now, len(pkt.RawPacket))
+			}
 		}
 
 		if spatialTracker != nil {
@@ -757,9 +1570,125 @@ func (w *WebRTCReceiver) forwardRTP(layer int32) {
 }
 
 // closeTracks close all tracks from Receiver
+func (w *WebRTCReceiver) close() {
+	w.closeOnce.Do(func() {
+		w.closed.Store(true)
+		w.bufferMu.Lock()
+		w.stopActiveLocked()
+		pliCoalescers := w.pliCoalescers
+		w.bufferMu.Unlock()
+		for _, pliCoalescer := range pliCoalescers {
+			if pliCoalescer != nil {
+				pliCoalescer.Close()
+			}
+		}
+		close(w.closedCh)
+		w.closeTracks()
+		if pr := w.primaryReceiver.Load(); pr != nil {
+			pr.Close()
+		}
+		if pr := w.redReceiver.Load(); pr != nil {
+			pr.Close()
+		}
+	})
+}
+
+// HandleRTCP queues RTCP packets received from the publisher for processing
+func (w *WebRTCReceiver) HandleRTCP(pkts []rtcp.Packet) {
+	if w.closed.Load() {
+		return
+	}
+
+	select {
+	case w.rtcpCh <- pkts:
+	default:
+		w.logger.Warnw("rtcp channel full, dropping", nil, "numPackets", len(pkts))
+	}
+}
+
+func (w *WebRTCReceiver) processRTCP() {
+	for {
+		select {
+		case <-w.closedCh:
+			return
+
+		case pkts := <-w.rtcpCh:
+			for _, pkt := range pkts {
+				bye, ok := pkt.(*rtcp.Goodbye)
+				if !ok || !w.isUpTrackSource(bye.Sources) {
+					continue
+				}
+
+				// publisher is done, close instead of waiting for packets which will not arrive
+				w.logger.Infow("received RTCP BYE, closing", "sources", bye.Sources, "reason", bye.Reason)
+				w.close()
+				return
+			}
+		}
+	}
+}
+
+// sleep waits for the given duration, returns false if the receiver is closed in the meantime
+func (w *WebRTCReceiver) rtcpReportWorker() {
+	ticker := time.NewTicker(w.rtcpInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-w.closedCh:
+			return
+
+		case <-ticker.C:
+			w.sendRTCP(w.snapshotRtcpReceptionReports())
+		}
+	}
+}
+
+func (w *WebRTCReceiver) snapshotRtcpReceptionReports() []rtcp.Packet {
+	w.bufferMu.RLock()
+	buffers := w.buffers
+	w.bufferMu.RUnlock()
+
+	var pkts []rtcp.Packet
+	for _, buff := range buffers {
+		if buff != nil {
+			pkts = append(pkts, buff.SnapshotRtcpReceptionReport()...)
+		}
+	}
+	return pkts
+}
+
+func (w *WebRTCReceiver) isUpTrackSource(sources []uint32) bool {
+	w.bufferMu.RLock()
+	defer w.bufferMu.RUnlock()
+
+	for _, upTrack := range w.upTracks {
+		if upTrack == nil {
+			continue
+		}
+
+		for _, ssrc := range sources {
+			if ssrc == uint32(upTrack.SSRC()) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (w *WebRTCReceiver) rtpStatsStreamKey() RTPStatsStreamKey {
+	return RTPStatsStreamKey{
+		TrackID:  w.trackID,
+		MimeType: w.codec.MimeType,
+	}
+}
+
 func (w *WebRTCReceiver) closeTracks() {
 	w.connectionStats.Close()
 	w.streamTrackerManager.Close()
+	if w.rtpStatsStreamer != nil {
+		w.rtpStatsStreamer.Remove(w.rtpStatsStreamKey())
+	}
 
 	closeTrackSenders(w.downTrackSpreader.ResetAndGetDownTracks())
 
@@ -769,33 +1698,142 @@ func (w *WebRTCReceiver) closeTracks() {
 }
 
 func (w *WebRTCReceiver) DebugInfo() map[string]interface{} {
-	isSimulcast := !w.isSVC
-	if ti := w.trackInfo.Load(); ti != nil {
-		isSimulcast = isSimulcast && len(ti.Layers) > 1
-	}
-	info := map[string]interface{}{
-		"SVC":       w.isSVC,
-		"Simulcast": isSimulcast,
-	}
-
 	w.bufferMu.RLock()
+	upTrackInfo, packetsRateLimited := w.getUpTrackDebugInfoLocked()
+	w.bufferMu.RUnlock()
+
+	return w.debugInfo(upTrackInfo, packetsRateLimited)
+}
+
+func (w *WebRTCReceiver) getUpTrackDebugInfoLocked() ([]map[string]interface{}, uint64) {
 	upTrackInfo := make([]map[string]interface{}, 0, len(w.upTracks))
 	for layer, ut := range w.upTracks {
 		if ut != nil {
-			upTrackInfo = append(upTrackInfo, map[string]interface{}{
+			utInfo := map[string]interface{}{
 				"Layer": layer,
 				"SSRC":  ut.SSRC(),
 				"Msid":  ut.Msid(),
 				"RID":   ut.RID(),
-			})
+			}
+			if fs := w.trackForwardStats[layer]; fs != nil {
+				latency, jitter := fs.GetStats()
+				utInfo["ForwardLatency"] = latency.String()
+				utInfo["ForwardJitter"] = jitter.String()
+				utInfo["ForwardLatencyP95"] = fs.GetPercentileLatency(95).String()
+			}
+			upTrackInfo = append(upTrackInfo, utInfo)
 		}
 	}
-	w.bufferMu.RUnlock()
-	info["UpTracks"] = upTrackInfo
+
+	return upTrackInfo, w.getPacketsRateLimitedLocked()
+}
+
+// debugInfo should be called without holding bufferMu as it queries components which call back into the receiver
+func (w *WebRTCReceiver) debugInfo(upTrackInfo []map[string]interface{}, packetsRateLimited uint64) map[string]interface{} {
+	isSimulcast := !w.isSVC
+	if ti := w.trackInfo.Load(); ti != nil {
+		isSimulcast = isSimulcast && len(ti.Layers) > 1
+	}
+	info := map[string]interface{}{
+		"SVC":                  w.isSVC,
+		"Simulcast":            isSimulcast,
+		"ScalabilityStructure": w.GetSVCScalabilityStructure(),
+		"UpTracks":             upTrackInfo,
+	}
+	if w.streamTrackerManager != nil {
+		info["ActiveLayers"] = w.GetActiveLayers()
+	}
+	if len(w.packetAuthKey) != 0 {
+		info["PacketsAuthFailed"] = w.packetsAuthFailed.Load()
+	}
+	if packetsRateLimited != 0 {
+		info["PacketsRateLimited"] = packetsRateLimited
+	}
+	if numBitrateCaps := w.numBitrateCaps.Load(); numBitrateCaps != 0 {
+		info["BitrateCaps"] = numBitrateCaps
+		info["IsBitrateCapped"] = w.isBitrateCapped.Load()
+	}
+	if pr := w.primaryReceiver.Load(); pr != nil {
+		info["PacketsRecoveredViaFEC"] = pr.GetPacketsRecoveredViaFEC()
+	}
 
 	return info
 }
 
+// GetSVCScalabilityStructure returns the scalability mode, e.g. "L3T3_KEY", of an SVC stream as described
+// by the latest dependency structure, "N/A" for simulcast, non-SVC codecs or if no structure has been seen.
+func (w *WebRTCReceiver) GetSVCScalabilityStructure() string {
+	if !w.isSVC || w.streamTrackerManager == nil {
+		return "N/A"
+	}
+
+	return scalabilityModeFromStructure(w.streamTrackerManager.GetDependencyStructure())
+}
+
+// scalabilityModeFromStructure derives the scalability mode from the layers and references of
+// the frame templates in a dependency structure. Spatial layers of a temporal unit are sent back to back,
+// so a template referencing the previous frame of an upper spatial layer uses inter-layer prediction.
+// Inter-layer prediction in all upper spatial layer templates is full SVC (L), in only some of them,
+// i. e. the key frame templates, is K-SVC (L.._KEY) and in none of them is simulcast (S).
+func scalabilityModeFromStructure(structure *dd.FrameDependencyStructure) string {
+	if structure == nil || len(structure.Templates) == 0 {
+		return "N/A"
+	}
+
+	numSpatial, numTemporal := 0, 0
+	numUpper, numInterLayer := 0, 0
+	for _, t := range structure.Templates {
+		numSpatial = max(numSpatial, t.SpatialId+1)
+		numTemporal = max(numTemporal, t.TemporalId+1)
+
+		if t.SpatialId == 0 {
+			continue
+		}
+		numUpper++
+		for _, diff := range t.FrameDiffs {
+			if diff == 1 {
+				numInterLayer++
+				break
+			}
+		}
+	}
+
+	switch {
+	case numSpatial > 1 && numInterLayer == 0:
+		return fmt.Sprintf("S%dT%d", numSpatial, numTemporal)
+	case numSpatial > 1 && numInterLayer < numUpper:
+		return fmt.Sprintf("L%dT%d_KEY", numSpatial, numTemporal)
+	default:
+		return fmt.Sprintf("L%dT%d", numSpatial, numTemporal)
+	}
+}
+
+// GetPacketsAuthFailed returns number of packets dropped due to failed packet authentication
+func (w *WebRTCReceiver) GetPacketsAuthFailed() uint64 {
+	return w.packetsAuthFailed.Load()
+}
+
+// GetExtendedStats returns track stats, connection quality, layer bitrates and debug info of the receiver in one shot.
+func (w *WebRTCReceiver) GetExtendedStats() ReceiverExtendedStats {
+	// snapshot receiver state under the lock, connection stats and stream tracker manager
+	// call back into the receiver and are queried after releasing it
+	w.bufferMu.RLock()
+	trackStats := w.getTrackStatsLocked()
+	upTrackInfo, packetsRateLimited := w.getUpTrackDebugInfoLocked()
+	w.bufferMu.RUnlock()
+
+	score, quality := w.connectionStats.GetScoreAndQuality()
+	availableLayers, bitrates := w.streamTrackerManager.GetLayeredBitrate()
+	return ReceiverExtendedStats{
+		TrackStats:        trackStats,
+		ConnectionScore:   score,
+		ConnectionQuality: quality,
+		AvailableLayers:   availableLayers,
+		Bitrates:          bitrates,
+		DebugInfo:         w.debugInfo(upTrackInfo, packetsRateLimited),
+	}
+}
+
 func (w *WebRTCReceiver) GetPrimaryReceiverForRed() TrackReceiver {
 	if !w.isRED || w.closed.Load() {
 		return w
@@ -15,16 +15,30 @@
 package sfu
 
 import (
+	"encoding/json"
 	"fmt"
 	"hash/fnv"
 	"math/rand"
 	"runtime"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/gammazero/workerpool"
+	"github.com/pion/rtcp"
+	"github.com/pion/rtp"
+	"github.com/pion/webrtc/v3"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/atomic"
+
+	"github.com/livekit/protocol/livekit"
+	"github.com/livekit/protocol/logger"
+
+	"github.com/livekit/livekit-server/pkg/config"
+	"github.com/livekit/livekit-server/pkg/sfu/buffer"
+	"github.com/livekit/livekit-server/pkg/sfu/connectionquality"
+	dd "github.com/livekit/livekit-server/pkg/sfu/rtpextension/dependencydescriptor"
 )
 
 func TestWebRTCReceiver_OnCloseHandler(t *testing.T) {
@@ -52,6 +66,528 @@ func TestWebRTCReceiver_OnCloseHandler(t *testing.T) {
 	}
 }
 
+func TestWebRTCReceiver_SilenceCallback(t *testing.T) {
+	w := &WebRTCReceiver{
+		kind:     webrtc.RTPCodecTypeAudio,
+		closedCh: make(chan struct{}),
+	}
+	defer close(w.closedCh)
+
+	numFired := 0
+	w.SetSilenceCallback(time.Second, func() { numFired++ })
+
+	now := time.Now()
+	require.True(t, w.checkSilence(now, true))
+	require.True(t, w.checkSilence(now.Add(500*time.Millisecond), true))
+	require.Equal(t, 0, numFired)
+
+	require.True(t, w.checkSilence(now.Add(1500*time.Millisecond), true))
+	require.Equal(t, 1, numFired)
+
+	// fires once per silence period
+	require.True(t, w.checkSilence(now.Add(3*time.Second), true))
+	require.Equal(t, 1, numFired)
+
+	// re-armed by audio
+	require.True(t, w.checkSilence(now.Add(4*time.Second), false))
+	require.True(t, w.checkSilence(now.Add(5*time.Second), true))
+	require.True(t, w.checkSilence(now.Add(6500*time.Millisecond), true))
+	require.Equal(t, 2, numFired)
+
+	w.SetSilenceCallback(0, nil)
+	require.False(t, w.checkSilence(now.Add(10*time.Second), true))
+}
+
+func TestWebRTCReceiver_ActiveDuration(t *testing.T) {
+	w := &WebRTCReceiver{}
+
+	// not active without up tracks
+	w.startActiveLocked()
+	require.Zero(t, w.GetActiveDuration())
+
+	w.buffers[0] = &buffer.Buffer{}
+	w.startActiveLocked()
+	time.Sleep(20 * time.Millisecond)
+	w.stopActiveLocked()
+	activeDuration := w.GetActiveDuration()
+	require.GreaterOrEqual(t, activeDuration, 20*time.Millisecond)
+
+	// does not accumulate while paused
+	time.Sleep(20 * time.Millisecond)
+	require.Equal(t, activeDuration, w.GetActiveDuration())
+
+	// includes ongoing interval
+	w.startActiveLocked()
+	time.Sleep(20 * time.Millisecond)
+	require.GreaterOrEqual(t, w.GetActiveDuration(), activeDuration+20*time.Millisecond)
+}
+
+func TestWebRTCReceiver_UpdatePliThrottleConfig(t *testing.T) {
+	w := WithPliThrottleConfig(config.PLIThrottleConfig{
+		LowQuality:  500 * time.Millisecond,
+		MidQuality:  time.Second,
+		HighQuality: time.Second,
+	})(&WebRTCReceiver{})
+	w.buffers[0] = &buffer.Buffer{}
+	require.Equal(t, 500*time.Millisecond, w.getPLIThrottle(0))
+	require.Equal(t, time.Second, w.getPLIThrottle(2))
+
+	w.UpdatePliThrottleConfig(config.PLIThrottleConfig{
+		LowQuality:  100 * time.Millisecond,
+		MidQuality:  200 * time.Millisecond,
+		HighQuality: 300 * time.Millisecond,
+	})
+	require.Equal(t, 100*time.Millisecond, w.getPLIThrottle(0))
+	require.Equal(t, 200*time.Millisecond, w.getPLIThrottle(1))
+	require.Equal(t, 300*time.Millisecond, w.getPLIThrottle(2))
+	require.Equal(t, 300*time.Millisecond, w.getPLICoalesceWindow(2))
+}
+
+func TestReceiverExtendedStats_String(t *testing.T) {
+	stats := ReceiverExtendedStats{
+		TrackStats:        &livekit.RTPStats{Packets: 100},
+		ConnectionScore:   4.5,
+		ConnectionQuality: livekit.ConnectionQuality_EXCELLENT,
+		AvailableLayers:   []int32{0, 1},
+		DebugInfo:         map[string]interface{}{"SVC": false},
+	}
+	stats.Bitrates[1][0] = 500_000
+
+	var decoded map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(stats.String()), &decoded))
+	require.Equal(t, map[string]interface{}{"packets": float64(100)}, decoded["trackStats"])
+	require.Equal(t, 4.5, decoded["connectionScore"])
+	require.Equal(t, "EXCELLENT", decoded["connectionQuality"])
+	require.Equal(t, []interface{}{float64(0), float64(1)}, decoded["availableLayers"])
+	require.Equal(t, float64(500_000), decoded["bitrates"].([]interface{})[1].([]interface{})[0])
+	require.Equal(t, map[string]interface{}{"SVC": false}, decoded["debugInfo"])
+}
+
+func TestGetActiveLayers(t *testing.T) {
+	var bitrates Bitrates
+	require.Empty(t, getActiveLayers(bitrates))
+
+	bitrates[0][0] = 100_000
+	bitrates[2][1] = 1_000_000
+	require.Equal(t, []int32{0, 2}, getActiveLayers(bitrates))
+}
+
+type recordingTrackSender struct {
+	TrackSender
+
+	lock              sync.Mutex
+	packets           []*buffer.ExtPacket
+	bandwidthOverride int64
+	temporalLayerCaps []int32
+	pacingRate        int64
+}
+
+func (r *recordingTrackSender) ID() string                              { return "recording" }
+func (r *recordingTrackSender) SubscriberID() livekit.ParticipantID     { return "subscriber" }
+func (r *recordingTrackSender) Close()                                  {}
+func (r *recordingTrackSender) IsClosed() bool                          { return false }
+func (r *recordingTrackSender) TrackInfoAvailable()                     {}
+func (r *recordingTrackSender) UpTrackMaxPublishedLayerChange(int32)    {}
+func (r *recordingTrackSender) UpTrackMaxTemporalLayerSeenChange(int32) {}
+
+func (r *recordingTrackSender) SetTemporalLayerCap(temporalLayer int32) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.temporalLayerCaps = append(r.temporalLayerCaps, temporalLayer)
+}
+
+func (r *recordingTrackSender) SetPacingRate(bytesPerSecond int64) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.pacingRate = bytesPerSecond
+}
+
+func (r *recordingTrackSender) getPacingRate() int64 {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	return r.pacingRate
+}
+
+func (r *recordingTrackSender) getTemporalLayerCaps() []int32 {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	return append([]int32{}, r.temporalLayerCaps...)
+}
+
+func (r *recordingTrackSender) SetBandwidthOverride(bps int64) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.bandwidthOverride = bps
+}
+
+func (r *recordingTrackSender) getBandwidthOverride() int64 {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	return r.bandwidthOverride
+}
+
+func (r *recordingTrackSender) WriteRTP(p *buffer.ExtPacket, _ int32) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.packets = append(r.packets, p)
+	return nil
+}
+
+func (r *recordingTrackSender) getSequenceNumbers() []uint16 {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	sns := make([]uint16, 0, len(r.packets))
+	for _, p := range r.packets {
+		sns = append(sns, p.Packet.SequenceNumber)
+	}
+	return sns
+}
+
+// newForwardingTestReceiver returns a receiver forwarding packets of layer 0 from the reader to a recording track sender
+func newForwardingTestReceiver(reader ExtPacketReader, opts ...ReceiverOpts) (*WebRTCReceiver, *recordingTrackSender) {
+	w := &WebRTCReceiver{
+		logger:   logger.GetLogger(),
+		codec:    webrtc.RTPCodecParameters{RTPCodecCapability: webrtc.RTPCodecCapability{MimeType: webrtc.MimeTypeVP8, ClockRate: 90000}},
+		kind:     webrtc.RTPCodecTypeVideo,
+		closedCh: make(chan struct{}),
+
+		bandwidthOverrides: make(map[livekit.ParticipantID]int64),
+
+		frameRateTemporalLayerCap: buffer.DefaultMaxLayerTemporal,
+		temporalLayerCap:          buffer.DefaultMaxLayerTemporal,
+	}
+	for _, opt := range opts {
+		w = opt(w)
+	}
+	w.downTrackSpreader = NewDownTrackSpreader(DownTrackSpreaderParams{Logger: w.logger})
+	w.connectionStats = connectionquality.NewConnectionStats(connectionquality.ConnectionStatsParams{
+		MimeType:         w.codec.MimeType,
+		ReceiverProvider: w,
+		Logger:           w.logger,
+	})
+	w.streamTrackerManager = NewStreamTrackerManager(w.logger, &livekit.TrackInfo{}, false, 90000, config.StreamTrackersConfig{})
+	w.readers[0] = reader
+
+	sender := &recordingTrackSender{}
+	w.downTrackSpreader.Store(sender)
+	return w, sender
+}
+
+func rtpSliceReader(sns ...uint16) *sliceReader {
+	s := &sliceReader{}
+	for _, sn := range sns {
+		s.packets = append(s.packets, &buffer.ExtPacket{
+			VideoLayer:        buffer.VideoLayer{Spatial: buffer.InvalidLayerSpatial},
+			Arrival:           time.Now(),
+			ExtSequenceNumber: uint64(sn),
+			ExtTimestamp:      uint64(sn) * 3000,
+			Packet: &rtp.Packet{
+				Header: rtp.Header{
+					SequenceNumber: sn,
+					Timestamp:      uint32(sn) * 3000,
+					Marker:         true,
+				},
+				Payload: []byte{0x01},
+			},
+		})
+	}
+	return s
+}
+
+type dropOddInterceptor struct{}
+
+func (d *dropOddInterceptor) Intercept(pkt *buffer.ExtPacket, _ int32) *buffer.ExtPacket {
+	if pkt.Packet.SequenceNumber%2 != 0 {
+		return nil
+	}
+	return pkt
+}
+
+func TestWebRTCReceiver_PacketInterceptor(t *testing.T) {
+	w, sender := newForwardingTestReceiver(rtpSliceReader(1, 2, 3, 4, 5, 6), WithPacketInterceptor(&dropOddInterceptor{}))
+	w.forwardRTP(0)
+
+	require.Equal(t, []uint16{2, 4, 6}, sender.getSequenceNumbers())
+}
+
+func TestWebRTCReceiver_RoomBitrateLimiter(t *testing.T) {
+	reader := rtpSliceReader(1, 2, 3, 4, 5)
+	for i, pkt := range reader.packets {
+		pkt.RawPacket = make([]byte, 500)
+		pkt.Arrival = time.Unix(0, 0).Add(time.Duration(i) * 400 * time.Millisecond)
+	}
+
+	// budget of 1000 bytes per window, over budget from third packet, under budget after the next window
+	w, sender := newForwardingTestReceiver(reader, WithRoomBitrateLimiter(NewRoomBitrateLimiter(8000)))
+	w.forwardRTP(0)
+
+	// packets are never dropped, temporal layers of down tracks are capped instead
+	require.Equal(t, []uint16{1, 2, 3, 4, 5}, sender.getSequenceNumbers())
+	require.Equal(t, []int32{0}, sender.getTemporalLayerCaps())
+	require.EqualValues(t, 1, w.GetBitrateCapCount())
+
+	// new down tracks start capped and are uncapped once under budget
+	w, _ = newForwardingTestReceiver(rtpSliceReader(), WithRoomBitrateLimiter(NewRoomBitrateLimiter(8000)))
+	w.updateBitrateCap(true)
+	dt := &recordingTrackSender{}
+	require.NoError(t, w.AddDownTrack(dt))
+	require.Equal(t, []int32{0}, dt.getTemporalLayerCaps())
+
+	w.updateBitrateCap(false)
+	require.Equal(t, []int32{0, buffer.DefaultMaxLayerTemporal}, dt.getTemporalLayerCaps())
+}
+
+func TestWebRTCReceiver_MaxFrameRate(t *testing.T) {
+	t.Run("temporal layer for max fps", func(t *testing.T) {
+		layerFps := []float32{7.5, 15, 30}
+		require.Equal(t, int32(2), getTemporalLayerForMaxFps(layerFps, 30))
+		require.Equal(t, int32(1), getTemporalLayerForMaxFps(layerFps, 20))
+		require.Equal(t, int32(0), getTemporalLayerForMaxFps(layerFps, 10))
+		// base layer is never dropped
+		require.Equal(t, int32(0), getTemporalLayerForMaxFps(layerFps, 5))
+		// not measured yet
+		require.Equal(t, int32(buffer.DefaultMaxLayerTemporal), getTemporalLayerForMaxFps(nil, 5))
+	})
+
+	t.Run("combined with bitrate cap", func(t *testing.T) {
+		w, sender := newForwardingTestReceiver(rtpSliceReader())
+
+		w.temporalLayerCapLock.Lock()
+		w.frameRateTemporalLayerCap = 1
+		w.temporalLayerCapLock.Unlock()
+		w.updateTemporalLayerCap()
+		require.Equal(t, []int32{1}, sender.getTemporalLayerCaps())
+
+		w.updateBitrateCap(true)
+		require.Equal(t, []int32{1, 0}, sender.getTemporalLayerCaps())
+
+		// back to frame rate cap
+		w.updateBitrateCap(false)
+		require.Equal(t, []int32{1, 0, 1}, sender.getTemporalLayerCaps())
+	})
+}
+
+func TestWebRTCReceiver_PacingRate(t *testing.T) {
+	reader := rtpSliceReader(1, 2, 3, 4, 5)
+	for _, pkt := range reader.packets {
+		pkt.RawPacket = make([]byte, 1000)
+	}
+
+	// forwarding loop does not wait, down tracks pace on their own
+	w, sender := newForwardingTestReceiver(reader, WithPacingRate(100_000))
+	start := time.Now()
+	w.forwardRTP(0)
+
+	require.Less(t, time.Since(start), 30*time.Millisecond)
+	require.Equal(t, []uint16{1, 2, 3, 4, 5}, sender.getSequenceNumbers())
+
+	w, _ = newForwardingTestReceiver(rtpSliceReader(), WithPacingRate(100_000))
+	dt := &recordingTrackSender{}
+	require.NoError(t, w.AddDownTrack(dt))
+	require.Equal(t, int64(100_000), dt.getPacingRate())
+}
+
+func TestWebRTCReceiver_TrackForwardStats(t *testing.T) {
+	// per track forward stats are updated without node level forward stats
+	w, _ := newForwardingTestReceiver(rtpSliceReader(1, 2, 3), WithTrackForwardStats(true))
+	require.Nil(t, w.forwardStats)
+	fs := NewForwardStats(trackForwardStatsUpdateInterval, 0, trackForwardStatsWindowLength)
+	w.trackForwardStats[0] = fs
+	w.forwardRTP(0)
+
+	require.Equal(t, uint64(3), fs.packetsForwarded.Load())
+}
+
+func TestWebRTCReceiver_LastPacketTime(t *testing.T) {
+	reader := rtpSliceReader(1, 2, 3)
+	lastArrival := reader.packets[2].Arrival
+	w, _ := newForwardingTestReceiver(reader)
+	w.createdAt = time.Now().Add(-time.Minute)
+
+	require.False(t, w.IsActive())
+	require.True(t, w.LastPacketTime().IsZero())
+	require.GreaterOrEqual(t, w.GetInactiveDuration(), time.Minute)
+
+	w.forwardRTP(0)
+	require.Equal(t, lastArrival.UnixNano(), w.LastPacketTime().UnixNano())
+	require.Less(t, w.GetInactiveDuration(), time.Minute)
+	// receiver is closed when reader reaches end of stream
+	require.False(t, w.IsActive())
+
+	w.closed.Store(false)
+	require.True(t, w.IsActive())
+}
+
+func TestWebRTCReceiver_SubscriberBandwidthOverride(t *testing.T) {
+	w, sender := newForwardingTestReceiver(rtpSliceReader())
+
+	w.SetSubscriberBandwidthOverride(sender.SubscriberID(), 2_000_000)
+	require.EqualValues(t, 2_000_000, sender.getBandwidthOverride())
+	require.EqualValues(t, 2_000_000, w.GetSubscriberBandwidthOverride(sender.SubscriberID()))
+	require.Zero(t, w.GetSubscriberBandwidthOverride("other"))
+
+	// override is applied to a replacing down track
+	replacement := &recordingTrackSender{}
+	require.NoError(t, w.AddDownTrack(replacement))
+	require.EqualValues(t, 2_000_000, replacement.getBandwidthOverride())
+
+	w.SetSubscriberBandwidthOverride(sender.SubscriberID(), 0)
+	require.Zero(t, replacement.getBandwidthOverride())
+	require.Zero(t, w.GetSubscriberBandwidthOverride(sender.SubscriberID()))
+}
+
+func TestWebRTCReceiver_PacketTrace(t *testing.T) {
+	w, sender := newForwardingTestReceiver(rtpSliceReader(1, 2, 3))
+	require.Nil(t, w.getPacketTrace(0, 2))
+
+	w.EnablePacketTrace(0, 2)
+	require.NotNil(t, w.getPacketTrace(0, 2))
+	require.Nil(t, w.getPacketTrace(0, 3))
+	require.Nil(t, w.getPacketTrace(1, 2))
+
+	// traced packet is forwarded like any other
+	w.forwardRTP(0)
+	require.Equal(t, []uint16{1, 2, 3}, sender.getSequenceNumbers())
+
+	// expired trace is disabled
+	w.packetTrace.Load().expiresAt = time.Now().Add(-time.Second)
+	require.Nil(t, w.getPacketTrace(0, 2))
+	require.Nil(t, w.packetTrace.Load())
+
+	w.EnablePacketTrace(0, 2)
+	w.DisablePacketTrace()
+	require.Nil(t, w.getPacketTrace(0, 2))
+}
+
+func TestWebRTCReceiver_GetSSRCForLayer(t *testing.T) {
+	w, _ := newForwardingTestReceiver(rtpSliceReader())
+	w.buffers[0] = buffer.NewBuffer(1000, 10, 10)
+	w.buffers[2] = buffer.NewBuffer(1002, 10, 10)
+
+	require.EqualValues(t, 1000, w.GetSSRCForLayer(0))
+	require.Zero(t, w.GetSSRCForLayer(1))
+	require.EqualValues(t, 1002, w.GetSSRCForLayer(2))
+	require.Zero(t, w.GetSSRCForLayer(buffer.InvalidLayerSpatial))
+	require.Zero(t, w.GetSSRCForLayer(buffer.DefaultMaxLayerSpatial+1))
+
+	require.False(t, w.IsSVC())
+
+	// single stream carries all layers of svc codecs
+	w.isSVC = true
+	require.True(t, w.IsSVC())
+	require.EqualValues(t, 1000, w.GetSSRCForLayer(2))
+}
+
+func TestWebRTCReceiver_RetransmissionTimeouts(t *testing.T) {
+	w, _ := newForwardingTestReceiver(rtpSliceReader())
+	require.Zero(t, w.GetRetransmissionTimeout())
+
+	w, _ = newForwardingTestReceiver(rtpSliceReader(), WithRetransmissionTimeouts(500*time.Millisecond, 50*time.Millisecond))
+	require.Equal(t, 500*time.Millisecond, w.GetRetransmissionTimeout())
+
+	w.kind = webrtc.RTPCodecTypeAudio
+	require.Equal(t, 50*time.Millisecond, w.GetRetransmissionTimeout())
+}
+
+func TestWebRTCReceiver_RTCPInterval(t *testing.T) {
+	var lock sync.Mutex
+	var reports []*rtcp.ReceiverReport
+	w, _ := newForwardingTestReceiver(rtpSliceReader(), WithRTCPInterval(10*time.Millisecond))
+	w.onRTCP = func(pkts []rtcp.Packet) {
+		lock.Lock()
+		defer lock.Unlock()
+		for _, pkt := range pkts {
+			if rr, ok := pkt.(*rtcp.ReceiverReport); ok {
+				reports = append(reports, rr)
+			}
+		}
+	}
+	getReports := func() []*rtcp.ReceiverReport {
+		lock.Lock()
+		defer lock.Unlock()
+		return append([]*rtcp.ReceiverReport{}, reports...)
+	}
+
+	buff := buffer.NewBuffer(1000, 10, 10)
+	buff.Bind(webrtc.RTPParameters{Codecs: []webrtc.RTPCodecParameters{{
+		RTPCodecCapability: webrtc.RTPCodecCapability{MimeType: webrtc.MimeTypeOpus, ClockRate: 48000},
+		PayloadType:        111,
+	}}}, webrtc.RTPCodecCapability{MimeType: webrtc.MimeTypeOpus, ClockRate: 48000}, 0)
+	w.buffers[0] = buff
+	go w.rtcpReportWorker()
+	defer w.close()
+
+	writePacket := func(sn uint16) {
+		pkt := rtp.Packet{
+			Header:  rtp.Header{Version: 2, PayloadType: 111, SequenceNumber: sn, Timestamp: uint32(sn) * 960, SSRC: 1000},
+			Payload: []byte{0xff, 0xff, 0xff, 0xfd},
+		}
+		b, err := pkt.Marshal()
+		require.NoError(t, err)
+		_, err = buff.Write(b)
+		require.NoError(t, err)
+	}
+
+	// reports are sent only when packets are received in the interval
+	time.Sleep(30 * time.Millisecond)
+	require.Empty(t, getReports())
+
+	writePacket(1)
+	writePacket(2)
+	require.Eventually(t, func() bool { return len(getReports()) == 1 }, time.Second, 5*time.Millisecond)
+	require.Equal(t, uint32(1000), getReports()[0].SSRC)
+
+	writePacket(3)
+	require.Eventually(t, func() bool { return len(getReports()) == 2 }, time.Second, 5*time.Millisecond)
+}
+
+func TestScalabilityModeFromStructure(t *testing.T) {
+	template := func(spatial, temporal int, frameDiffs ...int) *dd.FrameDependencyTemplate {
+		return &dd.FrameDependencyTemplate{SpatialId: spatial, TemporalId: temporal, FrameDiffs: frameDiffs}
+	}
+
+	require.Equal(t, "N/A", scalabilityModeFromStructure(nil))
+	require.Equal(t, "N/A", scalabilityModeFromStructure(&dd.FrameDependencyStructure{}))
+
+	// single spatial layer
+	require.Equal(t, "L1T3", scalabilityModeFromStructure(&dd.FrameDependencyStructure{Templates: []*dd.FrameDependencyTemplate{
+		template(0, 0), template(0, 0, 4), template(0, 1, 2), template(0, 2, 1),
+	}}))
+
+	// every upper spatial layer frame references the lower layer frame of the temporal unit
+	require.Equal(t, "L2T2", scalabilityModeFromStructure(&dd.FrameDependencyStructure{Templates: []*dd.FrameDependencyTemplate{
+		template(0, 0), template(1, 0, 1),
+		template(0, 0, 4), template(1, 0, 4, 1),
+		template(0, 1, 2), template(1, 1, 2, 1),
+	}}))
+
+	// only key frames use inter-layer prediction
+	require.Equal(t, "L3T3_KEY", scalabilityModeFromStructure(&dd.FrameDependencyStructure{Templates: []*dd.FrameDependencyTemplate{
+		template(0, 0), template(1, 0, 1), template(2, 0, 1),
+		template(0, 0, 12), template(1, 0, 12), template(2, 0, 12),
+		template(0, 1, 6), template(1, 1, 6), template(2, 1, 6),
+		template(0, 2, 3), template(1, 2, 3), template(2, 2, 3),
+	}}))
+
+	// independent spatial layers
+	require.Equal(t, "S2T1", scalabilityModeFromStructure(&dd.FrameDependencyStructure{Templates: []*dd.FrameDependencyTemplate{
+		template(0, 0), template(1, 0),
+		template(0, 0, 2), template(1, 0, 2),
+	}}))
+
+	w, _ := newForwardingTestReceiver(rtpSliceReader())
+	require.Equal(t, "N/A", w.GetSVCScalabilityStructure())
+	require.Equal(t, "N/A", w.DebugInfo()["ScalabilityStructure"])
+}
+
 func BenchmarkWriteRTP(b *testing.B) {
 	cases := []int{1, 2, 5, 10, 100, 250, 500}
 	workers := runtime.NumCPU()
@@ -47,6 +47,9 @@ type RedPrimaryReceiver struct {
 
 	// bitset for upstream packet receive history [lastSeq-8, lastSeq-1], bit 1 represents packet received
 	pktHistory byte
+
+	// packets missing upstream which are recovered from redundant encodings of later packets
+	packetsRecoveredViaFEC atomic.Uint64
 }
 
 func NewRedPrimaryReceiver(receiver TrackReceiver, dsp DownTrackSpreaderParams) *RedPrimaryReceiver {
@@ -68,6 +71,10 @@ func (r *RedPrimaryReceiver) ForwardRTP(pkt *buffer.ExtPacket, spatialLayer int3
 		r.logger.Errorw("get encoding for red failed", err, "payloadtype", pkt.Packet.PayloadType)
 		return 0
 	}
+	if len(pkts) > 1 {
+		// all but the last packet are recovered from redundant encodings
+		r.packetsRecoveredViaFEC.Add(uint64(len(pkts) - 1))
+	}
 
 	var count int
 	for i, sendPkt := range pkts {
@@ -89,6 +96,11 @@ func (r *RedPrimaryReceiver) ForwardRTP(pkt *buffer.ExtPacket, spatialLayer int3
 	return count
 }
 
+// GetPacketsRecoveredViaFEC returns the number of packets lost upstream and recovered from redundant encodings
+func (r *RedPrimaryReceiver) GetPacketsRecoveredViaFEC() uint64 {
+	return r.packetsRecoveredViaFEC.Load()
+}
+
 func (r *RedPrimaryReceiver) AddDownTrack(track TrackSender) error {
 	if r.closed.Load() {
 		return ErrReceiverClosed
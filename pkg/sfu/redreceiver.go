@@ -16,6 +16,7 @@ package sfu
 
 import (
 	"encoding/binary"
+	"errors"
 	"fmt"
 
 	"go.uber.org/atomic"
@@ -23,15 +24,24 @@ import (
 	"github.com/pion/rtp"
 
 	"github.com/livekit/livekit-server/pkg/sfu/buffer"
+	"github.com/livekit/livekit-server/pkg/sfu/utils"
 	"github.com/livekit/mediatransportutil/pkg/bucket"
 	"github.com/livekit/protocol/livekit"
 	"github.com/livekit/protocol/logger"
 )
 
+var (
+	ErrInvalidRedundancyLevel = errors.New("invalid redundancy level")
+)
+
 const (
 	maxRedCount = 2
 	mtuSize     = 1500
 
+	// number of past packets included in RED packets
+	defaultRedundancyLevel = maxRedCount
+	maxRedundancyLevel     = 3
+
 	// the RedReceiver is only for chrome / native webrtc now, we always negotiate opus payload to 111 with those clients,
 	// so it is safe to use a fixed payload 111 here for performance(avoid encoding red blocks for each downtrack that
 	// have a different opus payload type).
@@ -43,16 +53,34 @@ type RedReceiver struct {
 	downTrackSpreader *DownTrackSpreader
 	logger            logger.Logger
 	closed            atomic.Bool
-	pktBuff           [maxRedCount]*rtp.Packet
+	redundancyLevel   atomic.Int32
+	pktBuff           [maxRedundancyLevel]*rtp.Packet
 	redPayloadBuf     [mtuSize]byte
 }
 
 func NewRedReceiver(receiver TrackReceiver, dsp DownTrackSpreaderParams) *RedReceiver {
-	return &RedReceiver{
+	r := &RedReceiver{
 		TrackReceiver:     receiver,
 		downTrackSpreader: NewDownTrackSpreader(dsp),
 		logger:            dsp.Logger,
 	}
+	r.redundancyLevel.Store(defaultRedundancyLevel)
+	return r
+}
+
+// SetRedundancyLevel sets the number of past packets included in RED packets, between 0 and 3.
+// A level of 0 sends RED packets with the primary payload only.
+func (r *RedReceiver) SetRedundancyLevel(level int) error {
+	if level < 0 || level > maxRedundancyLevel {
+		return ErrInvalidRedundancyLevel
+	}
+
+	r.redundancyLevel.Store(int32(level))
+	return nil
+}
+
+func (r *RedReceiver) GetRedundancyLevel() int {
+	return int(r.redundancyLevel.Load())
 }
 
 func (r *RedReceiver) ForwardRTP(pkt *buffer.ExtPacket, spatialLayer int32) int {
@@ -122,18 +150,20 @@ func (r *RedReceiver) ReadRTP(buf []byte, layer uint8, sn uint16) (int, error) {
 }
 
 func (r *RedReceiver) encodeRedForPrimary(pkt *rtp.Packet, redPayload []byte) (int, error) {
-	redLength := len(r.pktBuff)
+	// history of the most recent packets, as deep as the redundancy level
+	pktBuff := r.pktBuff[len(r.pktBuff)-int(r.redundancyLevel.Load()):]
+	redLength := len(pktBuff)
 	redPkts := make([]*rtp.Packet, 0, redLength+1)
 	lastNilPkt := -1
 	for i := redLength - 1; i >= 0; i-- {
-		if r.pktBuff[i] == nil {
+		if pktBuff[i] == nil {
 			lastNilPkt = i
 			break
 		}
 
 	}
 
-	for _, prev := range r.pktBuff[lastNilPkt+1:] {
+	for _, prev := range pktBuff[lastNilPkt+1:] {
 		if pkt.SequenceNumber == prev.SequenceNumber ||
 			(pkt.SequenceNumber-prev.SequenceNumber) > uint16(redLength) ||
 			(pkt.Timestamp-prev.Timestamp) >= (1<<14) {
@@ -146,13 +176,13 @@ func (r *RedReceiver) encodeRedForPrimary(pkt *rtp.Packet, redPayload []byte) (i
 	// NOTE: packet is copied from retransmission buffer and used in forwarding path. So, not making another
 	// copy here and just maintaining pointer to the packet as the forwarding path should not alter the packet.
 	for i := redLength - 1; i >= 0; i-- {
-		if r.pktBuff[i] == nil || // history is empty
-			pkt.SequenceNumber-r.pktBuff[i].SequenceNumber < (1<<15) { // received packet has more recent sequence number
+		if pktBuff[i] == nil || // history is empty
+			utils.Distance(pktBuff[i].SequenceNumber, pkt.SequenceNumber) >= 0 { // received packet has more recent sequence number
 			// age out older ones
 			for j := 0; j < i; j++ {
-				r.pktBuff[j] = r.pktBuff[j+1]
+				pktBuff[j] = pktBuff[j+1]
 			}
-			r.pktBuff[i] = pkt
+			pktBuff[i] = pkt
 			break
 		}
 	}
@@ -214,6 +214,33 @@ func TestRedReceiver(t *testing.T) {
 	})
 }
 
+func TestRedReceiver_RedundancyLevel(t *testing.T) {
+	dt := &dummyDowntrack{TrackSender: &DownTrack{}}
+	w := &WebRTCReceiver{
+		kind:   webrtc.RTPCodecTypeAudio,
+		logger: logger.GetLogger(),
+	}
+	red := w.GetRedReceiver().(*RedReceiver)
+	require.NoError(t, red.AddDownTrack(dt))
+	require.Equal(t, defaultRedundancyLevel, red.GetRedundancyLevel())
+
+	require.ErrorIs(t, red.SetRedundancyLevel(-1), ErrInvalidRedundancyLevel)
+	require.ErrorIs(t, red.SetRedundancyLevel(maxRedundancyLevel+1), ErrInvalidRedundancyLevel)
+	require.Equal(t, defaultRedundancyLevel, red.GetRedundancyLevel())
+
+	header := rtp.Header{SequenceNumber: 65534, Timestamp: (uint32(1) << 31) - 2*tsStep, PayloadType: 111}
+	pkts := generatePkts(header, 12, tsStep)
+	// decreasing levels, history of a deeper level covers a shallower one
+	for i, pkt := range pkts {
+		level := maxRedundancyLevel - i/3
+		require.NoError(t, red.SetRedundancyLevel(level))
+		red.ForwardRTP(&buffer.ExtPacket{
+			Packet: pkt,
+		}, 0)
+		verifyRedEncodings(t, dt.lastReceivedPkt, pkts[max(i-level, 0):i+1])
+	}
+}
+
 func verifyRedEncodings(t *testing.T, red *rtp.Packet, redPkts []*rtp.Packet) {
 	solidPkts := make([]*rtp.Packet, 0, len(redPkts))
 	for _, pkt := range redPkts {
@@ -304,6 +331,9 @@ func testRedRedPrimaryReceiver(t *testing.T, maxPktCount, redCount int, sendPktI
 	}
 
 	verifyPktsEqual(t, expectPkts, dt.receivedPkts)
+	// every sent packet forwards its primary encoding, the rest are recovered
+	require.EqualValues(t, len(expectPktIdx)-len(sendPktIdx), red.GetPacketsRecoveredViaFEC())
+	require.EqualValues(t, red.GetPacketsRecoveredViaFEC(), w.DebugInfo()["PacketsRecoveredViaFEC"])
 }
 
 func TestRedPrimaryReceiver(t *testing.T) {
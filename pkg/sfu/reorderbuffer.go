@@ -0,0 +1,253 @@
+// Copyright 2024 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sfu
+
+import (
+	"io"
+	"time"
+
+	"github.com/livekit/livekit-server/pkg/sfu/buffer"
+)
+
+const (
+	// packets read ahead from source waiting to be ordered
+	reorderBufferQueueSize = 64
+	// used when RTT is not known yet
+	reorderBufferDefaultTimeout = 100 * time.Millisecond
+)
+
+type reorderBufferPacket struct {
+	slot   int
+	pkt    *buffer.ExtPacket
+	heldAt time.Time
+	isHeld bool
+}
+
+// reorderBuffer releases packets read from a buffer in sequence number order. Up to depth sequence numbers
+// are held waiting for missing ones, a gap is skipped when a packet beyond depth arrives or when a packet has been held
+// longer than one RTT. Packets arriving after their sequence number has been skipped are released as is.
+//
+// Packets are read into a fixed pool of slots by a goroutine which stops when source reaches end of stream
+// or when done is closed. Held packets are kept in a ring indexed by sequence number.
+type reorderBuffer struct {
+	source ExtPacketReader
+	depth  int
+	getRTT func() time.Duration
+	done   <-chan struct{}
+
+	slots   *packetSlotPool
+	packets chan reorderBufferPacket
+
+	// accessed only by reader
+	initialized bool
+	nextSN      uint64
+	held        []reorderBufferPacket
+	numHeld     int
+	overflow    reorderBufferPacket // packet beyond depth, valid when isHeld is set
+	lastSlot    int
+	isEOF       bool
+	timer       *time.Timer
+}
+
+func newReorderBuffer(source ExtPacketReader, depth int, getRTT func() time.Duration, done <-chan struct{}) *reorderBuffer {
+	r := &reorderBuffer{
+		source: source,
+		depth:  depth,
+		getRTT: getRTT,
+		done:   done,
+		// slots for held, queued, overflow, being read and last returned packets
+		slots:    newPacketSlotPool(depth + reorderBufferQueueSize + 3),
+		packets:  make(chan reorderBufferPacket, reorderBufferQueueSize),
+		held:     make([]reorderBufferPacket, depth),
+		lastSlot: -1,
+	}
+	go r.readWorker()
+	return r
+}
+
+func (r *reorderBuffer) readWorker() {
+	defer close(r.packets)
+
+	for {
+		slot, ok := r.slots.get(r.done)
+		if !ok {
+			return
+		}
+
+		pkt, err := r.source.ReadExtended(r.slots.buf(slot))
+		if err != nil {
+			r.slots.put(slot)
+			return
+		}
+
+		select {
+		case r.packets <- reorderBufferPacket{slot: slot, pkt: pkt}:
+		case <-r.done:
+			return
+		}
+	}
+}
+
+func (r *reorderBuffer) ReadExtended(_ []byte) (*buffer.ExtPacket, error) {
+	// previously returned packet has been consumed, its slot can be reused
+	if r.lastSlot >= 0 {
+		r.slots.put(r.lastSlot)
+		r.lastSlot = -1
+	}
+
+	select {
+	case <-r.done:
+		return nil, io.EOF
+	default:
+	}
+
+	for {
+		if r.overflow.isHeld {
+			switch {
+			case r.overflow.pkt.ExtSequenceNumber < r.nextSN+uint64(r.depth):
+				op := r.overflow
+				r.overflow = reorderBufferPacket{}
+				if pkt, ok := r.add(op); ok {
+					return pkt, nil
+				}
+				continue
+
+			case r.numHeld != 0:
+				// give up on missing sequence numbers to make room
+				r.nextSN, _ = r.getHeld()
+
+			default:
+				r.nextSN = r.overflow.pkt.ExtSequenceNumber - uint64(r.depth) + 1
+				continue
+			}
+		}
+
+		if hp := &r.held[r.nextSN%uint64(r.depth)]; hp.isHeld && hp.pkt.ExtSequenceNumber == r.nextSN {
+			hp.isHeld = false
+			r.numHeld--
+			r.nextSN++
+			r.lastSlot = hp.slot
+			return hp.pkt, nil
+		}
+
+		lowestSN, earliestHeldAt := r.getHeld()
+		if r.numHeld != 0 && (r.isEOF || time.Since(earliestHeldAt) >= r.timeout()) {
+			// give up on missing sequence numbers
+			r.nextSN = lowestSN
+			continue
+		}
+
+		if r.isEOF {
+			return nil, io.EOF
+		}
+
+		var timeoutCh <-chan time.Time
+		if r.numHeld != 0 {
+			r.resetTimer(time.Until(earliestHeldAt.Add(r.timeout())))
+			timeoutCh = r.timer.C
+		}
+
+		select {
+		case <-r.done:
+			return nil, io.EOF
+
+		case <-timeoutCh:
+
+		case p, ok := <-r.packets:
+			if !ok {
+				r.isEOF = true
+				continue
+			}
+			if pkt, ok := r.add(p); ok {
+				return pkt, nil
+			}
+		}
+	}
+}
+
+// add holds a packet for ordering, returns the packet if it should be released right away
+func (r *reorderBuffer) add(p reorderBufferPacket) (*buffer.ExtPacket, bool) {
+	sn := p.pkt.ExtSequenceNumber
+	if !r.initialized {
+		r.initialized = true
+		r.nextSN = sn
+	}
+
+	switch {
+	case sn < r.nextSN:
+		// too late to be ordered
+		r.lastSlot = p.slot
+		return p.pkt, true
+
+	case sn >= r.nextSN+uint64(r.depth):
+		p.isHeld = true
+		r.overflow = p
+		return nil, false
+	}
+
+	hp := &r.held[sn%uint64(r.depth)]
+	if hp.isHeld {
+		// duplicate
+		r.slots.put(p.slot)
+		return nil, false
+	}
+
+	p.heldAt = time.Now()
+	p.isHeld = true
+	*hp = p
+	r.numHeld++
+	return nil, false
+}
+
+// getHeld returns lowest held sequence number and the earliest time at which a held packet was queued
+func (r *reorderBuffer) getHeld() (lowestSN uint64, earliestHeldAt time.Time) {
+	isFirst := true
+	for i := range r.held {
+		hp := &r.held[i]
+		if !hp.isHeld {
+			continue
+		}
+		if isFirst || hp.pkt.ExtSequenceNumber < lowestSN {
+			lowestSN = hp.pkt.ExtSequenceNumber
+		}
+		if isFirst || hp.heldAt.Before(earliestHeldAt) {
+			earliestHeldAt = hp.heldAt
+		}
+		isFirst = false
+	}
+	return
+}
+
+func (r *reorderBuffer) resetTimer(d time.Duration) {
+	if r.timer == nil {
+		r.timer = time.NewTimer(d)
+		return
+	}
+
+	if !r.timer.Stop() {
+		select {
+		case <-r.timer.C:
+		default:
+		}
+	}
+	r.timer.Reset(d)
+}
+
+func (r *reorderBuffer) timeout() time.Duration {
+	if rtt := r.getRTT(); rtt > 0 {
+		return rtt
+	}
+	return reorderBufferDefaultTimeout
+}
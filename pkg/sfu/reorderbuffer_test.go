@@ -0,0 +1,128 @@
+// Copyright 2024 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sfu
+
+import (
+	"io"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/livekit/livekit-server/pkg/sfu/buffer"
+)
+
+// endlessReader produces packets in sequence number order without end
+type endlessReader struct {
+	sn uint64
+}
+
+func (e *endlessReader) ReadExtended(_ []byte) (*buffer.ExtPacket, error) {
+	e.sn++
+	return &buffer.ExtPacket{ExtSequenceNumber: e.sn}, nil
+}
+
+type chanReader struct {
+	packets chan *buffer.ExtPacket
+}
+
+func (c *chanReader) ReadExtended(_ []byte) (*buffer.ExtPacket, error) {
+	pkt, ok := <-c.packets
+	if !ok {
+		return nil, io.EOF
+	}
+	return pkt, nil
+}
+
+func sliceReaderWithSNs(sns ...uint64) *sliceReader {
+	s := &sliceReader{}
+	for _, sn := range sns {
+		s.packets = append(s.packets, &buffer.ExtPacket{ExtSequenceNumber: sn})
+	}
+	return s
+}
+
+func TestReorderBuffer(t *testing.T) {
+	rtt := func() time.Duration { return time.Second }
+
+	t.Run("orders", func(t *testing.T) {
+		r := newReorderBuffer(sliceReaderWithSNs(10, 12, 11, 13, 16, 14, 15), 4, rtt, make(chan struct{}))
+		require.Equal(t, []uint64{10, 11, 12, 13, 14, 15, 16}, readAll(t, r))
+	})
+
+	t.Run("skips missing when full", func(t *testing.T) {
+		r := newReorderBuffer(sliceReaderWithSNs(10, 11, 13, 14, 15, 12), 3, rtt, make(chan struct{}))
+		// 12 arrives after it has been given up on
+		require.Equal(t, []uint64{10, 11, 13, 14, 15, 12}, readAll(t, r))
+	})
+
+	t.Run("releases stuck packets after rtt", func(t *testing.T) {
+		source := &chanReader{packets: make(chan *buffer.ExtPacket, 10)}
+		source.packets <- &buffer.ExtPacket{ExtSequenceNumber: 10}
+		source.packets <- &buffer.ExtPacket{ExtSequenceNumber: 12}
+
+		r := newReorderBuffer(source, 10, func() time.Duration { return 50 * time.Millisecond }, make(chan struct{}))
+		pkt, err := r.ReadExtended(nil)
+		require.NoError(t, err)
+		require.Equal(t, uint64(10), pkt.ExtSequenceNumber)
+
+		start := time.Now()
+		pkt, err = r.ReadExtended(nil)
+		require.NoError(t, err)
+		require.Equal(t, uint64(12), pkt.ExtSequenceNumber)
+		require.Less(t, time.Since(start), time.Second)
+
+		close(source.packets)
+		_, err = r.ReadExtended(nil)
+		require.ErrorIs(t, err, io.EOF)
+	})
+	t.Run("reuses slots", func(t *testing.T) {
+		sns := []uint64{0}
+		for sn := uint64(1); sn <= 1000; sn++ {
+			// swap pairs
+			sns = append(sns, ((sn-1)^1)+1)
+		}
+		r := newReorderBuffer(sliceReaderWithSNs(sns...), 4, rtt, make(chan struct{}))
+
+		ordered := readAll(t, r)
+		require.Len(t, ordered, 1001)
+		for i, sn := range ordered {
+			require.Equal(t, uint64(i), sn)
+		}
+	})
+
+	t.Run("does not leak goroutine", func(t *testing.T) {
+		numGoroutines := runtime.NumGoroutine()
+
+		done := make(chan struct{})
+		r := newReorderBuffer(&endlessReader{}, 4, rtt, done)
+		for i := 0; i < 10; i++ {
+			_, err := r.ReadExtended(nil)
+			require.NoError(t, err)
+		}
+
+		// reader stops reading, read goroutine is blocked on a full queue till done
+		close(done)
+		// not using require.Eventually as it runs condition in a goroutine of its own
+		for i := 0; i < 100 && runtime.NumGoroutine() > numGoroutines; i++ {
+			time.Sleep(10 * time.Millisecond)
+		}
+		require.LessOrEqual(t, runtime.NumGoroutine(), numGoroutines)
+
+		_, err := r.ReadExtended(nil)
+		require.ErrorIs(t, err, io.EOF)
+	})
+}
@@ -0,0 +1,64 @@
+// Copyright 2024 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sfu
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	roomBitrateLimiterWindow = time.Second
+)
+
+// RoomBitrateLimiter caps the aggregate bitrate of media published in a room.
+// It is shared by receivers of all tracks in the room. Bytes received in fixed windows of
+// roomBitrateLimiterWindow are counted. The room is over budget while the current window
+// exceeds the budget and for the whole window following one which exceeded it.
+//
+// The limiter does not drop packets, dropping arbitrary packets would leave gaps which corrupt
+// decoding till the next key frame. Receivers cap the temporal layers forwarded by their
+// down tracks instead, which drops whole non-reference frames with sequence number translation.
+type RoomBitrateLimiter struct {
+	maxBitrateBps uint64
+
+	lock                   sync.Mutex
+	windowStart            time.Time
+	windowBytes            uint64
+	isPrevWindowOverBudget bool
+}
+
+func NewRoomBitrateLimiter(maxBitrateBps uint64) *RoomBitrateLimiter {
+	return &RoomBitrateLimiter{
+		maxBitrateBps: maxBitrateBps,
+	}
+}
+
+// Add accounts a packet of the given size arriving at the given time and returns true if the room is over budget.
+func (r *RoomBitrateLimiter) Add(size int, now time.Time) bool {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	budgetBytes := r.maxBitrateBps * uint64(roomBitrateLimiterWindow) / uint64(time.Second) / 8
+	if elapsed := now.Sub(r.windowStart); r.windowStart.IsZero() || elapsed >= roomBitrateLimiterWindow {
+		// a window without packets in between is under budget
+		r.isPrevWindowOverBudget = !r.windowStart.IsZero() && elapsed < 2*roomBitrateLimiterWindow && r.windowBytes > budgetBytes
+		r.windowStart = now
+		r.windowBytes = 0
+	}
+
+	r.windowBytes += uint64(size)
+	return r.isPrevWindowOverBudget || r.windowBytes > budgetBytes
+}
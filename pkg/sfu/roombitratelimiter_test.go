@@ -0,0 +1,40 @@
+// Copyright 2024 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sfu
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRoomBitrateLimiter(t *testing.T) {
+	// 8000 bps, budget of 1000 bytes per window
+	r := NewRoomBitrateLimiter(8000)
+	now := time.Now()
+
+	require.False(t, r.Add(600, now))
+	require.False(t, r.Add(400, now.Add(100*time.Millisecond)))
+	require.True(t, r.Add(1, now.Add(200*time.Millisecond)))
+
+	// stays over budget for the window after one over budget
+	require.True(t, r.Add(100, now.Add(time.Second)))
+	require.False(t, r.Add(100, now.Add(2*time.Second)))
+
+	// a window without packets in between resets
+	require.True(t, r.Add(1001, now.Add(3*time.Second)))
+	require.False(t, r.Add(100, now.Add(5*time.Second)))
+}
@@ -297,7 +297,7 @@ func (r *RTPMunger) FilterRTX(nacks []uint16) []uint16 {
 
 	filtered := make([]uint16, 0, len(nacks))
 	for _, sn := range nacks {
-		if (sn - uint16(r.extRtxGateSn)) < (1 << 15) {
+		if utils.Distance(uint16(r.extRtxGateSn), sn) >= 0 {
 			filtered = append(filtered, sn)
 		}
 	}
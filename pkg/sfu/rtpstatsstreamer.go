@@ -0,0 +1,138 @@
+// Copyright 2024 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sfu
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/livekit/protocol/livekit"
+)
+
+const (
+	defaultRTPStatsStreamInterval = time.Second
+)
+
+// RTPStatsStreamKey identifies a stream of a track, a track can be received in multiple codecs
+type RTPStatsStreamKey struct {
+	TrackID  livekit.TrackID
+	MimeType string
+}
+
+type rtpStatsEntry struct {
+	stats      *livekit.RTPStats
+	generation uint64
+}
+
+// RTPStatsStreamer holds the latest RTP stats of receivers and streams them to subscribers
+// at a subscriber chosen interval. Receivers update it on every stats update.
+type RTPStatsStreamer struct {
+	lock       sync.RWMutex
+	generation uint64
+	latest     map[RTPStatsStreamKey]rtpStatsEntry
+}
+
+func NewRTPStatsStreamer() *RTPStatsStreamer {
+	return &RTPStatsStreamer{
+		latest: make(map[RTPStatsStreamKey]rtpStatsEntry),
+	}
+}
+
+func (r *RTPStatsStreamer) Update(key RTPStatsStreamKey, stats *livekit.RTPStats) {
+	if stats == nil {
+		return
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.generation++
+	r.latest[key] = rtpStatsEntry{
+		stats:      stats,
+		generation: r.generation,
+	}
+}
+
+func (r *RTPStatsStreamer) Remove(key RTPStatsStreamKey) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	delete(r.latest, key)
+}
+
+// Stream sends stats of streams of the given tracks, or of all tracks if none are given, every interval
+// (1s if interval is not positive) until the context is done or send fails. Stats of a stream
+// are sent only when updated since the last send.
+func (r *RTPStatsStreamer) Stream(
+	ctx context.Context,
+	interval time.Duration,
+	trackIDs []livekit.TrackID,
+	send func(key RTPStatsStreamKey, stats *livekit.RTPStats) error,
+) error {
+	if interval <= 0 {
+		interval = defaultRTPStatsStreamInterval
+	}
+
+	var filter map[livekit.TrackID]bool
+	if len(trackIDs) != 0 {
+		filter = make(map[livekit.TrackID]bool, len(trackIDs))
+		for _, trackID := range trackIDs {
+			filter[trackID] = true
+		}
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	sent := make(map[RTPStatsStreamKey]uint64)
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+
+		case <-ticker.C:
+			for key, entry := range r.getUpdated(filter, sent) {
+				if err := send(key, entry.stats); err != nil {
+					return err
+				}
+				sent[key] = entry.generation
+			}
+		}
+	}
+}
+
+// getUpdated returns streams updated since last sent and prunes removed streams from sent
+func (r *RTPStatsStreamer) getUpdated(filter map[livekit.TrackID]bool, sent map[RTPStatsStreamKey]uint64) map[RTPStatsStreamKey]rtpStatsEntry {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	for key := range sent {
+		if _, ok := r.latest[key]; !ok {
+			delete(sent, key)
+		}
+	}
+
+	updated := make(map[RTPStatsStreamKey]rtpStatsEntry)
+	for key, entry := range r.latest {
+		if filter != nil && !filter[key.TrackID] {
+			continue
+		}
+		if entry.generation > sent[key] {
+			updated[key] = entry
+		}
+	}
+	return updated
+}
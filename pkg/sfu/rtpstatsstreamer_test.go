@@ -0,0 +1,108 @@
+// Copyright 2024 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sfu
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/livekit/protocol/livekit"
+)
+
+func TestRTPStatsStreamer(t *testing.T) {
+	keyA := RTPStatsStreamKey{TrackID: "TR_a", MimeType: "video/VP8"}
+	keyAH264 := RTPStatsStreamKey{TrackID: "TR_a", MimeType: "video/H264"}
+	r := NewRTPStatsStreamer()
+	r.Update(keyA, &livekit.RTPStats{Packets: 1})
+	r.Update(keyAH264, &livekit.RTPStats{Packets: 10})
+	r.Update(RTPStatsStreamKey{TrackID: "TR_b"}, &livekit.RTPStats{Packets: 2})
+	r.Update(RTPStatsStreamKey{TrackID: "TR_c"}, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var lock sync.Mutex
+	received := make(map[string][]uint32)
+	done := make(chan error, 1)
+	go func() {
+		done <- r.Stream(ctx, 10*time.Millisecond, []livekit.TrackID{"TR_a", "TR_c"}, func(key RTPStatsStreamKey, stats *livekit.RTPStats) error {
+			require.Equal(t, livekit.TrackID("TR_a"), key.TrackID)
+
+			lock.Lock()
+			defer lock.Unlock()
+			received[key.MimeType] = append(received[key.MimeType], stats.Packets)
+			return nil
+		})
+	}()
+
+	getReceived := func(mimeType string) []uint32 {
+		lock.Lock()
+		defer lock.Unlock()
+		return append([]uint32{}, received[mimeType]...)
+	}
+	require.Eventually(t, func() bool {
+		return len(getReceived("video/VP8")) == 1 && len(getReceived("video/H264")) == 1
+	}, time.Second, 5*time.Millisecond)
+	require.Equal(t, []uint32{10}, getReceived("video/H264"))
+
+	// not updated, not sent again
+	time.Sleep(30 * time.Millisecond)
+	require.Equal(t, []uint32{1}, getReceived("video/VP8"))
+
+	// removing one codec does not affect the other
+	r.Remove(keyAH264)
+	r.Update(keyA, &livekit.RTPStats{Packets: 3})
+	require.Eventually(t, func() bool { return len(getReceived("video/VP8")) == 2 }, time.Second, 5*time.Millisecond)
+	require.Equal(t, []uint32{1, 3}, getReceived("video/VP8"))
+	require.Equal(t, []uint32{10}, getReceived("video/H264"))
+
+	cancel()
+	require.ErrorIs(t, <-done, context.Canceled)
+}
+
+func TestRTPStatsStreamer_PrunesSent(t *testing.T) {
+	key := RTPStatsStreamKey{TrackID: "TR_a", MimeType: "video/VP8"}
+	r := NewRTPStatsStreamer()
+	r.Update(key, &livekit.RTPStats{Packets: 1})
+
+	sent := make(map[RTPStatsStreamKey]uint64)
+	for k, entry := range r.getUpdated(nil, sent) {
+		sent[k] = entry.generation
+	}
+	require.Len(t, sent, 1)
+
+	r.Remove(key)
+	require.Empty(t, r.getUpdated(nil, sent))
+	require.Empty(t, sent)
+}
+
+func TestRTPStatsStreamer_SendError(t *testing.T) {
+	r := NewRTPStatsStreamer()
+	r.Update(RTPStatsStreamKey{TrackID: "TR_a"}, &livekit.RTPStats{Packets: 1})
+	r.Remove(RTPStatsStreamKey{TrackID: "TR_a"})
+	r.Update(RTPStatsStreamKey{TrackID: "TR_b"}, &livekit.RTPStats{Packets: 2})
+
+	errSend := errors.New("send failed")
+	err := r.Stream(context.Background(), 10*time.Millisecond, nil, func(key RTPStatsStreamKey, _ *livekit.RTPStats) error {
+		require.Equal(t, livekit.TrackID("TR_b"), key.TrackID)
+		return errSend
+	})
+	require.ErrorIs(t, err, errSend)
+}
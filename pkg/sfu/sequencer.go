@@ -16,6 +16,7 @@ package sfu
 
 import (
 	"math"
+	"sort"
 	"sync"
 	"time"
 
@@ -24,9 +25,18 @@ import (
 )
 
 const (
-	defaultRtt           = 70
-	ignoreRetransmission = 100 // Ignore packet retransmission after ignoreRetransmission milliseconds
-	maxAck               = 3
+	defaultRtt    = 70
+	defaultMaxAck = 3
+
+	// ignore packet retransmission after retransmission timeout
+	defaultRetransmissionTimeout = 100 * time.Millisecond
+
+	// maximum number of sequence numbers looked up per NACK, the rest are ignored
+	defaultMaxNACKBatchSize = 100
+
+	h264NALTypeIDR   = 5
+	h264NALTypeSTAPA = 24
+	h264NALTypeFUA   = 28
 )
 
 func btoi(b bool) int {
@@ -56,12 +66,19 @@ type packetMeta struct {
 	timestamp uint32
 	// Modified marker
 	marker bool
+	// Packet belongs to a key frame
+	isKeyFrame bool
+	// H.264 NAL unit type carried by the packet, for FU-A the type of the fragmented
+	// NAL unit. 0 if not H.264.
+	nalType uint8
 	// The last time this packet was nack requested.
 	// Sometimes clients request the same packet more than once, so keep
 	// track of the requested packets helps to avoid writing multiple times
 	// the same packet.
 	// The resolution is 1 ms counting after the sequencer start time.
 	lastNack uint32
+	// The time this packet was pushed, same resolution as lastNack.
+	pushedAt uint32
 	// number of NACKs this packet has received
 	nacked uint8
 	// Spatial layer of packet
@@ -85,6 +102,31 @@ type extPacketMeta struct {
 	extTimestamp      uint64
 }
 
+// SequencerDiagnostics is a snapshot of the sequencer ring buffer state for detecting capacity issues.
+type SequencerDiagnostics struct {
+	// packets which could not be cached as they are too far behind the head
+	DroppedOldPackets uint64
+	// packets pushed again while still cached, the cached entry is replaced by the duplicate
+	DroppedDuplicates uint64
+	// highest sequence number cached
+	HeadSN uint16
+	// oldest sequence number still cached
+	OldestCachedSN uint16
+	// fraction of slots holding a valid packet, same as GetCacheOccupancy
+	CacheOccupancy float32
+}
+
+// PacketMetaSummary describes a packet cached in the sequencer.
+type PacketMetaSummary struct {
+	TargetSN  uint16
+	SourceSN  uint16
+	Timestamp uint32
+	Layer     int8
+	NACKed    uint8
+	// time since the packet was cached
+	AgeMs uint32
+}
+
 // Sequencer stores the packet sequence received by the down track
 type sequencer struct {
 	sync.Mutex
@@ -95,19 +137,50 @@ type sequencer struct {
 	extHighestSN uint64
 	snOffset     uint64
 	extHighestTS uint64
-	meta         []packetMeta
-	snRangeMap   *utils.RangeMap[uint64, uint64]
-	rtt          uint32
-	logger       logger.Logger
+	// layer of the last frame ending packet, used to detect layer switches
+	lastMarkerLayer int8
+	// key frame indication is available only on some packets of a key frame,
+	// remember the key frame timestamp to mark all packets of the key frame
+	keyFrameExtTS     uint64
+	isKeyFrameTSValid bool
+	meta              []packetMeta
+	snRangeMap        *utils.RangeMap[uint64, uint64]
+	rtt               uint32
+	maxAck            uint8
+	isH264            bool
+	logger            logger.Logger
+
+	// minimum interval between retransmissions of a packet, capped at 2 * RTT
+	retransmissionTimeout time.Duration
+	// limits allocation for NACKs of misbehaving subscribers
+	maxNACKBatchSize int
+
+	numOccupied              int
+	numPrematureEvictions    uint64
+	isPrematureEvictionAlert bool
+
+	numDroppedOld        uint64
+	numDroppedDuplicates uint64
 }
 
-func newSequencer(size int, maybeSparse bool, logger logger.Logger) *sequencer {
+func newSequencer(size int, maybeSparse bool, maxAck int, logger logger.Logger) *sequencer {
+	if maxAck <= 0 {
+		maxAck = defaultMaxAck
+	} else if maxAck > math.MaxUint8 {
+		maxAck = math.MaxUint8
+	}
 	s := &sequencer{
 		size:      size,
 		startTime: time.Now().UnixMilli(),
 		meta:      make([]packetMeta, size),
 		rtt:       defaultRtt,
+		maxAck:    uint8(maxAck),
 		logger:    logger,
+
+		retransmissionTimeout: defaultRetransmissionTimeout,
+		maxNACKBatchSize:      defaultMaxNACKBatchSize,
+
+		lastMarkerLayer: -1,
 	}
 
 	if maybeSparse {
@@ -132,11 +205,13 @@ func (s *sequencer) push(
 	extIncomingSN, extModifiedSN uint64,
 	extModifiedTS uint64,
 	marker bool,
+	isKeyFrame bool,
 	layer int8,
 	codecBytes []byte,
 	numCodecBytesIn int,
 	ddBytes []byte,
 	actBytes []byte,
+	payload []byte,
 ) {
 	s.Lock()
 	defer s.Unlock()
@@ -151,6 +226,7 @@ func (s *sequencer) push(
 
 	if extModifiedSN < s.extStartSN {
 		// old packet, should not happen
+		s.numDroppedOld++
 		return
 	}
 
@@ -168,6 +244,7 @@ func (s *sequencer) push(
 					"extModifiedSN", extModifiedSN,
 					"snOffset", s.snOffset,
 				)
+				s.numDroppedOld++
 				return
 			}
 
@@ -182,9 +259,16 @@ func (s *sequencer) push(
 			"extIncomingSN", extIncomingSN,
 			"extModifiedSN", extModifiedSN,
 		)
+		s.numDroppedOld++
 		return
 	}
 
+	slot := extModifiedSNAdjusted % uint64(s.size)
+	if extModifiedSNAdjusted <= extHighestSNAdjusted && s.slotHolds(int(slot), uint16(extModifiedSN)) {
+		// cached entry is replaced, the previous one is dropped
+		s.numDroppedDuplicates++
+	}
+
 	// invalidate missing sequence numbers
 	if extModifiedSNAdjusted > extHighestSNAdjusted {
 		numInvalidated := 0
@@ -197,17 +281,49 @@ func (s *sequencer) push(
 		}
 	}
 
-	slot := extModifiedSNAdjusted % uint64(s.size)
+	refTime := s.getRefTime(packetTime)
+	if marker {
+		if s.lastMarkerLayer != -1 && layer != s.lastMarkerLayer {
+			// layer switched, packets before the switch are stale, hold off retransmitting them,
+			// but keep the packets of the frame completed by this packet
+			s.flushNackQueue(s.getRefTime(time.Now()), func(pm *packetMeta) bool {
+				return pm.timestamp == uint32(extModifiedTS)
+			})
+		}
+		s.lastMarkerLayer = layer
+	}
+
+	if isKeyFrame {
+		s.keyFrameExtTS = extModifiedTS
+		s.isKeyFrameTSValid = true
+	} else if s.isKeyFrameTSValid && s.keyFrameExtTS == extModifiedTS {
+		isKeyFrame = true
+	}
+
+	wasInvalid := s.isInvalidSlot(int(slot))
+	if !wasInvalid {
+		s.checkEviction(&s.meta[slot], refTime)
+	}
 	s.meta[slot] = packetMeta{
 		sourceSeqNo:     uint16(extIncomingSN),
 		targetSeqNo:     uint16(extModifiedSN),
 		timestamp:       uint32(extModifiedTS),
 		marker:          marker,
+		isKeyFrame:      isKeyFrame,
 		layer:           layer,
 		numCodecBytesIn: uint8(numCodecBytesIn),
-		lastNack:        s.getRefTime(packetTime), // delay retransmissions after the original transmission
+		pushedAt:        refTime,
+		lastNack:        refTime, // delay retransmissions after the original transmission
 	}
 	pm := &s.meta[slot]
+	if s.isH264 {
+		pm.nalType = getH264NALType(payload)
+	}
+	s.updateOccupancy(wasInvalid, s.isInvalidSlot(int(slot)))
+	if isKeyFrame {
+		// mark packets of the key frame which were pushed earlier
+		s.markKeyFrame(int(slot), pm.timestamp)
+	}
 
 	pm.numCodecBytesOut = uint8(len(codecBytes))
 	if len(codecBytes) > len(pm.codecBytes) {
@@ -233,6 +349,13 @@ func (s *sequencer) push(
 	}
 }
 
+func (s *sequencer) setH264(isH264 bool) {
+	s.Lock()
+	defer s.Unlock()
+
+	s.isH264 = isH264
+}
+
 func (s *sequencer) pushPadding(extStartSNInclusive uint64, extEndSNInclusive uint64) {
 	s.Lock()
 	defer s.Unlock()
@@ -289,10 +412,37 @@ func (s *sequencer) getExtPacketMetas(seqNo []uint16) []extPacketMeta {
 	s.Lock()
 	defer s.Unlock()
 
+	return s.getExtPacketMetasLocked(seqNo, true)
+}
+
+// PeekPacketsMeta returns the packets that would be retransmitted for the given sequence numbers,
+// but without counting it as a NACK, i. e. retransmission state of packets is not modified.
+func (s *sequencer) PeekPacketsMeta(seqNo []uint16) []packetMeta {
+	s.Lock()
+	defer s.Unlock()
+
+	extPacketMetas := s.getExtPacketMetasLocked(seqNo, false)
+	packetMetas := make([]packetMeta, 0, len(extPacketMetas))
+	for _, epm := range extPacketMetas {
+		packetMetas = append(packetMetas, epm.packetMeta)
+	}
+	return packetMetas
+}
+
+func (s *sequencer) getExtPacketMetasLocked(seqNo []uint16, consume bool) []extPacketMeta {
 	if !s.initialized {
 		return nil
 	}
 
+	if len(seqNo) > s.maxNACKBatchSize {
+		s.logger.Warnw(
+			"too many sequence numbers in NACK, truncating", nil,
+			"count", len(seqNo),
+			"maxNACKBatchSize", s.maxNACKBatchSize,
+		)
+		seqNo = seqNo[:s.maxNACKBatchSize]
+	}
+
 	snOffset := uint64(0)
 	var err error
 	extPacketMetas := make([]extPacketMeta, 0, len(seqNo))
@@ -300,8 +450,7 @@ func (s *sequencer) getExtPacketMetas(seqNo []uint16) []extPacketMeta {
 	highestSN := uint16(s.extHighestSN)
 	highestTS := uint32(s.extHighestTS)
 	for _, sn := range seqNo {
-		diff := highestSN - sn
-		if diff > (1 << 15) {
+		if utils.Distance(sn, highestSN) < 0 {
 			// out-of-order from head (should not happen, just be safe)
 			continue
 		}
@@ -334,9 +483,11 @@ func (s *sequencer) getExtPacketMetas(seqNo []uint16) []extPacketMeta {
 			continue
 		}
 
-		if meta.nacked < maxAck && refTime-meta.lastNack > uint32(math.Min(float64(ignoreRetransmission), float64(2*s.rtt))) {
-			meta.nacked++
-			meta.lastNack = refTime
+		if meta.nacked < s.maxAck && refTime-meta.lastNack > s.getRetransmissionIntervalLocked() {
+			if consume {
+				meta.nacked++
+				meta.lastNack = refTime
+			}
 
 			extTS := uint64(meta.timestamp) + (s.extHighestTS & 0xFFFF_FFFF_0000_0000)
 			if meta.timestamp > highestTS {
@@ -354,9 +505,234 @@ func (s *sequencer) getExtPacketMetas(seqNo []uint16) []extPacketMeta {
 		}
 	}
 
+	// retransmit packets in order of usefulness to the receiver,
+	// key frame packets first as nothing can be decoded without them and
+	// frame ending (i. e. marker) packets last
+	sort.SliceStable(extPacketMetas, func(i, j int) bool {
+		return getRetransmitPriority(&extPacketMetas[i].packetMeta) < getRetransmitPriority(&extPacketMetas[j].packetMeta)
+	})
 	return extPacketMetas
 }
 
+// FlushNackQueue defers retransmission of all cached packets by at least an RTT from now.
+func (s *sequencer) FlushNackQueue() {
+	s.Lock()
+	defer s.Unlock()
+
+	s.flushNackQueue(s.getRefTime(time.Now()), nil)
+}
+
+func (s *sequencer) flushNackQueue(refTime uint32, skip func(pm *packetMeta) bool) {
+	for slot := range s.meta {
+		if s.isInvalidSlot(slot) || (skip != nil && skip(&s.meta[slot])) {
+			continue
+		}
+
+		s.meta[slot].lastNack = refTime
+	}
+}
+
+// GetCacheOccupancy returns the fraction of slots in the ring buffer holding a valid packet.
+func (s *sequencer) GetCacheOccupancy() float32 {
+	s.Lock()
+	defer s.Unlock()
+
+	return s.getCacheOccupancy()
+}
+
+func (s *sequencer) getCacheOccupancy() float32 {
+	if s.size == 0 {
+		return 0
+	}
+
+	return float32(s.numOccupied) / float32(s.size)
+}
+
+// GetDiagnostics returns drop counters and ring buffer state of the sequencer.
+func (s *sequencer) GetDiagnostics() SequencerDiagnostics {
+	s.Lock()
+	defer s.Unlock()
+
+	diagnostics := SequencerDiagnostics{
+		DroppedOldPackets: s.numDroppedOld,
+		DroppedDuplicates: s.numDroppedDuplicates,
+		CacheOccupancy:    s.getCacheOccupancy(),
+	}
+	if !s.initialized || s.size == 0 {
+		return diagnostics
+	}
+
+	diagnostics.HeadSN = uint16(s.extHighestSN)
+	diagnostics.OldestCachedSN = diagnostics.HeadSN
+
+	// oldest valid slot is the first one after the head going around the ring
+	headSlot := int((s.extHighestSN - s.snOffset) % uint64(s.size))
+	for i := 1; i <= s.size; i++ {
+		slot := (headSlot + i) % s.size
+		if !s.isInvalidSlot(slot) {
+			diagnostics.OldestCachedSN = s.meta[slot].targetSeqNo
+			break
+		}
+	}
+	return diagnostics
+}
+
+// Dump returns a summary of every cached packet in sequence number order, oldest first.
+func (s *sequencer) Dump() []PacketMetaSummary {
+	s.Lock()
+	defer s.Unlock()
+
+	if !s.initialized || s.size == 0 {
+		return nil
+	}
+
+	refTime := s.getRefTime(time.Now())
+	summaries := make([]PacketMetaSummary, 0, s.numOccupied)
+	// going around the ring starting after the head visits slots in sequence number order
+	headSlot := int((s.extHighestSN - s.snOffset) % uint64(s.size))
+	for i := 1; i <= s.size; i++ {
+		slot := (headSlot + i) % s.size
+		if s.isInvalidSlot(slot) {
+			continue
+		}
+
+		pm := &s.meta[slot]
+		summaries = append(summaries, PacketMetaSummary{
+			TargetSN:  pm.targetSeqNo,
+			SourceSN:  pm.sourceSeqNo,
+			Timestamp: pm.timestamp,
+			Layer:     pm.layer,
+			NACKed:    pm.nacked,
+			AgeMs:     refTime - pm.pushedAt,
+		})
+	}
+	return summaries
+}
+
+// setRetransmissionTimeout sets the minimum interval between retransmissions of a packet,
+// a non-positive timeout restores the default.
+func (s *sequencer) setRetransmissionTimeout(timeout time.Duration) {
+	s.Lock()
+	defer s.Unlock()
+
+	if timeout <= 0 {
+		s.retransmissionTimeout = defaultRetransmissionTimeout
+	} else {
+		s.retransmissionTimeout = timeout
+	}
+}
+
+// setMaxNACKBatchSize sets the maximum number of sequence numbers looked up per NACK,
+// a non-positive size restores the default.
+func (s *sequencer) setMaxNACKBatchSize(size int) {
+	s.Lock()
+	defer s.Unlock()
+
+	if size <= 0 {
+		s.maxNACKBatchSize = defaultMaxNACKBatchSize
+	} else {
+		s.maxNACKBatchSize = size
+	}
+}
+
+func (s *sequencer) updateOccupancy(wasInvalid bool, isInvalid bool) {
+	switch {
+	case wasInvalid && !isInvalid:
+		s.numOccupied++
+	case !wasInvalid && isInvalid:
+		s.numOccupied--
+	}
+}
+
+func (s *sequencer) getRetransmissionIntervalLocked() uint32 {
+	return uint32(math.Min(float64(s.retransmissionTimeout.Milliseconds()), float64(2*s.rtt)))
+}
+
+// a packet evicted before it could have used all its retransmission attempts
+// indicates that the cache is too small for the packet rate
+func (s *sequencer) checkEviction(pm *packetMeta, refTime uint32) {
+	retentionWindow := uint32(s.maxAck) * s.getRetransmissionIntervalLocked()
+	age := refTime - pm.pushedAt
+	if age >= retentionWindow {
+		return
+	}
+
+	s.numPrematureEvictions++
+	if !s.isPrematureEvictionAlert {
+		s.isPrematureEvictionAlert = true
+		s.logger.Infow(
+			"sequencer evicting packets before retransmission window ends, consider increasing cache size",
+			"size", s.size,
+			"age", age,
+			"retentionWindow", retentionWindow,
+		)
+	}
+}
+
+func (s *sequencer) markKeyFrame(slot int, timestamp uint32) {
+	for i := 1; i < s.size; i++ {
+		prev := (slot - i + s.size) % s.size
+		if s.isInvalidSlot(prev) {
+			continue
+		}
+
+		pm := &s.meta[prev]
+		if pm.timestamp != timestamp {
+			break
+		}
+		pm.isKeyFrame = true
+	}
+}
+
+func getRetransmitPriority(pm *packetMeta) int {
+	switch {
+	case pm.nalType == h264NALTypeIDR:
+		return 0
+	case pm.isKeyFrame:
+		return 1
+	case !pm.marker:
+		return 2
+	default:
+		return 3
+	}
+}
+
+// getH264NALType returns the type of the NAL unit carried in an H.264 RTP payload.
+// For FU-A, the type of the fragmented NAL unit is returned and for STAP-A,
+// IDR is returned if any of the aggregated NAL units is an IDR, else the type of the first one.
+func getH264NALType(payload []byte) uint8 {
+	if len(payload) == 0 {
+		return 0
+	}
+
+	nalType := payload[0] & 0x1F
+	switch nalType {
+	case h264NALTypeFUA:
+		if len(payload) < 2 {
+			return 0
+		}
+		return payload[1] & 0x1F
+
+	case h264NALTypeSTAPA:
+		first := uint8(0)
+		for offset := 1; offset+2 < len(payload); {
+			size := int(payload[offset])<<8 | int(payload[offset+1])
+			offset += 2
+			aggregatedType := payload[offset] & 0x1F
+			if aggregatedType == h264NALTypeIDR {
+				return h264NALTypeIDR
+			}
+			if first == 0 {
+				first = aggregatedType
+			}
+			offset += size
+		}
+		return first
+	}
+
+	return nalType
+}
+
 func (s *sequencer) getRefTime(at time.Time) uint32 {
 	return uint32(at.UnixMilli() - s.startTime)
 }
@@ -379,11 +755,17 @@ func (s *sequencer) invalidateSlot(slot int) {
 		return
 	}
 
+	wasInvalid := s.isInvalidSlot(slot)
 	s.meta[slot] = packetMeta{
 		sourceSeqNo: 0,
 		targetSeqNo: 0,
 		lastNack:    0,
 	}
+	s.updateOccupancy(wasInvalid, true)
+}
+
+func (s *sequencer) slotHolds(slot int, targetSeqNo uint16) bool {
+	return !s.isInvalidSlot(slot) && s.meta[slot].targetSeqNo == targetSeqNo
 }
 
 func (s *sequencer) isInvalidSlot(slot int) bool {
@@ -25,22 +25,22 @@ import (
 )
 
 func Test_sequencer(t *testing.T) {
-	seq := newSequencer(500, false, logger.GetLogger())
+	seq := newSequencer(500, false, 0, logger.GetLogger())
 	off := uint16(15)
 
 	for i := uint64(1); i < 518; i++ {
-		seq.push(time.Now(), i, i+uint64(off), 123, true, 2, nil, 0, nil, nil)
+		seq.push(time.Now(), i, i+uint64(off), 123, true, false, 2, nil, 0, nil, nil, nil)
 	}
 	// send the last two out-of-order
-	seq.push(time.Now(), 519, 519+uint64(off), 123, false, 2, nil, 0, nil, nil)
-	seq.push(time.Now(), 518, 518+uint64(off), 123, true, 2, nil, 0, nil, nil)
+	seq.push(time.Now(), 519, 519+uint64(off), 123, false, false, 2, nil, 0, nil, nil, nil)
+	seq.push(time.Now(), 518, 518+uint64(off), 123, true, false, 2, nil, 0, nil, nil, nil)
 
 	req := []uint16{57, 58, 62, 63, 513, 514, 515, 516, 517}
 	res := seq.getExtPacketMetas(req)
 	// nothing should be returned as not enough time has elapsed since sending packet
 	require.Equal(t, 0, len(res))
 
-	time.Sleep((ignoreRetransmission + 10) * time.Millisecond)
+	time.Sleep(defaultRetransmissionTimeout + 10*time.Millisecond)
 	res = seq.getExtPacketMetas(req)
 	require.Equal(t, len(req), len(res))
 	for i, val := range res {
@@ -52,7 +52,7 @@ func Test_sequencer(t *testing.T) {
 	}
 	res = seq.getExtPacketMetas(req)
 	require.Equal(t, 0, len(res))
-	time.Sleep((ignoreRetransmission + 10) * time.Millisecond)
+	time.Sleep(defaultRetransmissionTimeout + 10*time.Millisecond)
 	res = seq.getExtPacketMetas(req)
 	require.Equal(t, len(req), len(res))
 	for i, val := range res {
@@ -63,17 +63,17 @@ func Test_sequencer(t *testing.T) {
 		require.Equal(t, val.extTimestamp, uint64(123))
 	}
 
-	seq.push(time.Now(), 521, 521+uint64(off), 123, true, 1, nil, 0, nil, nil)
+	seq.push(time.Now(), 521, 521+uint64(off), 123, true, false, 1, nil, 0, nil, nil, nil)
 	m := seq.getExtPacketMetas([]uint16{521 + off})
 	require.Equal(t, 0, len(m))
-	time.Sleep((ignoreRetransmission + 10) * time.Millisecond)
+	time.Sleep(defaultRetransmissionTimeout + 10*time.Millisecond)
 	m = seq.getExtPacketMetas([]uint16{521 + off})
 	require.Equal(t, 1, len(m))
 
-	seq.push(time.Now(), 505, 505+uint64(off), 123, false, 1, nil, 0, nil, nil)
+	seq.push(time.Now(), 505, 505+uint64(off), 123, false, false, 1, nil, 0, nil, nil, nil)
 	m = seq.getExtPacketMetas([]uint16{505 + off})
 	require.Equal(t, 0, len(m))
-	time.Sleep((ignoreRetransmission + 10) * time.Millisecond)
+	time.Sleep(defaultRetransmissionTimeout + 10*time.Millisecond)
 	m = seq.getExtPacketMetas([]uint16{505 + off})
 	require.Equal(t, 1, len(m))
 }
@@ -142,14 +142,15 @@ func Test_sequencer_getNACKSeqNo_exclusion(t *testing.T) {
 			},
 			// although 65526 is originally pushed, that would have been reset by 65532 (padding only packet)
 			// because of trying to add an exclusion range before highest sequence number which will fail
-			// and the resulting fix up of the exclusion range slots
-			want: []uint16{65530, 65533, 65534},
+			// and the resulting fix up of the exclusion range slots,
+			// marker packets are retransmitted last
+			want: []uint16{65534, 65530, 65533},
 		},
 	}
 	for _, tt := range tests {
 		tt := tt
 		t.Run(tt.name, func(t *testing.T) {
-			n := newSequencer(5, true, logger.GetLogger())
+			n := newSequencer(5, true, 0, logger.GetLogger())
 
 			for _, i := range tt.fields.inputs {
 				if i.isPadding {
@@ -162,11 +163,13 @@ func Test_sequencer_getNACKSeqNo_exclusion(t *testing.T) {
 							i.seqNo+tt.fields.offset,
 							123,
 							tt.fields.markerOdd,
+							false,
 							3,
 							tt.fields.codecBytesOversized,
 							len(tt.fields.codecBytesOversized),
 							tt.fields.ddBytesOversized,
 							tt.fields.actBytesOdd,
+							nil,
 						)
 					} else {
 						if i.seqNo%2 == 0 {
@@ -176,11 +179,13 @@ func Test_sequencer_getNACKSeqNo_exclusion(t *testing.T) {
 								i.seqNo+tt.fields.offset,
 								123,
 								tt.fields.markerEven,
+								false,
 								3,
 								tt.fields.codecBytesEven,
 								tt.fields.numCodecBytesInEven,
 								tt.fields.ddBytesEven,
 								tt.fields.actBytesEven,
+								nil,
 							)
 						} else {
 							n.push(
@@ -189,18 +194,20 @@ func Test_sequencer_getNACKSeqNo_exclusion(t *testing.T) {
 								i.seqNo+tt.fields.offset,
 								123,
 								tt.fields.markerOdd,
+								false,
 								3,
 								tt.fields.codecBytesOdd,
 								tt.fields.numCodecBytesInOdd,
 								tt.fields.ddBytesOdd,
 								tt.fields.actBytesOdd,
+								nil,
 							)
 						}
 					}
 				}
 			}
 
-			time.Sleep((ignoreRetransmission + 10) * time.Millisecond)
+			time.Sleep(defaultRetransmissionTimeout + 10*time.Millisecond)
 			g := n.getExtPacketMetas(tt.args.seqNo)
 			var got []uint16
 			for _, sn := range g {
@@ -296,14 +303,15 @@ func Test_sequencer_getNACKSeqNo_no_exclusion(t *testing.T) {
 			args: args{
 				seqNo: []uint16{4 + 5, 5 + 5, 8 + 5, 9 + 5, 10 + 5, 11 + 5, 12 + 5},
 			},
-			// although 4 and 8 were originally added, they would be too old after a cycle of sequencer buffer
-			want: []uint16{11, 12},
+			// although 4 and 8 were originally added, they would be too old after a cycle of sequencer buffer,
+			// marker packets are retransmitted last
+			want: []uint16{12, 11},
 		},
 	}
 	for _, tt := range tests {
 		tt := tt
 		t.Run(tt.name, func(t *testing.T) {
-			n := newSequencer(5, false, logger.GetLogger())
+			n := newSequencer(5, false, 0, logger.GetLogger())
 
 			for _, i := range tt.fields.inputs {
 				if i.isPadding {
@@ -316,11 +324,13 @@ func Test_sequencer_getNACKSeqNo_no_exclusion(t *testing.T) {
 							i.seqNo+tt.fields.offset,
 							123,
 							tt.fields.markerEven,
+							false,
 							3,
 							tt.fields.codecBytesEven,
 							tt.fields.numCodecBytesInEven,
 							tt.fields.ddBytesEven,
 							tt.fields.actBytesEven,
+							nil,
 						)
 					} else {
 						n.push(
@@ -329,17 +339,19 @@ func Test_sequencer_getNACKSeqNo_no_exclusion(t *testing.T) {
 							i.seqNo+tt.fields.offset,
 							123,
 							tt.fields.markerOdd,
+							false,
 							3,
 							tt.fields.codecBytesOdd,
 							tt.fields.numCodecBytesInOdd,
 							tt.fields.ddBytesOdd,
 							tt.fields.actBytesOdd,
+							nil,
 						)
 					}
 				}
 			}
 
-			time.Sleep((ignoreRetransmission + 10) * time.Millisecond)
+			time.Sleep(defaultRetransmissionTimeout + 10*time.Millisecond)
 			g := n.getExtPacketMetas(tt.args.seqNo)
 			var got []uint16
 			for _, sn := range g {
@@ -366,3 +378,299 @@ func Test_sequencer_getNACKSeqNo_no_exclusion(t *testing.T) {
 		})
 	}
 }
+
+func Test_sequencer_cacheOccupancy(t *testing.T) {
+	seq := newSequencer(10, false, 0, logger.GetLogger())
+	require.Equal(t, float32(0), seq.GetCacheOccupancy())
+
+	now := time.Now()
+	for i := uint64(1); i <= 5; i++ {
+		seq.push(now, i, i, 123, true, false, 0, nil, 0, nil, nil, nil)
+	}
+	require.Equal(t, float32(0.5), seq.GetCacheOccupancy())
+
+	// a gap invalidates the missing slots
+	seq.push(now, 9, 9, 123, true, false, 0, nil, 0, nil, nil, nil)
+	require.Equal(t, float32(0.6), seq.GetCacheOccupancy())
+
+	// wrapping around the ring fills it up, packets spaced out in time are not evicted prematurely
+	for i := uint64(10); i < 30; i++ {
+		seq.push(now.Add(time.Duration(i)*time.Second), i, i, 123, true, false, 0, nil, 0, nil, nil, nil)
+	}
+	require.Equal(t, float32(1.0), seq.GetCacheOccupancy())
+	require.Zero(t, seq.numPrematureEvictions)
+}
+
+func Test_sequencer_diagnostics(t *testing.T) {
+	seq := newSequencer(10, false, 0, logger.GetLogger())
+	require.Equal(t, SequencerDiagnostics{}, seq.GetDiagnostics())
+
+	now := time.Now()
+	for i := uint64(1); i <= 5; i++ {
+		seq.push(now, i, i, 123, true, false, 0, nil, 0, nil, nil, nil)
+	}
+	require.Equal(t, SequencerDiagnostics{HeadSN: 5, OldestCachedSN: 1, CacheOccupancy: 0.5}, seq.GetDiagnostics())
+
+	seq.push(now, 3, 3, 123, true, false, 0, nil, 0, nil, nil, nil)
+	require.EqualValues(t, 1, seq.GetDiagnostics().DroppedDuplicates)
+
+	// a large gap invalidates all slots
+	seq.push(now, 20, 20, 123, true, false, 0, nil, 0, nil, nil, nil)
+	require.Equal(t, SequencerDiagnostics{DroppedDuplicates: 1, HeadSN: 20, OldestCachedSN: 20, CacheOccupancy: 0.1}, seq.GetDiagnostics())
+
+	// too far behind head and before start
+	seq.push(now, 9, 9, 123, true, false, 0, nil, 0, nil, nil, nil)
+	seq.push(now, 0, 0, 123, true, false, 0, nil, 0, nil, nil, nil)
+	require.EqualValues(t, 2, seq.GetDiagnostics().DroppedOldPackets)
+}
+
+func Test_sequencer_dump(t *testing.T) {
+	seq := newSequencer(10, false, 0, logger.GetLogger())
+	require.Nil(t, seq.Dump())
+
+	// wraps around the sequence number space
+	now := time.Now()
+	for i := uint64(65530); i < 65538; i++ {
+		if i == 65534 {
+			continue
+		}
+		seq.push(now.Add(-time.Duration(65538-i)*time.Second), i-10, i, i*10, true, false, 1, nil, 0, nil, nil, nil)
+	}
+	seq.maxAck = 5
+	seq.getExtPacketMetas([]uint16{65533})
+
+	dump := seq.Dump()
+	require.Len(t, dump, 7)
+	require.Equal(t, []uint16{65530, 65531, 65532, 65533, 65535, 0, 1}, func() []uint16 {
+		var sns []uint16
+		for _, pms := range dump {
+			sns = append(sns, pms.TargetSN)
+		}
+		return sns
+	}())
+	require.Equal(t, uint16(65520), dump[0].SourceSN)
+	require.Equal(t, uint32(655300), dump[0].Timestamp)
+	require.Equal(t, int8(1), dump[0].Layer)
+	require.Equal(t, uint8(1), dump[3].NACKed)
+	require.Zero(t, dump[4].NACKed)
+	require.InDelta(t, 8000, dump[0].AgeMs, 100)
+	require.InDelta(t, 1000, dump[6].AgeMs, 100)
+}
+
+func Test_sequencer_prematureEviction(t *testing.T) {
+	seq := newSequencer(5, false, 0, logger.GetLogger())
+
+	// packets arriving faster than the cache can hold for the retransmission window
+	now := time.Now()
+	for i := uint64(1); i <= 12; i++ {
+		seq.push(now, i, i, 123, true, false, 0, nil, 0, nil, nil, nil)
+	}
+	require.Equal(t, uint64(7), seq.numPrematureEvictions)
+	require.True(t, seq.isPrematureEvictionAlert)
+}
+
+func Test_sequencer_maxAck(t *testing.T) {
+	seq := newSequencer(10, false, 5, logger.GetLogger())
+	seq.setRTT(1)
+	seq.push(time.Now(), 1, 1, 123, true, false, 0, nil, 0, nil, nil, nil)
+
+	for i := 0; i < 5; i++ {
+		time.Sleep(5 * time.Millisecond)
+		require.Equal(t, 1, len(seq.getExtPacketMetas([]uint16{1})))
+	}
+	time.Sleep(5 * time.Millisecond)
+	require.Equal(t, 0, len(seq.getExtPacketMetas([]uint16{1})))
+}
+
+func Test_sequencer_flushNackQueue(t *testing.T) {
+	seq := newSequencer(10, false, 0, logger.GetLogger())
+	seq.setRTT(10)
+	for i := uint64(1); i <= 3; i++ {
+		seq.push(time.Now(), i, i, 123, true, false, 0, nil, 0, nil, nil, nil)
+	}
+
+	time.Sleep(30 * time.Millisecond)
+	seq.FlushNackQueue()
+	require.Equal(t, 0, len(seq.getExtPacketMetas([]uint16{1, 2, 3})))
+	require.Equal(t, float32(0.3), seq.GetCacheOccupancy())
+
+	time.Sleep(30 * time.Millisecond)
+	require.Equal(t, 3, len(seq.getExtPacketMetas([]uint16{1, 2, 3})))
+}
+
+func Test_sequencer_flushNackQueue_layerSwitch(t *testing.T) {
+	seq := newSequencer(20, false, 0, logger.GetLogger())
+	past := time.Now().Add(-time.Second)
+
+	// layer 0 frames
+	seq.push(past, 1, 1, 100, false, false, 0, nil, 0, nil, nil, nil)
+	seq.push(past, 2, 2, 100, true, false, 0, nil, 0, nil, nil, nil)
+	seq.push(past, 3, 3, 200, true, false, 0, nil, 0, nil, nil, nil)
+
+	// multi-packet key frame of layer 1, layer switch is known only at the frame end
+	seq.push(past, 4, 4, 300, false, true, 1, nil, 0, nil, nil, nil)
+	seq.push(past, 5, 5, 300, false, true, 1, nil, 0, nil, nil, nil)
+	require.Equal(t, 5, len(seq.PeekPacketsMeta([]uint16{1, 2, 3, 4, 5})))
+	seq.push(past, 6, 6, 300, true, true, 1, nil, 0, nil, nil, nil)
+
+	// packets before the switch are flushed, packets of the key frame are not
+	var got []uint16
+	for _, epm := range seq.PeekPacketsMeta([]uint16{1, 2, 3, 4, 5, 6}) {
+		got = append(got, epm.targetSeqNo)
+	}
+	require.Equal(t, []uint16{4, 5, 6}, got)
+}
+
+func Test_sequencer_flushNackQueue_svc(t *testing.T) {
+	seq := newSequencer(20, false, 0, logger.GetLogger())
+	past := time.Now().Add(-time.Second)
+
+	// S0 and S1 interleaved, S1 frames are single packet, should not be treated as layer switches
+	for i := uint64(0); i < 5; i++ {
+		seq.push(past, 2*i+1, 2*i+1, 100*(i+1), false, false, 0, nil, 0, nil, nil, nil)
+		seq.push(past, 2*i+2, 2*i+2, 100*(i+1), true, false, 1, nil, 0, nil, nil, nil)
+	}
+	require.Equal(t, 10, len(seq.PeekPacketsMeta([]uint16{1, 2, 3, 4, 5, 6, 7, 8, 9, 10})))
+}
+
+func Test_sequencer_peekPacketsMeta(t *testing.T) {
+	seq := newSequencer(10, false, 0, logger.GetLogger())
+	for i := uint64(1); i <= 3; i++ {
+		seq.push(time.Now(), i, i, 123, true, false, 0, nil, 0, nil, nil, nil)
+	}
+	time.Sleep(defaultRetransmissionTimeout + 10*time.Millisecond)
+
+	for i := 0; i < defaultMaxAck+1; i++ {
+		res := seq.PeekPacketsMeta([]uint16{1, 2, 3})
+		require.Equal(t, 3, len(res))
+		for _, epm := range res {
+			require.Equal(t, uint8(0), epm.nacked)
+		}
+	}
+
+	res := seq.getExtPacketMetas([]uint16{1, 2, 3})
+	require.Equal(t, 3, len(res))
+	require.Equal(t, 0, len(seq.PeekPacketsMeta([]uint16{1, 2, 3})))
+}
+
+func Test_sequencer_retransmitPriority(t *testing.T) {
+	seq := newSequencer(10, false, 0, logger.GetLogger())
+	seq.push(time.Now(), 1, 1, 123, false, false, 0, nil, 0, nil, nil, nil)
+	seq.push(time.Now(), 2, 2, 123, true, false, 0, nil, 0, nil, nil, nil)
+	seq.push(time.Now(), 3, 3, 456, false, true, 0, nil, 0, nil, nil, nil)
+	seq.push(time.Now(), 4, 4, 456, true, true, 0, nil, 0, nil, nil, nil)
+	seq.push(time.Now(), 5, 5, 789, false, false, 0, nil, 0, nil, nil, nil)
+	time.Sleep(defaultRetransmissionTimeout + 10*time.Millisecond)
+
+	var got []uint16
+	for _, epm := range seq.getExtPacketMetas([]uint16{1, 2, 3, 4, 5}) {
+		got = append(got, epm.targetSeqNo)
+	}
+	require.Equal(t, []uint16{3, 4, 1, 5, 2}, got)
+}
+
+func Test_sequencer_retransmissionTimeout(t *testing.T) {
+	seq := newSequencer(10, false, 0, logger.GetLogger())
+	seq.setRetransmissionTimeout(30 * time.Millisecond)
+
+	seq.push(time.Now(), 1, 1, 100, true, false, 0, nil, 0, nil, nil, nil)
+	require.Empty(t, seq.getExtPacketMetas([]uint16{1}))
+
+	time.Sleep(40 * time.Millisecond)
+	require.Len(t, seq.getExtPacketMetas([]uint16{1}), 1)
+	require.Empty(t, seq.getExtPacketMetas([]uint16{1}))
+
+	time.Sleep(40 * time.Millisecond)
+	require.Len(t, seq.getExtPacketMetas([]uint16{1}), 1)
+
+	// capped at 2 * RTT
+	seq.setRetransmissionTimeout(time.Second)
+	seq.setRTT(20)
+	time.Sleep(50 * time.Millisecond)
+	require.Len(t, seq.getExtPacketMetas([]uint16{1}), 1)
+
+	seq.setRetransmissionTimeout(0)
+	require.Equal(t, defaultRetransmissionTimeout, seq.retransmissionTimeout)
+}
+
+func Test_sequencer_maxNACKBatchSize(t *testing.T) {
+	seq := newSequencer(500, false, 0, logger.GetLogger())
+	past := time.Now().Add(-time.Second)
+	for i := uint64(1); i <= 200; i++ {
+		seq.push(past, i, i, 123, true, false, 0, nil, 0, nil, nil, nil)
+	}
+
+	req := make([]uint16, 0, 150)
+	for sn := uint16(1); sn <= 150; sn++ {
+		req = append(req, sn)
+	}
+	res := seq.getExtPacketMetas(req)
+	require.Len(t, res, defaultMaxNACKBatchSize)
+	require.Equal(t, uint16(100), res[len(res)-1].targetSeqNo)
+
+	seq.setMaxNACKBatchSize(10)
+	require.Len(t, seq.PeekPacketsMeta(req[100:]), 10)
+
+	seq.setMaxNACKBatchSize(0)
+	require.Len(t, seq.PeekPacketsMeta(req[100:]), 50)
+}
+
+func Test_sequencer_retransmitPriority_multiPacketKeyFrame(t *testing.T) {
+	seq := newSequencer(20, false, 0, logger.GetLogger())
+	past := time.Now().Add(-time.Second)
+
+	// key frame indication is available only on the first packet of the frame,
+	// which arrives after the second packet of the frame
+	seq.push(past, 1, 1, 200, true, false, 0, nil, 0, nil, nil, nil)
+	seq.push(past, 3, 3, 300, false, false, 0, nil, 0, nil, nil, nil)
+	seq.push(past, 2, 2, 300, false, true, 0, nil, 0, nil, nil, nil)
+	seq.push(past, 4, 4, 300, true, false, 0, nil, 0, nil, nil, nil)
+	seq.push(past, 5, 5, 400, false, false, 0, nil, 0, nil, nil, nil)
+	seq.push(past, 6, 6, 400, true, false, 0, nil, 0, nil, nil, nil)
+
+	var got []uint16
+	for _, epm := range seq.getExtPacketMetas([]uint16{6, 5, 4, 3, 2, 1}) {
+		got = append(got, epm.targetSeqNo)
+	}
+	require.Equal(t, []uint16{4, 3, 2, 5, 6, 1}, got)
+}
+
+func Test_sequencer_getH264NALType(t *testing.T) {
+	require.Equal(t, uint8(0), getH264NALType(nil))
+	// single NAL unit
+	require.Equal(t, uint8(5), getH264NALType([]byte{0x65, 0x88}))
+	require.Equal(t, uint8(1), getH264NALType([]byte{0x41, 0x9a}))
+	// FU-A carrying IDR
+	require.Equal(t, uint8(5), getH264NALType([]byte{0x7c, 0x85, 0x88}))
+	// STAP-A with SPS, PPS and IDR
+	require.Equal(t, uint8(5), getH264NALType([]byte{0x78, 0x00, 0x02, 0x67, 0x42, 0x00, 0x02, 0x68, 0xce, 0x00, 0x02, 0x65, 0x88}))
+	// STAP-A with SPS and PPS
+	require.Equal(t, uint8(7), getH264NALType([]byte{0x78, 0x00, 0x02, 0x67, 0x42, 0x00, 0x02, 0x68, 0xce}))
+}
+
+func Test_sequencer_retransmitPriority_h264IDR(t *testing.T) {
+	seq := newSequencer(10, false, 0, logger.GetLogger())
+	seq.setH264(true)
+	past := time.Now().Add(-time.Second)
+
+	// SPS/PPS flagged as key frame, followed by an IDR slice fragmented with FU-A
+	seq.push(past, 1, 1, 100, false, false, 0, nil, 0, nil, nil, []byte{0x41, 0x9a})
+	seq.push(past, 2, 2, 100, true, false, 0, nil, 0, nil, nil, []byte{0x41, 0x9a})
+	seq.push(past, 3, 3, 200, false, true, 0, nil, 0, nil, nil, []byte{0x78, 0x00, 0x02, 0x67, 0x42, 0x00, 0x02, 0x68, 0xce})
+	seq.push(past, 4, 4, 200, false, true, 0, nil, 0, nil, nil, []byte{0x7c, 0x85, 0x88})
+	seq.push(past, 5, 5, 200, true, true, 0, nil, 0, nil, nil, []byte{0x7c, 0x45, 0x88})
+
+	var got []uint16
+	var nalTypes []uint8
+	for _, epm := range seq.getExtPacketMetas([]uint16{1, 2, 3, 4, 5}) {
+		got = append(got, epm.targetSeqNo)
+		nalTypes = append(nalTypes, epm.nalType)
+	}
+	require.Equal(t, []uint16{4, 5, 3, 1, 2}, got)
+	require.Equal(t, []uint8{5, 5, 7, 1, 1}, nalTypes)
+
+	// NAL type is not parsed for other codecs
+	seq = newSequencer(10, false, 0, logger.GetLogger())
+	seq.push(past, 1, 1, 100, true, false, 0, nil, 0, nil, nil, []byte{0x65, 0x88})
+	require.Equal(t, uint8(0), seq.getExtPacketMetas([]uint16{1})[0].nalType)
+}
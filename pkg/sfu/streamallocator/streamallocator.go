@@ -37,6 +37,8 @@ import (
 const (
 	ChannelCapacityInfinity = 100 * 1000 * 1000 // 100 Mbps
 
+	bandwidthEstimateUpdateInterval = time.Second
+
 	PriorityMin                = uint8(1)
 	PriorityMax                = uint8(255)
 	PriorityDefaultScreenshare = PriorityMax
@@ -155,6 +157,8 @@ type StreamAllocator struct {
 	committedChannelCapacity  int64
 	overriddenChannelCapacity int64
 
+	lastBandwidthEstimateUpdate time.Time
+
 	probeController *ProbeController
 
 	prober *Prober
@@ -666,9 +670,23 @@ func (s *StreamAllocator) handleSignalPeriodicPing(Event) {
 		s.maybeProbe()
 	}
 
+	s.maybeUpdateTracksBandwidthEstimate()
+
 	// s.updateTracksHistory()
 }
 
+// feed committed channel capacity to tracks for accounting of bandwidth used by each track
+func (s *StreamAllocator) maybeUpdateTracksBandwidthEstimate() {
+	if s.committedChannelCapacity <= 0 || time.Since(s.lastBandwidthEstimateUpdate) < bandwidthEstimateUpdateInterval {
+		return
+	}
+
+	s.lastBandwidthEstimateUpdate = time.Now()
+	for _, track := range s.getTracks() {
+		track.UpdateBandwidthEstimate(s.committedChannelCapacity)
+	}
+}
+
 func (s *StreamAllocator) handleSignalSendProbe(event Event) {
 	bytesToSend := event.Data.(int)
 	if bytesToSend <= 0 {
@@ -130,6 +130,10 @@ func (t *Track) DownTrack() *sfu.DownTrack {
 }
 
 func (t *Track) IsManaged() bool {
+	if t.downTrack.GetBandwidthOverride() > 0 {
+		// bandwidth granted out-of-band, not subject to channel estimate
+		return false
+	}
 	return t.source != livekit.TrackSource_SCREEN_SHARE || t.isSimulcast
 }
 
@@ -154,6 +158,10 @@ func (t *Track) WritePaddingRTP(bytesToSend int) int {
 	return t.downTrack.WritePaddingRTP(bytesToSend, false, false)
 }
 
+func (t *Track) UpdateBandwidthEstimate(estimatedBitrate int64) {
+	t.downTrack.UpdateBandwidthEstimate(estimatedBitrate)
+}
+
 func (t *Track) AllocateOptimal(allowOvershoot bool) sfu.VideoAllocation {
 	return t.downTrack.AllocateOptimal(allowOvershoot)
 }
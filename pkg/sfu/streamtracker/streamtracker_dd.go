@@ -39,6 +39,9 @@ type StreamTrackerDependencyDescriptor struct {
 	bytesForBitrate   [buffer.DefaultMaxLayerSpatial + 1][buffer.DefaultMaxLayerTemporal + 1]int64
 	bitrate           [buffer.DefaultMaxLayerSpatial + 1][buffer.DefaultMaxLayerTemporal + 1]int64
 
+	// latest dependency structure attached to a descriptor
+	structure *dd.FrameDependencyStructure
+
 	isStopped bool
 }
 
@@ -100,6 +103,14 @@ func (s *StreamTrackerDependencyDescriptor) BitrateTemporalCumulative(layer int3
 	return s.bitrate[layer][:]
 }
 
+// GetStructure returns the latest dependency structure seen, nil if none seen yet
+func (s *StreamTrackerDependencyDescriptor) GetStructure() *dd.FrameDependencyStructure {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	return s.structure
+}
+
 func (s *StreamTrackerDependencyDescriptor) Reset() {
 }
 
@@ -145,6 +156,10 @@ func (s *StreamTrackerDependencyDescriptor) Observe(temporalLayer int32, pktSize
 		return
 	}
 
+	if ddVal.StructureUpdated && ddVal.Descriptor.AttachedStructure != nil {
+		s.structure = ddVal.Descriptor.AttachedStructure
+	}
+
 	var notifyFns []func(status StreamStatus)
 	var notifyStatus StreamStatus
 	if mask := ddVal.Descriptor.ActiveDecodeTargetsBitmask; mask != nil && ddVal.ActiveDecodeTargetsUpdated {
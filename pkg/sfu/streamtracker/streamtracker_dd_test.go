@@ -96,3 +96,23 @@ func TestStreamTrackerDD(t *testing.T) {
 	ddTracker.Observe(0, 1000, 1000, false, 0, createDescriptorDependencyForTargets(1, 1))
 	checkStatues(t, statuses, StreamStatusActive, 1)
 }
+
+func TestStreamTrackerDD_Structure(t *testing.T) {
+	ddTracker := NewStreamTrackerDependencyDescriptor(StreamTrackerParams{
+		BitrateReportInterval: 1 * time.Second,
+		Logger:                logger.GetLogger(),
+	})
+	defer ddTracker.Stop()
+	require.Nil(t, ddTracker.GetStructure())
+
+	structure := &dd.FrameDependencyStructure{StructureId: 1}
+	ddVal := createDescriptorDependencyForTargets(1, 1)
+	ddVal.Descriptor.AttachedStructure = structure
+	ddVal.StructureUpdated = true
+	ddTracker.Observe(0, 1000, 1000, false, 0, ddVal)
+	require.Same(t, structure, ddTracker.GetStructure())
+
+	// kept till next structure update
+	ddTracker.Observe(0, 1000, 1000, false, 0, createDescriptorDependencyForTargets(1, 1))
+	require.Same(t, structure, ddTracker.GetStructure())
+}
@@ -19,6 +19,7 @@ import (
 	"time"
 
 	"github.com/livekit/livekit-server/pkg/config"
+	"github.com/livekit/livekit-server/pkg/sfu/utils"
 	"github.com/livekit/protocol/logger"
 )
 
@@ -95,12 +96,10 @@ func (s *StreamTrackerFrame) Observe(hasMarker bool, ts uint32) StreamStatusChan
 			s.newestTS = ts
 			s.numFrames = 1
 		} else {
-			diff := ts - s.oldestTS
-			if diff > (1 << 31) {
+			if utils.Distance(s.oldestTS, ts) < 0 {
 				s.oldestTS = ts
 			}
-			diff = ts - s.newestTS
-			if diff < (1 << 31) {
+			if utils.Distance(s.newestTS, ts) >= 0 {
 				s.newestTS = ts
 			}
 			s.numFrames++
@@ -15,6 +15,7 @@
 package sfu
 
 import (
+	"math"
 	"sort"
 	"sync"
 	"time"
@@ -28,9 +29,87 @@ import (
 
 	"github.com/livekit/livekit-server/pkg/config"
 	"github.com/livekit/livekit-server/pkg/sfu/buffer"
+	dd "github.com/livekit/livekit-server/pkg/sfu/rtpextension/dependencydescriptor"
 	"github.com/livekit/livekit-server/pkg/sfu/streamtracker"
 )
 
+const (
+	defaultBitrateReporterTickInterval = time.Second
+
+	estimatedSpatialLayerBitrateRatio  = 2.0
+	estimatedTemporalLayerBitrateRatio = 1.5
+
+	defaultLayerEventLogSize = 1000
+
+	// bitrate reports are kept for this long to calculate stable bitrates
+	stableBitrateHistoryDuration = time.Minute
+)
+
+const (
+	LayerEventAvailable               = "available"
+	LayerEventUnavailable             = "unavailable"
+	LayerEventMaxPublishedLayerChange = "max_published_layer_change"
+	LayerEventMaxAvailableLayerChange = "max_available_layer_change"
+)
+
+// ---------------------------------------------------
+
+// LayerEvent is a layer switch event, Layer is the spatial layer the event is about and
+// AvailableLayers are the spatial layers available after the event.
+type LayerEvent struct {
+	Time            time.Time
+	Layer           int32
+	Event           string
+	AvailableLayers []int32
+}
+
+// layerEventLog is a ring buffer of the most recent layer events,
+// it grows as events are added till it reaches its size and wraps around after that.
+type layerEventLog struct {
+	size   int
+	events []LayerEvent
+	next   int
+}
+
+func newLayerEventLog(size int) *layerEventLog {
+	if size <= 0 {
+		size = defaultLayerEventLogSize
+	}
+	return &layerEventLog{
+		size: size,
+	}
+}
+
+func (l *layerEventLog) add(layer int32, event string, availableLayers []int32) {
+	ev := LayerEvent{
+		Time:            time.Now(),
+		Layer:           layer,
+		Event:           event,
+		AvailableLayers: append([]int32{}, availableLayers...),
+	}
+	if len(l.events) < l.size {
+		l.events = append(l.events, ev)
+		return
+	}
+
+	l.events[l.next] = ev
+	l.next = (l.next + 1) % l.size
+}
+
+// get returns events from oldest to newest
+func (l *layerEventLog) get() []LayerEvent {
+	events := make([]LayerEvent, 0, len(l.events))
+	events = append(events, l.events[l.next:]...)
+	return append(events, l.events[:l.next]...)
+}
+
+// ---------------------------------------------------
+
+type bitrateSample struct {
+	at       time.Time
+	bitrates Bitrates
+}
+
 // ---------------------------------------------------
 
 type StreamTrackerManagerListener interface {
@@ -63,6 +142,11 @@ type StreamTrackerManager struct {
 	maxExpectedLayer int32
 	paused           bool
 
+	layerEventLog *layerEventLog
+
+	// bitrate reports of the last stableBitrateHistoryDuration, oldest first
+	bitrateHistory []bitrateSample
+
 	closed core.Fuse
 
 	listener StreamTrackerManagerListener
@@ -92,6 +176,7 @@ func NewStreamTrackerManager(
 	default:
 		s.trackerConfig = trackersConfig.Video
 	}
+	s.layerEventLog = newLayerEventLog(s.trackerConfig.LayerEventLogSize)
 
 	s.maxExpectedLayerFromTrackInfo()
 
@@ -231,6 +316,7 @@ func (s *StreamTrackerManager) AddTracker(layer int32) streamtracker.StreamTrack
 	notify := false
 	if layer > s.maxPublishedLayer {
 		s.maxPublishedLayer = layer
+		s.layerEventLog.add(layer, LayerEventMaxPublishedLayerChange, s.availableLayers)
 		notify = true
 	}
 	s.lock.Unlock()
@@ -291,6 +377,10 @@ func (s *StreamTrackerManager) GetTracker(layer int32) streamtracker.StreamTrack
 	return s.trackers[layer]
 }
 
+func (s *StreamTrackerManager) IsSVC() bool {
+	return s.isSVC
+}
+
 func (s *StreamTrackerManager) SetPaused(paused bool) {
 	s.lock.Lock()
 	s.paused = paused
@@ -402,6 +492,18 @@ func (s *StreamTrackerManager) GetMaxPublishedLayer() int32 {
 	return s.maxPublishedLayer
 }
 
+// GetDependencyStructure returns the latest dependency structure of a stream using dependency descriptor,
+// nil if the stream does not use dependency descriptor or no structure has been seen yet
+func (s *StreamTrackerManager) GetDependencyStructure() *dd.FrameDependencyStructure {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	if s.ddTracker == nil {
+		return nil
+	}
+	return s.ddTracker.GetStructure()
+}
+
 func (s *StreamTrackerManager) GetLayeredBitrate() ([]int32, Bitrates) {
 	s.lock.RLock()
 	defer s.lock.RUnlock()
@@ -409,6 +511,17 @@ func (s *StreamTrackerManager) GetLayeredBitrate() ([]int32, Bitrates) {
 	return s.getLayeredBitrateLocked()
 }
 
+// GetTemporalLayerBitrates returns the bitrates of a spatial layer indexed by temporal layer,
+// cumulative of lower temporal layers, nil for an invalid spatial layer.
+func (s *StreamTrackerManager) GetTemporalLayerBitrates(spatialLayer int32) []int64 {
+	if spatialLayer < 0 || spatialLayer > buffer.DefaultMaxLayerSpatial {
+		return nil
+	}
+
+	_, br := s.GetLayeredBitrate()
+	return append([]int64{}, br[spatialLayer][:]...)
+}
+
 func (s *StreamTrackerManager) getLayeredBitrateLocked() ([]int32, Bitrates) {
 	var br Bitrates
 
@@ -444,6 +557,167 @@ func (s *StreamTrackerManager) getLayeredBitrateLocked() ([]int32, Bitrates) {
 	return availableLayers, br
 }
 
+// GetStableLayeredBitrate returns the minimum bitrate of each layer over the last minStableDuration.
+// Layers which were not available in every bitrate report of that period have a bitrate of 0 and
+// only spatial layers which are available and have a stable bitrate are returned as available.
+// Returns no layers if bitrate reports do not go back minStableDuration, which is at most one minute.
+func (s *StreamTrackerManager) GetStableLayeredBitrate(minStableDuration time.Duration) ([]int32, Bitrates) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	var stable Bitrates
+
+	// include the last report before the period, it covers the start of the period
+	windowStart := time.Now().Add(-minStableDuration)
+	start := -1
+	for i := len(s.bitrateHistory) - 1; i >= 0; i-- {
+		if !s.bitrateHistory[i].at.After(windowStart) {
+			start = i
+			break
+		}
+	}
+	if start < 0 {
+		return nil, stable
+	}
+
+	samples := s.bitrateHistory[start:]
+	for i := range stable {
+		for j := range stable[i] {
+			stable[i][j] = samples[0].bitrates[i][j]
+			for _, sample := range samples[1:] {
+				stable[i][j] = min(stable[i][j], sample.bitrates[i][j])
+			}
+		}
+	}
+
+	var availableLayers []int32
+	for _, layer := range s.availableLayers {
+		if layer >= 0 && int(layer) < len(stable) && stable[layer][0] != 0 {
+			availableLayers = append(availableLayers, layer)
+		}
+	}
+	return availableLayers, stable
+}
+
+func (s *StreamTrackerManager) addBitrateSample(at time.Time, brs Bitrates) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.bitrateHistory = append(s.bitrateHistory, bitrateSample{at: at, bitrates: brs})
+
+	// keep one report older than history duration to cover the start of the longest period
+	expired := 0
+	for expired < len(s.bitrateHistory)-1 && !s.bitrateHistory[expired+1].at.After(at.Add(-stableBitrateHistoryDuration)) {
+		expired++
+	}
+	if expired != 0 {
+		s.bitrateHistory = append(s.bitrateHistory[:0], s.bitrateHistory[expired:]...)
+	}
+}
+
+// GetEstimatedBitrate returns the measured bitrate of a layer. If the layer has not been measured,
+// the bitrate is estimated from the closest measured layer, preferring layers with the same spatial layer,
+// assuming each spatial layer doubles the bitrate of the one below (power-of-2 ratios as in VP9 SVC)
+// and each temporal layer adds half the bitrate of the ones below (frame rate doubles, but upper
+// temporal layer frames are cheaper). Returns 0 if no layer has been measured or if the spatial layer
+// is not published.
+func (s *StreamTrackerManager) GetEstimatedBitrate(spatial, temporal int32) int64 {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	if !s.isPublishedLayerLocked(spatial) {
+		return 0
+	}
+
+	_, br := s.getLayeredBitrateLocked()
+	return getEstimatedBitrate(br, spatial, temporal)
+}
+
+// GetEstimatedLayeredBitrate returns layered bitrates like GetLayeredBitrate, but with bitrates of
+// layers pending measurement estimated. Only available spatial layers which are published
+// are estimated, up to the max temporal layer seen.
+func (s *StreamTrackerManager) GetEstimatedLayeredBitrate() ([]int32, Bitrates) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	availableLayers, br := s.getLayeredBitrateLocked()
+	if s.maxTemporalLayerSeen < 0 {
+		return availableLayers, br
+	}
+
+	estimated := br
+	for _, spatial := range availableLayers {
+		if spatial < 0 || int(spatial) >= len(br) || !s.isPublishedLayerLocked(spatial) {
+			continue
+		}
+
+		for temporal := int32(0); temporal <= s.maxTemporalLayerSeen && int(temporal) < len(br[spatial]); temporal++ {
+			if estimated[spatial][temporal] == 0 {
+				estimated[spatial][temporal] = getEstimatedBitrate(br, spatial, temporal)
+			}
+		}
+	}
+	return availableLayers, estimated
+}
+
+func (s *StreamTrackerManager) isPublishedLayerLocked(spatial int32) bool {
+	ti := s.trackInfo.Load()
+	if ti == nil {
+		return false
+	}
+
+	for _, layer := range ti.Layers {
+		if buffer.VideoQualityToSpatialLayer(layer.Quality, ti) == spatial {
+			return true
+		}
+	}
+	return false
+}
+
+func getEstimatedBitrate(br Bitrates, spatial, temporal int32) int64 {
+	if spatial < 0 || int(spatial) >= len(br) || temporal < 0 || int(temporal) >= len(br[spatial]) {
+		return 0
+	}
+
+	if br[spatial][temporal] != 0 {
+		return br[spatial][temporal]
+	}
+
+	abs := func(v int32) int32 {
+		if v < 0 {
+			return -v
+		}
+		return v
+	}
+
+	refSpatial, refTemporal := buffer.InvalidLayerSpatial, buffer.InvalidLayerTemporal
+	for s := range br {
+		for t := range br[s] {
+			if br[s][t] == 0 {
+				continue
+			}
+
+			if refSpatial == buffer.InvalidLayerSpatial {
+				refSpatial, refTemporal = int32(s), int32(t)
+				continue
+			}
+
+			ds, dt := abs(int32(s)-spatial), abs(int32(t)-temporal)
+			refDS, refDT := abs(refSpatial-spatial), abs(refTemporal-temporal)
+			if ds < refDS || (ds == refDS && dt < refDT) {
+				refSpatial, refTemporal = int32(s), int32(t)
+			}
+		}
+	}
+	if refSpatial == buffer.InvalidLayerSpatial {
+		return 0
+	}
+
+	return int64(float64(br[refSpatial][refTemporal]) *
+		math.Pow(estimatedSpatialLayerBitrateRatio, float64(spatial-refSpatial)) *
+		math.Pow(estimatedTemporalLayerBitrateRatio, float64(temporal-refTemporal)))
+}
+
 func (s *StreamTrackerManager) hasSpatialLayerLocked(layer int32) bool {
 	for _, l := range s.availableLayers {
 		if l == layer {
@@ -474,6 +748,11 @@ func (s *StreamTrackerManager) addAvailableLayer(layer int32) {
 	// check if new layer is the max layer
 	isMaxLayerChange := s.availableLayers[len(s.availableLayers)-1] == layer
 
+	s.layerEventLog.add(layer, LayerEventAvailable, s.availableLayers)
+	if isMaxLayerChange {
+		s.layerEventLog.add(layer, LayerEventMaxAvailableLayerChange, s.availableLayers)
+	}
+
 	s.logger.Debugw(
 		"available layers changed - layer seen",
 		"added", layer,
@@ -516,6 +795,17 @@ func (s *StreamTrackerManager) removeAvailableLayer(layer int32) {
 	if len(s.availableLayers) > 0 {
 		curMaxLayer = s.availableLayers[len(s.availableLayers)-1]
 	}
+
+	s.layerEventLog.add(layer, LayerEventUnavailable, s.availableLayers)
+	if curMaxLayer != prevMaxLayer {
+		s.layerEventLog.add(curMaxLayer, LayerEventMaxAvailableLayerChange, s.availableLayers)
+	}
+
+	isMaxTemporalLayerSeenChanged := false
+	if layer == prevMaxLayer {
+		isMaxTemporalLayerSeenChanged = s.recalcMaxTemporalLayerSeenLocked()
+	}
+	maxTemporalLayerSeen := s.maxTemporalLayerSeen
 	s.lock.Unlock()
 
 	// need to immediately switch off unavailable layers
@@ -526,7 +816,31 @@ func (s *StreamTrackerManager) removeAvailableLayer(layer int32) {
 		if curMaxLayer != prevMaxLayer {
 			listener.OnMaxAvailableLayerChanged(curMaxLayer)
 		}
+
+		if isMaxTemporalLayerSeenChanged {
+			listener.OnMaxTemporalLayerSeenChanged(maxTemporalLayerSeen)
+		}
+	}
+}
+
+// recalcMaxTemporalLayerSeenLocked recomputes max temporal layer seen from bitrates of available layers,
+// allowing it to go down when higher temporal layers stop with the removal of the highest spatial layer.
+// Max temporal layer seen is left unchanged when remaining layers have not been measured yet.
+// Returns true if max temporal layer seen changed.
+func (s *StreamTrackerManager) recalcMaxTemporalLayerSeenLocked() bool {
+	_, brs := s.getLayeredBitrateLocked()
+	maxTemporalLayerSeen := getMaxTemporalLayer(brs)
+	if maxTemporalLayerSeen == buffer.InvalidLayerTemporal || maxTemporalLayerSeen == s.maxTemporalLayerSeen {
+		return false
 	}
+
+	s.logger.Debugw(
+		"max temporal layer seen recalculated",
+		"from", s.maxTemporalLayerSeen,
+		"to", maxTemporalLayerSeen,
+	)
+	s.maxTemporalLayerSeen = maxTemporalLayerSeen
+	return true
 }
 
 func (s *StreamTrackerManager) maxExpectedLayerFromTrackInfo() {
@@ -549,6 +863,14 @@ func (s *StreamTrackerManager) maxExpectedLayerFromTrackInfoLocked() {
 	}
 }
 
+// GetLayerEventLog returns the most recent layer events, oldest first.
+func (s *StreamTrackerManager) GetLayerEventLog() []LayerEvent {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	return s.layerEventLog.get()
+}
+
 func (s *StreamTrackerManager) GetMaxTemporalLayerSeen() int32 {
 	s.lock.RLock()
 	defer s.lock.RUnlock()
@@ -557,16 +879,7 @@ func (s *StreamTrackerManager) GetMaxTemporalLayerSeen() int32 {
 }
 
 func (s *StreamTrackerManager) updateMaxTemporalLayerSeen(brs Bitrates) {
-	maxTemporalLayerSeen := buffer.InvalidLayerTemporal
-done:
-	for t := int32(len(brs[0])) - 1; t >= 0; t-- {
-		for s := int32(len(brs)) - 1; s >= 0; s-- {
-			if brs[s][t] != 0 {
-				maxTemporalLayerSeen = t
-				break done
-			}
-		}
-	}
+	maxTemporalLayerSeen := getMaxTemporalLayer(brs)
 
 	s.lock.Lock()
 	if maxTemporalLayerSeen <= s.maxTemporalLayerSeen {
@@ -583,7 +896,11 @@ done:
 }
 
 func (s *StreamTrackerManager) bitrateReporter() {
-	ticker := time.NewTicker(time.Second)
+	interval := s.trackerConfig.BitrateReporterTickInterval
+	if interval <= 0 {
+		interval = defaultBitrateReporterTickInterval
+	}
+	ticker := time.NewTicker(interval)
 	defer ticker.Stop()
 
 	for {
@@ -594,6 +911,7 @@ func (s *StreamTrackerManager) bitrateReporter() {
 		case <-ticker.C:
 			al, brs := s.GetLayeredBitrate()
 			s.updateMaxTemporalLayerSeen(brs)
+			s.addBitrateSample(time.Now(), brs)
 
 			if listener := s.getListener(); listener != nil {
 				listener.OnBitrateReport(al, brs)
@@ -601,3 +919,17 @@ func (s *StreamTrackerManager) bitrateReporter() {
 		}
 	}
 }
+
+// ---------------------------------------------------
+
+func getMaxTemporalLayer(brs Bitrates) int32 {
+	for t := int32(len(brs[0])) - 1; t >= 0; t-- {
+		for s := int32(len(brs)) - 1; s >= 0; s-- {
+			if brs[s][t] != 0 {
+				return t
+			}
+		}
+	}
+
+	return buffer.InvalidLayerTemporal
+}
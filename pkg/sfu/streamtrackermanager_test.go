@@ -0,0 +1,232 @@
+// Copyright 2024 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sfu
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/livekit/protocol/livekit"
+	"github.com/livekit/protocol/logger"
+
+	"github.com/livekit/livekit-server/pkg/config"
+	"github.com/livekit/livekit-server/pkg/sfu/buffer"
+	"github.com/livekit/livekit-server/pkg/sfu/streamtracker"
+)
+
+type fixedBitrateStreamTracker struct {
+	streamtracker.StreamTrackerWorker
+
+	bitrates []int64
+}
+
+func (f *fixedBitrateStreamTracker) BitrateTemporalCumulative() []int64 {
+	return f.bitrates
+}
+
+type maxTemporalLayerSeenListener struct {
+	StreamTrackerManagerListener
+
+	maxTemporalLayerSeen []int32
+}
+
+func (m *maxTemporalLayerSeenListener) OnAvailableLayersChanged()          {}
+func (m *maxTemporalLayerSeenListener) OnMaxAvailableLayerChanged(_ int32) {}
+func (m *maxTemporalLayerSeenListener) OnMaxTemporalLayerSeenChanged(maxTemporalLayerSeen int32) {
+	m.maxTemporalLayerSeen = append(m.maxTemporalLayerSeen, maxTemporalLayerSeen)
+}
+
+func TestStreamTrackerManager_MaxTemporalLayerSeenOnLayerRemoval(t *testing.T) {
+	s := NewStreamTrackerManager(logger.GetLogger(), &livekit.TrackInfo{}, false, 90000, config.StreamTrackersConfig{})
+	defer s.Close()
+
+	listener := &maxTemporalLayerSeenListener{}
+	s.SetListener(listener)
+
+	// layer 0 has two temporal layers, layer 2 has three
+	s.trackers[0] = &fixedBitrateStreamTracker{bitrates: []int64{100, 200, 0, 0}}
+	s.trackers[1] = &fixedBitrateStreamTracker{bitrates: []int64{300, 400, 0, 0}}
+	s.trackers[2] = &fixedBitrateStreamTracker{bitrates: []int64{500, 600, 700, 0}}
+	s.availableLayers = []int32{0, 1, 2}
+	_, brs := s.GetLayeredBitrate()
+	s.updateMaxTemporalLayerSeen(brs)
+	require.Equal(t, int32(2), s.GetMaxTemporalLayerSeen())
+	require.Equal(t, []int32{2}, listener.maxTemporalLayerSeen)
+
+	// removing a layer which is not the highest does not recalculate
+	s.removeAvailableLayer(1)
+	require.Equal(t, int32(2), s.GetMaxTemporalLayerSeen())
+
+	s.removeAvailableLayer(2)
+	require.Equal(t, int32(1), s.GetMaxTemporalLayerSeen())
+	require.Equal(t, []int32{2, 1}, listener.maxTemporalLayerSeen)
+
+	// unchanged when nothing remains to measure
+	s.removeAvailableLayer(0)
+	require.Equal(t, int32(1), s.GetMaxTemporalLayerSeen())
+	require.Equal(t, []int32{2, 1}, listener.maxTemporalLayerSeen)
+	require.Equal(t, buffer.InvalidLayerTemporal, getMaxTemporalLayer(Bitrates{}))
+}
+
+func TestStreamTrackerManager_GetTemporalLayerBitrates(t *testing.T) {
+	s := NewStreamTrackerManager(logger.GetLogger(), &livekit.TrackInfo{}, false, 90000, config.StreamTrackersConfig{})
+	defer s.Close()
+
+	require.False(t, s.IsSVC())
+
+	s.trackers[0] = &fixedBitrateStreamTracker{bitrates: []int64{100, 200, 0, 0}}
+	s.trackers[2] = &fixedBitrateStreamTracker{bitrates: []int64{500, 600, 700, 0}}
+	s.availableLayers = []int32{0, 2}
+
+	require.Equal(t, []int64{100, 200, 0, 0}, s.GetTemporalLayerBitrates(0))
+	require.Equal(t, []int64{0, 0, 0, 0}, s.GetTemporalLayerBitrates(1))
+	require.Equal(t, []int64{500, 600, 700, 0}, s.GetTemporalLayerBitrates(2))
+	require.Nil(t, s.GetTemporalLayerBitrates(buffer.InvalidLayerSpatial))
+	require.Nil(t, s.GetTemporalLayerBitrates(buffer.DefaultMaxLayerSpatial+1))
+}
+
+func TestStreamTrackerManager_GetStableLayeredBitrate(t *testing.T) {
+	s := NewStreamTrackerManager(logger.GetLogger(), &livekit.TrackInfo{}, false, 90000, config.StreamTrackersConfig{})
+	defer s.Close()
+	s.availableLayers = []int32{0, 1, 2}
+
+	now := time.Now()
+	s.addBitrateSample(now.Add(-5*time.Second), Bitrates{{100, 200}, {300, 400}})
+	s.addBitrateSample(now.Add(-4*time.Second), Bitrates{{110, 180}, {250, 450}, {600, 700}})
+	s.addBitrateSample(now.Add(-3*time.Second), Bitrates{{90, 210}, {0, 0}, {650, 750}})
+	s.addBitrateSample(now.Add(-2*time.Second), Bitrates{{120, 220}, {350, 420}, {550, 800}})
+	s.addBitrateSample(now.Add(-time.Second), Bitrates{{105, 190}, {320, 410}, {700, 720}})
+
+	// layer 1 dropped out, layer 2 was not available from the start
+	al, brs := s.GetStableLayeredBitrate(4500 * time.Millisecond)
+	require.Equal(t, []int32{0}, al)
+	require.Equal(t, Bitrates{{90, 180}}, brs)
+
+	al, brs = s.GetStableLayeredBitrate(2500 * time.Millisecond)
+	require.Equal(t, []int32{0, 2}, al)
+	require.Equal(t, Bitrates{{90, 190}, {0, 0}, {550, 720}}, brs)
+	// layer 1 has been stable since
+	al, brs = s.GetStableLayeredBitrate(1500 * time.Millisecond)
+	require.Equal(t, []int32{0, 1, 2}, al)
+	require.Equal(t, Bitrates{{105, 190}, {320, 410}, {550, 720}}, brs)
+
+	// not enough history
+	al, brs = s.GetStableLayeredBitrate(10 * time.Second)
+	require.Empty(t, al)
+	require.Equal(t, Bitrates{}, brs)
+
+	// history is bounded, one report before the history duration is kept
+	s.addBitrateSample(now.Add(stableBitrateHistoryDuration-2*time.Second), Bitrates{})
+	require.Len(t, s.bitrateHistory, 3)
+}
+
+func TestStreamTrackerManager_LayerEventLog(t *testing.T) {
+	s := NewStreamTrackerManager(logger.GetLogger(), &livekit.TrackInfo{}, false, 90000, config.StreamTrackersConfig{
+		Video: config.StreamTrackerConfig{LayerEventLogSize: 4},
+	})
+	defer s.Close()
+	s.SetListener(&maxTemporalLayerSeenListener{})
+	require.Empty(t, s.GetLayerEventLog())
+
+	// log grows as events are added
+	s.addAvailableLayer(0)
+	require.Len(t, s.GetLayerEventLog(), 2)
+	require.Len(t, s.layerEventLog.events, 2)
+
+	s.addAvailableLayer(1)
+	s.addAvailableLayer(1)
+	require.Len(t, s.GetLayerEventLog(), 4)
+
+	s.removeAvailableLayer(1)
+
+	type event struct {
+		layer           int32
+		event           string
+		availableLayers []int32
+	}
+	var events []event
+	for _, e := range s.GetLayerEventLog() {
+		require.False(t, e.Time.IsZero())
+		events = append(events, event{e.Layer, e.Event, e.AvailableLayers})
+	}
+	// oldest events are dropped
+	require.Equal(t, []event{
+		{1, LayerEventAvailable, []int32{0, 1}},
+		{1, LayerEventMaxAvailableLayerChange, []int32{0, 1}},
+		{1, LayerEventUnavailable, []int32{0}},
+		{0, LayerEventMaxAvailableLayerChange, []int32{0}},
+	}, events)
+}
+
+func TestGetEstimatedBitrate(t *testing.T) {
+	var br Bitrates
+	require.Zero(t, getEstimatedBitrate(br, 1, 1))
+
+	br[0][0] = 100_000
+	br[0][1] = 150_000
+	br[1][0] = 400_000
+
+	// measured
+	require.Equal(t, int64(150_000), getEstimatedBitrate(br, 0, 1))
+	require.Equal(t, int64(400_000), getEstimatedBitrate(br, 1, 0))
+
+	// same spatial layer preferred
+	require.Equal(t, int64(600_000), getEstimatedBitrate(br, 1, 1))
+	require.Equal(t, int64(225_000), getEstimatedBitrate(br, 0, 2))
+
+	// closest spatial layer
+	require.Equal(t, int64(800_000), getEstimatedBitrate(br, 2, 0))
+	require.Equal(t, int64(1_200_000), getEstimatedBitrate(br, 2, 1))
+
+	// invalid layers
+	require.Zero(t, getEstimatedBitrate(br, -1, 0))
+	require.Zero(t, getEstimatedBitrate(br, 0, int32(len(br[0]))))
+}
+
+func TestStreamTrackerManager_GetEstimatedLayeredBitrate(t *testing.T) {
+	ti := &livekit.TrackInfo{
+		Type: livekit.TrackType_VIDEO,
+		Layers: []*livekit.VideoLayer{
+			{Quality: livekit.VideoQuality_LOW},
+			{Quality: livekit.VideoQuality_MEDIUM},
+		},
+	}
+	s := NewStreamTrackerManager(logger.GetLogger(), ti, false, 90000, config.StreamTrackersConfig{})
+	defer s.Close()
+
+	// layer 1 is pending measurement, layer 2 is not published
+	s.trackers[0] = &fixedBitrateStreamTracker{bitrates: []int64{100, 150, 0, 0}}
+	s.trackers[1] = &fixedBitrateStreamTracker{bitrates: []int64{0, 0, 0, 0}}
+	s.trackers[2] = &fixedBitrateStreamTracker{bitrates: []int64{0, 0, 0, 0}}
+	s.availableLayers = []int32{0, 1, 2}
+
+	// nothing is estimated before temporal layers are known
+	_, brs := s.GetEstimatedLayeredBitrate()
+	require.Equal(t, Bitrates{{100, 150}}, brs)
+
+	s.maxTemporalLayerSeen = 1
+	al, brs := s.GetEstimatedLayeredBitrate()
+	require.Equal(t, []int32{0, 1, 2}, al)
+	require.Equal(t, Bitrates{{100, 150}, {200, 300}}, brs)
+
+	// measured bitrates are not changed
+	_, brs = s.GetLayeredBitrate()
+	require.Equal(t, Bitrates{{100, 150}}, brs)
+
+	require.Equal(t, int64(300), s.GetEstimatedBitrate(1, 1))
+	require.Zero(t, s.GetEstimatedBitrate(2, 0))
+}
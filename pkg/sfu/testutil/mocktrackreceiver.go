@@ -0,0 +1,88 @@
+// Copyright 2024 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package testutil
+
+import (
+	"sync"
+
+	"github.com/livekit/protocol/livekit"
+
+	"github.com/livekit/livekit-server/pkg/sfu"
+	"github.com/livekit/livekit-server/pkg/sfu/buffer"
+)
+
+//go:generate go run github.com/maxbrunsfeld/counterfeiter/v6 -o fake_track_receiver.go ../ TrackReceiver
+
+var _ sfu.TrackReceiver = (*MockTrackReceiver)(nil)
+
+// MockTrackReceiver is a TrackReceiver for unit testing down stream components
+// without a WebRTC connection. Return values and call recording are provided by
+// the embedded FakeTrackReceiver, down tracks added successfully are kept so
+// that packets can be fed to them with InjectRTPPacket.
+type MockTrackReceiver struct {
+	*FakeTrackReceiver
+
+	lock       sync.RWMutex
+	downTracks map[livekit.ParticipantID]sfu.TrackSender
+}
+
+func NewMockTrackReceiver() *MockTrackReceiver {
+	return &MockTrackReceiver{
+		FakeTrackReceiver: &FakeTrackReceiver{},
+		downTracks:        make(map[livekit.ParticipantID]sfu.TrackSender),
+	}
+}
+
+func (m *MockTrackReceiver) AddDownTrack(track sfu.TrackSender) error {
+	if err := m.FakeTrackReceiver.AddDownTrack(track); err != nil {
+		return err
+	}
+
+	m.lock.Lock()
+	m.downTracks[track.SubscriberID()] = track
+	m.lock.Unlock()
+	return nil
+}
+
+func (m *MockTrackReceiver) DeleteDownTrack(subscriberID livekit.ParticipantID) {
+	m.FakeTrackReceiver.DeleteDownTrack(subscriberID)
+
+	m.lock.Lock()
+	delete(m.downTracks, subscriberID)
+	m.lock.Unlock()
+}
+
+func (m *MockTrackReceiver) GetDownTracks() []sfu.TrackSender {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	downTracks := make([]sfu.TrackSender, 0, len(m.downTracks))
+	for _, dt := range m.downTracks {
+		downTracks = append(downTracks, dt)
+	}
+	return downTracks
+}
+
+// InjectRTPPacket writes the packet to all registered down tracks
+// and returns the first error encountered, if any.
+func (m *MockTrackReceiver) InjectRTPPacket(pkt *buffer.ExtPacket, layer int32) error {
+	var firstErr error
+	for _, dt := range m.GetDownTracks() {
+		if err := dt.WriteRTP(pkt, layer); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
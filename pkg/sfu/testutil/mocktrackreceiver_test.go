@@ -0,0 +1,87 @@
+// Copyright 2024 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package testutil
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/livekit/protocol/livekit"
+
+	"github.com/livekit/livekit-server/pkg/sfu"
+	"github.com/livekit/livekit-server/pkg/sfu/buffer"
+)
+
+type testTrackSender struct {
+	sfu.TrackSender
+
+	subscriberID livekit.ParticipantID
+	written      []*buffer.ExtPacket
+	layers       []int32
+}
+
+func (t *testTrackSender) SubscriberID() livekit.ParticipantID {
+	return t.subscriberID
+}
+
+func (t *testTrackSender) WriteRTP(p *buffer.ExtPacket, layer int32) error {
+	t.written = append(t.written, p)
+	t.layers = append(t.layers, layer)
+	return nil
+}
+
+func TestMockTrackReceiver(t *testing.T) {
+	t.Run("returns injected values", func(t *testing.T) {
+		m := NewMockTrackReceiver()
+		m.TrackIDReturns("TR_test")
+		m.IsClosedReturns(true)
+
+		require.Equal(t, livekit.TrackID("TR_test"), m.TrackID())
+		require.True(t, m.IsClosed())
+		require.Equal(t, 1, m.TrackIDCallCount())
+	})
+
+	t.Run("injects packets into registered down tracks", func(t *testing.T) {
+		m := NewMockTrackReceiver()
+		dt1 := &testTrackSender{subscriberID: "PA_1"}
+		dt2 := &testTrackSender{subscriberID: "PA_2"}
+		require.NoError(t, m.AddDownTrack(dt1))
+		require.NoError(t, m.AddDownTrack(dt2))
+		require.Equal(t, 2, m.AddDownTrackCallCount())
+
+		pkt := &buffer.ExtPacket{ExtSequenceNumber: 10}
+		require.NoError(t, m.InjectRTPPacket(pkt, 1))
+		require.Equal(t, []*buffer.ExtPacket{pkt}, dt1.written)
+		require.Equal(t, []int32{1}, dt1.layers)
+		require.Equal(t, []*buffer.ExtPacket{pkt}, dt2.written)
+
+		m.DeleteDownTrack("PA_1")
+		require.Equal(t, livekit.ParticipantID("PA_1"), m.DeleteDownTrackArgsForCall(0))
+		require.NoError(t, m.InjectRTPPacket(pkt, 1))
+		require.Len(t, dt1.written, 1)
+		require.Len(t, dt2.written, 2)
+	})
+
+	t.Run("does not register down track on error", func(t *testing.T) {
+		m := NewMockTrackReceiver()
+		m.AddDownTrackReturns(errors.New("rejected"))
+
+		dt := &testTrackSender{subscriberID: "PA_1"}
+		require.Error(t, m.AddDownTrack(dt))
+		require.Empty(t, m.GetDownTracks())
+	})
+}
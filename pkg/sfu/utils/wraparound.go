@@ -15,9 +15,15 @@
 package utils
 
 import (
+	"encoding/binary"
+	"errors"
 	"unsafe"
 )
 
+var (
+	errInvalidWrapAroundLength = errors.New("invalid wrap around data length")
+)
+
 type number interface {
 	uint16 | uint32
 }
@@ -77,9 +83,7 @@ func (w *WrapAround[T, ET]) Update(val T) (result WrapAroundUpdateResult[ET]) {
 		return
 	}
 
-	gap := val - w.highest
-	if gap > T(w.fullRange>>1) {
-		// out-of-order
+	if w.IsOutOfOrder(val) {
 		return w.maybeAdjustStart(val)
 	}
 
@@ -96,6 +100,16 @@ func (w *WrapAround[T, ET]) Update(val T) (result WrapAroundUpdateResult[ET]) {
 	return
 }
 
+// IsOutOfOrder returns true if val is older than the highest value seen so far.
+func (w *WrapAround[T, ET]) IsOutOfOrder(val T) bool {
+	return w.initialized && val-w.highest > T(w.fullRange>>1)
+}
+
+// IsDuplicate returns true if val is the same as the highest value seen so far.
+func (w *WrapAround[T, ET]) IsDuplicate(val T) bool {
+	return w.initialized && val == w.highest
+}
+
 func (w *WrapAround[T, ET]) RollbackRestart(ev ET) {
 	if w.isWrapBack(w.start, T(ev)) {
 		w.cycles -= w.fullRange
@@ -126,6 +140,53 @@ func (w *WrapAround[T, ET]) GetExtendedHighest() ET {
 	return w.extendedHighest
 }
 
+// GetCycleCount returns the number of full wrap arounds seen.
+func (w *WrapAround[T, ET]) GetCycleCount() int {
+	return int(w.cycles / w.fullRange)
+}
+
+// Distance returns the signed distance from a to b accounting for wrap around.
+func (w *WrapAround[T, ET]) Distance(a, b T) int64 {
+	return Distance(a, b)
+}
+
+// Marshal encodes the state in little-endian binary format as
+// initialized (1 byte), start (size of T), highest (size of T), cycles (size of ET).
+func (w *WrapAround[T, ET]) Marshal() []byte {
+	var t T
+	var et ET
+	sizeT := int(unsafe.Sizeof(t))
+	sizeET := int(unsafe.Sizeof(et))
+
+	b := make([]byte, 1+2*sizeT+sizeET)
+	if w.initialized {
+		b[0] = 1
+	}
+	putUint(b[1:], uint64(w.start), sizeT)
+	putUint(b[1+sizeT:], uint64(w.highest), sizeT)
+	putUint(b[1+2*sizeT:], uint64(w.cycles), sizeET)
+	return b
+}
+
+// Unmarshal restores state encoded by Marshal.
+func (w *WrapAround[T, ET]) Unmarshal(b []byte) error {
+	var t T
+	var et ET
+	sizeT := int(unsafe.Sizeof(t))
+	sizeET := int(unsafe.Sizeof(et))
+
+	if len(b) != 1+2*sizeT+sizeET {
+		return errInvalidWrapAroundLength
+	}
+
+	w.initialized = b[0] != 0
+	w.start = T(getUint(b[1:], sizeT))
+	w.highest = T(getUint(b[1+sizeT:], sizeT))
+	w.cycles = ET(getUint(b[1+2*sizeT:], sizeET))
+	w.updateExtendedHighest()
+	return nil
+}
+
 func (w *WrapAround[T, ET]) updateExtendedHighest() {
 	w.extendedHighest = getExtendedHighest(w.cycles, w.highest)
 }
@@ -184,3 +245,38 @@ func (w *WrapAround[T, ET]) isWrapBack(earlier T, later T) bool {
 func getExtendedHighest[T number, ET extendedNumber](cycles ET, val T) ET {
 	return cycles + ET(val)
 }
+
+// Distance returns the signed distance from a to b in wrapped space,
+// i. e. positive if b is after a and negative if b is before a.
+// A distance of exactly half the range is considered positive.
+func Distance[T number](a, b T) int64 {
+	fullRange := int64(1) << (unsafe.Sizeof(a) * 8)
+	diff := int64(b - a)
+	if diff > fullRange>>1 {
+		diff -= fullRange
+	}
+	return diff
+}
+
+func putUint(b []byte, val uint64, size int) {
+	switch size {
+	case 2:
+		binary.LittleEndian.PutUint16(b, uint16(val))
+	case 4:
+		binary.LittleEndian.PutUint32(b, uint32(val))
+	case 8:
+		binary.LittleEndian.PutUint64(b, val)
+	}
+}
+
+func getUint(b []byte, size int) uint64 {
+	switch size {
+	case 2:
+		return uint64(binary.LittleEndian.Uint16(b))
+	case 4:
+		return uint64(binary.LittleEndian.Uint32(b))
+	case 8:
+		return binary.LittleEndian.Uint64(b)
+	}
+	return 0
+}
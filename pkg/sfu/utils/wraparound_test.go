@@ -591,3 +591,86 @@ func TestWrapAroundUint32(t *testing.T) {
 		})
 	}
 }
+
+func TestWrapAroundDistance(t *testing.T) {
+	w := NewWrapAround[uint16, uint64](WrapAroundParams{})
+	require.Equal(t, int64(0), w.Distance(10, 10))
+	require.Equal(t, int64(5), w.Distance(10, 15))
+	require.Equal(t, int64(-5), w.Distance(15, 10))
+	require.Equal(t, int64(10), w.Distance(65530, 4))
+	require.Equal(t, int64(-10), w.Distance(4, 65530))
+	require.Equal(t, int64(32768), w.Distance(0, 32768))
+	require.Equal(t, int64(-32767), w.Distance(0, 32769))
+
+	require.Equal(t, int64(10), Distance[uint32](0xffff_fffb, 5))
+	require.Equal(t, int64(-10), Distance[uint32](5, 0xffff_fffb))
+}
+
+func TestWrapAroundIsOutOfOrder(t *testing.T) {
+	w := NewWrapAround[uint16, uint64](WrapAroundParams{})
+	require.False(t, w.IsOutOfOrder(10))
+	require.False(t, w.IsDuplicate(0))
+
+	w.Update(65530)
+	require.True(t, w.IsDuplicate(65530))
+	require.False(t, w.IsOutOfOrder(65530))
+	require.True(t, w.IsOutOfOrder(65529))
+	require.False(t, w.IsOutOfOrder(65531))
+	require.False(t, w.IsOutOfOrder(5))
+
+	w.Update(5)
+	require.True(t, w.IsOutOfOrder(65535))
+	require.False(t, w.IsDuplicate(65530))
+	require.True(t, w.IsDuplicate(5))
+}
+
+func TestWrapAroundMarshal(t *testing.T) {
+	roundTrip := func(w *WrapAround[uint16, uint64]) *WrapAround[uint16, uint64] {
+		b := w.Marshal()
+		require.Len(t, b, 1+2+2+8)
+
+		r := NewWrapAround[uint16, uint64](WrapAroundParams{})
+		require.NoError(t, r.Unmarshal(b))
+		require.Equal(t, w.initialized, r.initialized)
+		require.Equal(t, w.GetStart(), r.GetStart())
+		require.Equal(t, w.GetHighest(), r.GetHighest())
+		require.Equal(t, w.GetExtendedHighest(), r.GetExtendedHighest())
+		return r
+	}
+
+	// zero value
+	w := NewWrapAround[uint16, uint64](WrapAroundParams{})
+	roundTrip(w)
+
+	// initialized, not wrapped
+	w.Update(65530)
+	w.Update(65533)
+	roundTrip(w)
+
+	// wrapped, restored state should continue extending
+	w.Update(3)
+	r := roundTrip(w)
+	require.Equal(t, uint64(65536+3), r.GetExtendedHighest())
+	require.Equal(t, uint64(65536+10), r.Update(10).ExtendedVal)
+
+	require.Error(t, r.Unmarshal([]byte{1, 2, 3}))
+
+	w32 := NewWrapAround[uint32, uint64](WrapAroundParams{})
+	w32.Update(0xffff_fff0)
+	w32.Update(0x10)
+	r32 := NewWrapAround[uint32, uint64](WrapAroundParams{})
+	require.NoError(t, r32.Unmarshal(w32.Marshal()))
+	require.Equal(t, w32.GetExtendedHighest(), r32.GetExtendedHighest())
+}
+
+func TestWrapAroundGetCycleCount(t *testing.T) {
+	w := NewWrapAround[uint16, uint64](WrapAroundParams{})
+	require.Equal(t, 0, w.GetCycleCount())
+
+	sn := uint16(0)
+	for i := 0; i < 11; i++ {
+		w.Update(sn)
+		sn += 20000
+	}
+	require.Equal(t, 3, w.GetCycleCount())
+}
@@ -0,0 +1,116 @@
+// Copyright 2024 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sfu
+
+import (
+	"github.com/pion/rtp/codecs"
+
+	"github.com/livekit/livekit-server/pkg/sfu/buffer"
+)
+
+// vp9ScalabilityStructure tracks the scalability structure signalled in-band by a VP9 SVC stream
+// and uses it to resolve layers of packets which do not carry layer indices.
+//
+// In non-flexible mode, layer indices are optional. When they are absent, the temporal layer of
+// a picture is given by its position in the picture group described by the last seen SS and the
+// spatial layer is given by the position of the layer frame within the picture.
+type vp9ScalabilityStructure struct {
+	numSpatialLayers int32
+	// temporal layer of each picture in the picture group (PG) description of the last seen SS
+	pgTID []uint8
+	hasPG bool
+
+	initialized    bool
+	lastPictureID  uint16
+	lastTimestamp  uint32
+	pictureIndex   int
+	spatialInFrame int32
+}
+
+func newVP9ScalabilityStructure() *vp9ScalabilityStructure {
+	return &vp9ScalabilityStructure{}
+}
+
+// Update resolves spatial and temporal layers of a VP9 packet in place. Packets carrying
+// a dependency descriptor or explicit layer indices are left untouched.
+func (v *vp9ScalabilityStructure) Update(pkt *buffer.ExtPacket) {
+	if pkt.DependencyDescriptor != nil {
+		return
+	}
+
+	vp9, ok := pkt.Payload.(codecs.VP9Packet)
+	if !ok {
+		return
+	}
+
+	if vp9.V {
+		v.updateSS(&vp9)
+	}
+
+	newPicture := !v.initialized
+	if v.initialized {
+		if vp9.I {
+			newPicture = vp9.PictureID != v.lastPictureID
+		} else {
+			newPicture = pkt.Packet.Timestamp != v.lastTimestamp
+		}
+	}
+
+	if newPicture {
+		if vp9.V || pkt.KeyFrame || !v.initialized {
+			// SS is sent at the start of a picture group, restart picture indexing
+			v.pictureIndex = 0
+		} else {
+			v.pictureIndex++
+		}
+		v.spatialInFrame = -1
+	}
+
+	if vp9.B {
+		v.spatialInFrame++
+	}
+
+	v.initialized = true
+	v.lastPictureID = vp9.PictureID
+	v.lastTimestamp = pkt.Packet.Timestamp
+
+	if vp9.L {
+		// explicit layer indices, already set from payload descriptor
+		return
+	}
+
+	if v.spatialInFrame >= 0 {
+		spatial := v.spatialInFrame
+		if v.numSpatialLayers > 0 && spatial >= v.numSpatialLayers {
+			spatial = v.numSpatialLayers - 1
+		}
+		pkt.Spatial = spatial
+	}
+
+	if v.hasPG && len(v.pgTID) != 0 {
+		pkt.Temporal = int32(v.pgTID[v.pictureIndex%len(v.pgTID)])
+	}
+}
+
+func (v *vp9ScalabilityStructure) updateSS(vp9 *codecs.VP9Packet) {
+	v.numSpatialLayers = int32(vp9.NS) + 1
+	if !vp9.G {
+		// picture group description not present, keep the previous one
+		return
+	}
+
+	v.pgTID = append(v.pgTID[:0], vp9.PGTID...)
+	v.hasPG = true
+}
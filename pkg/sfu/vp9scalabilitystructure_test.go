@@ -0,0 +1,86 @@
+// Copyright 2024 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sfu
+
+import (
+	"testing"
+
+	"github.com/pion/rtp"
+	"github.com/pion/rtp/codecs"
+	"github.com/stretchr/testify/require"
+
+	"github.com/livekit/livekit-server/pkg/sfu/buffer"
+)
+
+func TestVP9ScalabilityStructure(t *testing.T) {
+	// handcrafted payload descriptors of an L2T3 stream in non-flexible mode without layer indices,
+	// each followed by a single byte of VP9 payload
+	packets := []struct {
+		timestamp uint32
+		keyFrame  bool
+		payload   []byte
+		spatial   int32
+		temporal  int32
+	}{
+		// key frame, SS with 2 spatial layers and a picture group of 4 pictures with temporal layers 0, 2, 1, 2
+		{1000, true, []byte{0x8e, 0x01, 0x28, 0x04, 0x04, 0x04, 0x54, 0x01, 0x34, 0x02, 0x54, 0x01, 0x82}, 0, 0},
+		{1000, true, []byte{0x8c, 0x01, 0x82}, 1, 0},
+		{4000, false, []byte{0xcc, 0x02, 0x86}, 0, 2},
+		{4000, false, []byte{0xc8, 0x02, 0x86}, 1, 2},
+		{4000, false, []byte{0xc4, 0x02, 0x86}, 1, 2},
+		{7000, false, []byte{0xcc, 0x03, 0x86}, 0, 1},
+		{7000, false, []byte{0xcc, 0x03, 0x86}, 1, 1},
+		{10000, false, []byte{0xcc, 0x04, 0x86}, 0, 2},
+		{10000, false, []byte{0xcc, 0x04, 0x86}, 1, 2},
+		// start of next picture group
+		{13000, false, []byte{0xcc, 0x05, 0x86}, 0, 0},
+		{13000, false, []byte{0xcc, 0x05, 0x86}, 1, 0},
+		// explicit layer indices, T1 S1, are not overridden
+		{16000, false, []byte{0xec, 0x06, 0x32, 0x01, 0x86}, 1, 1},
+	}
+
+	v := newVP9ScalabilityStructure()
+	for i, r := range packets {
+		var vp9 codecs.VP9Packet
+		_, err := vp9.Unmarshal(r.payload)
+		require.NoError(t, err)
+
+		pkt := &buffer.ExtPacket{
+			VideoLayer: buffer.VideoLayer{
+				Spatial:  int32(vp9.SID),
+				Temporal: int32(vp9.TID),
+			},
+			Packet: &rtp.Packet{
+				Header:  rtp.Header{Timestamp: r.timestamp},
+				Payload: r.payload,
+			},
+			Payload:  vp9,
+			KeyFrame: r.keyFrame,
+		}
+		v.Update(pkt)
+		require.Equal(t, r.spatial, pkt.Spatial, "packet %d", i)
+		require.Equal(t, r.temporal, pkt.Temporal, "packet %d", i)
+	}
+
+	// packets with dependency descriptor are left untouched
+	pkt := &buffer.ExtPacket{
+		VideoLayer:           buffer.VideoLayer{Spatial: 2, Temporal: 1},
+		Packet:               &rtp.Packet{Header: rtp.Header{Timestamp: 19000}},
+		Payload:              codecs.VP9Packet{B: true},
+		DependencyDescriptor: &buffer.ExtDependencyDescriptor{},
+	}
+	v.Update(pkt)
+	require.Equal(t, buffer.VideoLayer{Spatial: 2, Temporal: 1}, pkt.VideoLayer)
+}
@@ -54,6 +54,7 @@ var (
 	promFirTotal        *prometheus.CounterVec
 	promPacketLossTotal *prometheus.CounterVec
 	promPacketLoss      *prometheus.HistogramVec
+	promFrameTotal      *prometheus.CounterVec
 	promJitter          *prometheus.HistogramVec
 	promRTT             *prometheus.HistogramVec
 	promParticipantJoin *prometheus.CounterVec
@@ -61,6 +62,11 @@ var (
 	promForwardLatency  prometheus.Gauge
 	promForwardJitter   prometheus.Gauge
 
+	promForwardLatencyPercentile *prometheus.GaugeVec
+
+	promForwardBytesPerSecond   prometheus.Gauge
+	promForwardPacketsPerSecond prometheus.Gauge
+
 	promPacketTotalIncomingInitial    prometheus.Counter
 	promPacketTotalIncomingRetransmit prometheus.Counter
 	promPacketTotalOutgoingInitial    prometheus.Counter
@@ -115,6 +121,12 @@ func initPacketStats(nodeID string, nodeType livekit.NodeType) {
 		ConstLabels: prometheus.Labels{"node_id": nodeID, "node_type": nodeType.String()},
 		Buckets:     []float64{0.0, 0.1, 0.3, 0.5, 0.7, 1, 5, 10, 40, 100},
 	}, promStreamLabels)
+	promFrameTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace:   livekitNamespace,
+		Subsystem:   "frame",
+		Name:        "total",
+		ConstLabels: prometheus.Labels{"node_id": nodeID, "node_type": nodeType.String()},
+	}, promStreamLabels)
 	promJitter = prometheus.NewHistogramVec(prometheus.HistogramOpts{
 		Namespace:   livekitNamespace,
 		Subsystem:   "jitter",
@@ -155,6 +167,24 @@ func initPacketStats(nodeID string, nodeType livekit.NodeType) {
 		Name:        "jitter",
 		ConstLabels: prometheus.Labels{"node_id": nodeID, "node_type": nodeType.String()},
 	})
+	promForwardLatencyPercentile = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace:   livekitNamespace,
+		Subsystem:   "forward",
+		Name:        "latency_percentile",
+		ConstLabels: prometheus.Labels{"node_id": nodeID, "node_type": nodeType.String()},
+	}, []string{"quantile"})
+	promForwardBytesPerSecond = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace:   livekitNamespace,
+		Subsystem:   "forward",
+		Name:        "bytes_per_second",
+		ConstLabels: prometheus.Labels{"node_id": nodeID, "node_type": nodeType.String()},
+	})
+	promForwardPacketsPerSecond = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace:   livekitNamespace,
+		Subsystem:   "forward",
+		Name:        "packets_per_second",
+		ConstLabels: prometheus.Labels{"node_id": nodeID, "node_type": nodeType.String()},
+	})
 
 	prometheus.MustRegister(promPacketTotal)
 	prometheus.MustRegister(promPacketBytes)
@@ -163,12 +193,16 @@ func initPacketStats(nodeID string, nodeType livekit.NodeType) {
 	prometheus.MustRegister(promFirTotal)
 	prometheus.MustRegister(promPacketLossTotal)
 	prometheus.MustRegister(promPacketLoss)
+	prometheus.MustRegister(promFrameTotal)
 	prometheus.MustRegister(promJitter)
 	prometheus.MustRegister(promRTT)
 	prometheus.MustRegister(promParticipantJoin)
 	prometheus.MustRegister(promConnections)
 	prometheus.MustRegister(promForwardLatency)
 	prometheus.MustRegister(promForwardJitter)
+	prometheus.MustRegister(promForwardLatencyPercentile)
+	prometheus.MustRegister(promForwardBytesPerSecond)
+	prometheus.MustRegister(promForwardPacketsPerSecond)
 
 	promPacketTotalIncomingInitial = promPacketTotal.WithLabelValues(string(Incoming), transmissionInitial)
 	promPacketTotalIncomingRetransmit = promPacketTotal.WithLabelValues(string(Incoming), transmissionRetransmit)
@@ -252,6 +286,12 @@ func RecordPacketLoss(direction Direction, trackSource livekit.TrackSource, trac
 	}
 }
 
+func RecordFrames(direction Direction, trackSource livekit.TrackSource, trackType livekit.TrackType, frames uint32) {
+	if frames > 0 {
+		promFrameTotal.WithLabelValues(string(direction), trackSource.String(), trackType.String()).Add(float64(frames))
+	}
+}
+
 func RecordJitter(direction Direction, trackSource livekit.TrackSource, trackType livekit.TrackType, jitter uint32) {
 	if jitter > 0 {
 		promJitter.WithLabelValues(string(direction), trackSource.String(), trackType.String()).Observe(float64(jitter))
@@ -304,6 +344,17 @@ func RecordForwardLatency(_, latencyAvg uint32) {
 	promForwardLatency.Set(float64(latencyAvg))
 }
 
+func RecordForwardLatencyPercentiles(p50, p95, p99 uint32) {
+	promForwardLatencyPercentile.WithLabelValues("0.5").Set(float64(p50))
+	promForwardLatencyPercentile.WithLabelValues("0.95").Set(float64(p95))
+	promForwardLatencyPercentile.WithLabelValues("0.99").Set(float64(p99))
+}
+
+func RecordForwardThroughput(bytesPerSec float64, packetsPerSec float64) {
+	promForwardBytesPerSecond.Set(bytesPerSec)
+	promForwardPacketsPerSecond.Set(packetsPerSec)
+}
+
 func RecordForwardJitter(_, jitterAvg uint32) {
 	forwardJitter.Store(jitterAvg)
 	promForwardJitter.Set(float64(jitterAvg))
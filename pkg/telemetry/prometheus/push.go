@@ -0,0 +1,97 @@
+// Copyright 2024 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prometheus
+
+import (
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/push"
+
+	"github.com/livekit/protocol/logger"
+)
+
+const (
+	pushJobName             = "livekit"
+	defaultPushInterval     = 15 * time.Second
+	pushGroupingLabelNodeID = "node_id"
+)
+
+// Pusher periodically pushes a snapshot of all registered metrics to a Prometheus pushgateway,
+// for deployments where the Prometheus server cannot scrape the node.
+type Pusher struct {
+	pusher   *push.Pusher
+	interval time.Duration
+
+	stopOnce sync.Once
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+func NewPusher(url string, interval time.Duration, nodeID string) *Pusher {
+	return newPusher(url, interval, nodeID, prometheus.DefaultGatherer)
+}
+
+func newPusher(url string, interval time.Duration, nodeID string, gatherer prometheus.Gatherer) *Pusher {
+	if interval <= 0 {
+		interval = defaultPushInterval
+	}
+
+	return &Pusher{
+		pusher: push.New(url, pushJobName).
+			Gatherer(gatherer).
+			Grouping(pushGroupingLabelNodeID, nodeID),
+		interval: interval,
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+}
+
+func (p *Pusher) Start() {
+	go p.worker()
+}
+
+// Stop stops pushing and deletes the metrics of this node from the pushgateway.
+func (p *Pusher) Stop() {
+	p.stopOnce.Do(func() {
+		close(p.stop)
+		<-p.done
+
+		if err := p.pusher.Delete(); err != nil {
+			logger.Warnw("could not delete metrics from pushgateway", err)
+		}
+	})
+}
+
+func (p *Pusher) worker() {
+	defer close(p.done)
+
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-p.stop:
+			return
+
+		case <-ticker.C:
+			// replaces all metrics of this node on every push, a snapshot of the full state
+			if err := p.pusher.Push(); err != nil {
+				logger.Warnw("could not push metrics", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,69 @@
+// Copyright 2024 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prometheus
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPusher(t *testing.T) {
+	var lock sync.Mutex
+	var requests []string
+	var pushed [][]byte
+	gateway := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+
+		lock.Lock()
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		if r.Method == http.MethodPut {
+			pushed = append(pushed, body)
+		}
+		lock.Unlock()
+
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer gateway.Close()
+
+	registry := prometheus.NewRegistry()
+	counter := prometheus.NewCounter(prometheus.CounterOpts{Namespace: livekitNamespace, Name: "test_total"})
+	registry.MustRegister(counter)
+	counter.Add(3)
+
+	p := newPusher(gateway.URL, 10*time.Millisecond, "node", registry)
+	p.Start()
+
+	require.Eventually(t, func() bool {
+		lock.Lock()
+		defer lock.Unlock()
+		return len(pushed) >= 2
+	}, time.Second, 5*time.Millisecond)
+
+	p.Stop()
+	p.Stop()
+
+	lock.Lock()
+	defer lock.Unlock()
+	require.Equal(t, "PUT /metrics/job/livekit/node_id/node", requests[0])
+	require.Equal(t, "DELETE /metrics/job/livekit/node_id/node", requests[len(requests)-1])
+	require.NotEmpty(t, pushed[0])
+}
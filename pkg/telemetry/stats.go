@@ -79,6 +79,7 @@ func (t *telemetryService) TrackStats(key StatsKey, stat *livekit.AnalyticsStat)
 				prometheus.RecordPacketLoss(direction, key.trackSource, key.trackType, stream.PacketsLost, stream.PrimaryPackets+stream.PaddingPackets)
 				prometheus.RecordRTT(direction, key.trackSource, key.trackType, stream.Rtt)
 				prometheus.RecordJitter(direction, key.trackSource, key.trackType, stream.Jitter)
+				prometheus.RecordFrames(direction, key.trackSource, key.trackType, stream.Frames)
 			}
 		}
 		prometheus.IncrementRTCP(direction, nacks, plis, firs)
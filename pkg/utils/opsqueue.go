@@ -17,6 +17,7 @@ package utils
 import (
 	"math/bits"
 	"sync"
+	"time"
 
 	"github.com/gammazero/deque"
 
@@ -27,7 +28,27 @@ type OpsQueueParams struct {
 	Name        string
 	MinSize     uint
 	FlushOnStop bool
-	Logger      logger.Logger
+	// Number of workers processing ops, defaults to 1.
+	// Ops are processed in the order they are enqueued only when there is a single worker,
+	// with multiple workers, ops should be independent of each other.
+	Workers int
+	// Maximum number of pending ops, ops enqueued beyond that are dropped, 0 means unbounded.
+	MaxSize int
+	// Called in a separate goroutine when an op is dropped because the queue is full,
+	// once per overflow event, i. e. not called again till an op is enqueued successfully.
+	OnOverflow func()
+	// Measure processing time of ops from start, for processing latency in stats.
+	// Otherwise, it is measured only after the first deadline based enqueue or stats query.
+	TrackProcessingTime bool
+	Logger              logger.Logger
+}
+
+type OpsQueueStats struct {
+	OpsProcessed           uint64
+	OpsDropped             uint64
+	CurrentDepth           int
+	AvgProcessingLatencyMs float64
+	MaxProcessingLatencyMs float64
 }
 
 type UntypedQueueOp func()
@@ -44,6 +65,18 @@ func NewOpsQueue(params OpsQueueParams) *OpsQueue {
 	return &OpsQueue{*newOpsQueueBase[UntypedQueueOp](params)}
 }
 
+// EnqueueBatch enqueues all ops under a single lock acquisition, so that ops enqueued
+// from other goroutines are not interleaved with them. With a single worker, the ops
+// run consecutively, except for high priority ops which are still processed first.
+// Returns false if the ops were dropped, a batch which does not fit in a bounded queue is dropped as a whole.
+func (oq *OpsQueue) EnqueueBatch(ops []func()) bool {
+	batch := make([]UntypedQueueOp, 0, len(ops))
+	for _, op := range ops {
+		batch = append(batch, op)
+	}
+	return oq.opsQueueBase.enqueueBatch(batch)
+}
+
 type typedQueueOp[T any] struct {
 	fn  func(T)
 	arg T
@@ -61,31 +94,72 @@ func NewTypedOpsQueue[T any](params OpsQueueParams) *TypedOpsQueue[T] {
 	return &TypedOpsQueue[T]{*newOpsQueueBase[typedQueueOp[T]](params)}
 }
 
-func (oq *TypedOpsQueue[T]) Enqueue(fn func(T), arg T) {
-	oq.opsQueueBase.Enqueue(typedQueueOp[T]{fn, arg})
+func (oq *TypedOpsQueue[T]) Enqueue(fn func(T), arg T) bool {
+	return oq.opsQueueBase.Enqueue(typedQueueOp[T]{fn, arg})
+}
+
+func (oq *TypedOpsQueue[T]) EnqueueHigh(fn func(T), arg T) bool {
+	return oq.opsQueueBase.EnqueueHigh(typedQueueOp[T]{fn, arg})
+}
+
+func (oq *TypedOpsQueue[T]) EnqueueWithTimeout(fn func(T), arg T, maxDepth int) bool {
+	return oq.opsQueueBase.EnqueueWithTimeout(typedQueueOp[T]{fn, arg}, maxDepth)
+}
+
+func (oq *TypedOpsQueue[T]) EnqueueWithDeadline(fn func(T), arg T, deadline time.Time) bool {
+	return oq.opsQueueBase.EnqueueWithDeadline(typedQueueOp[T]{fn, arg}, deadline)
 }
 
 type opsQueueItem interface {
 	run()
 }
 
+const (
+	opProcessingTimeEWMAFactor = 0.1
+
+	// to prevent starvation, a normal priority op is processed after these many high priority ops
+	maxHighOpsBeforeNormal = 8
+)
+
 type opsQueueBase[T opsQueueItem] struct {
 	params OpsQueueParams
 
 	lock      sync.Mutex
 	ops       deque.Deque[T]
+	highOps   deque.Deque[T]
 	wake      chan struct{}
 	isStarted bool
 	doneChan  chan struct{}
 	isStopped bool
+	isDrain   bool
+
+	numWorkersRunning int
+	numOpsRunning     int
+
+	// processing time of ops is measured only when needed for deadline based enqueues or stats
+	isProcessingTimeTracked bool
+	avgOpProcessingTime     time.Duration
+	maxOpProcessingTime     time.Duration
+
+	numOpsProcessed uint64
+	numOpsDropped   uint64
+
+	numHighOpsSinceNormal int
+
+	isOverflowing bool
 }
 
 func newOpsQueueBase[T opsQueueItem](params OpsQueueParams) *opsQueueBase[T] {
+	if params.Workers < 1 {
+		params.Workers = 1
+	}
 	return &opsQueueBase[T]{
-		params:   params,
-		ops:      *deque.New[T](min(bits.Len64(uint64(params.MinSize-1)), 7)),
-		wake:     make(chan struct{}, 1),
-		doneChan: make(chan struct{}),
+		params:                  params,
+		ops:                     *deque.New[T](min(bits.Len64(uint64(params.MinSize-1)), 7)),
+		highOps:                 *deque.New[T](),
+		wake:                    make(chan struct{}, params.Workers),
+		doneChan:                make(chan struct{}),
+		isProcessingTimeTracked: params.TrackProcessingTime,
 	}
 }
 
@@ -97,9 +171,12 @@ func (oq *opsQueueBase[T]) Start() {
 	}
 
 	oq.isStarted = true
+	oq.numWorkersRunning = oq.params.Workers
 	oq.lock.Unlock()
 
-	go oq.process()
+	for i := 0; i < oq.params.Workers; i++ {
+		go oq.process()
+	}
 }
 
 func (oq *opsQueueBase[T]) Stop() <-chan struct{} {
@@ -115,43 +192,266 @@ func (oq *opsQueueBase[T]) Stop() <-chan struct{} {
 	return oq.doneChan
 }
 
-func (oq *opsQueueBase[T]) Enqueue(op T) {
+// Drain stops accepting new ops like Stop, but processes all pending ops
+// irrespective of FlushOnStop. Returned channel is closed once all pending ops are processed.
+func (oq *opsQueueBase[T]) Drain() <-chan struct{} {
+	oq.lock.Lock()
+	oq.isDrain = true
+	if oq.isStopped {
+		oq.lock.Unlock()
+		return oq.doneChan
+	}
+
+	oq.isStopped = true
+	close(oq.wake)
+	oq.lock.Unlock()
+	return oq.doneChan
+}
+
+// Enqueue enqueues the op, returns false if the op was dropped because the queue is stopped or full.
+func (oq *opsQueueBase[T]) Enqueue(op T) bool {
+	oq.lock.Lock()
+	defer oq.lock.Unlock()
+
+	return oq.enqueueLocked(op, false)
+}
+
+// EnqueueHigh enqueues the op in the high priority lane,
+// high priority ops are processed before normal ops.
+// Returns false if the op was dropped because the queue is stopped or full.
+func (oq *opsQueueBase[T]) EnqueueHigh(op T) bool {
+	oq.lock.Lock()
+	defer oq.lock.Unlock()
+
+	return oq.enqueueLocked(op, true)
+}
+
+// EnqueueWithTimeout enqueues the op only if there are less than maxDepth ops pending.
+// Returns false if the op was dropped.
+func (oq *opsQueueBase[T]) EnqueueWithTimeout(op T, maxDepth int) bool {
 	oq.lock.Lock()
 	defer oq.lock.Unlock()
 
+	if oq.lenLocked() >= maxDepth {
+		oq.numOpsDropped++
+		return false
+	}
+
+	return oq.enqueueLocked(op, false)
+}
+
+// EnqueueWithDeadline enqueues the op only if it is expected to be processed before the deadline.
+// Expected processing time is estimated from the average processing time of ops,
+// which is measured only after the first use of this method.
+// Returns false if the op was dropped.
+func (oq *opsQueueBase[T]) EnqueueWithDeadline(op T, deadline time.Time) bool {
+	oq.lock.Lock()
+	defer oq.lock.Unlock()
+
+	oq.isProcessingTimeTracked = true
+
+	numAhead := oq.lenLocked() + oq.numOpsRunning
+	expectedWait := time.Duration(numAhead+1) * oq.avgOpProcessingTime / time.Duration(oq.params.Workers)
+	if time.Now().Add(expectedWait).After(deadline) {
+		oq.numOpsDropped++
+		return false
+	}
+
+	return oq.enqueueLocked(op, false)
+}
+
+func (oq *opsQueueBase[T]) enqueueLocked(op T, isHigh bool) bool {
 	if oq.isStopped {
-		return
+		oq.numOpsDropped++
+		return false
 	}
 
-	oq.ops.PushBack(op)
-	if oq.ops.Len() == 1 {
+	if !oq.hasRoomLocked(1) {
+		oq.overflowLocked(1)
+		return false
+	}
+	oq.isOverflowing = false
+
+	if isHigh {
+		oq.highOps.PushBack(op)
+	} else {
+		oq.ops.PushBack(op)
+	}
+	if oq.lenLocked() == 1 || oq.params.Workers > 1 {
 		select {
 		case oq.wake <- struct{}{}:
 		default:
 		}
 	}
+	return true
 }
 
-func (oq *opsQueueBase[T]) process() {
-	defer close(oq.doneChan)
+func (oq *opsQueueBase[T]) enqueueBatch(ops []T) bool {
+	oq.lock.Lock()
+	defer oq.lock.Unlock()
 
+	if len(ops) == 0 {
+		return true
+	}
+
+	if oq.isStopped {
+		oq.numOpsDropped += uint64(len(ops))
+		return false
+	}
+
+	if !oq.hasRoomLocked(len(ops)) {
+		oq.overflowLocked(len(ops))
+		return false
+	}
+	oq.isOverflowing = false
+
+	wasEmpty := oq.lenLocked() == 0
+	for _, op := range ops {
+		oq.ops.PushBack(op)
+	}
+
+	numWakes := 0
+	switch {
+	case oq.params.Workers > 1:
+		numWakes = min(len(ops), oq.params.Workers)
+	case wasEmpty:
+		numWakes = 1
+	}
+	for i := 0; i < numWakes; i++ {
+		select {
+		case oq.wake <- struct{}{}:
+		default:
+		}
+	}
+	return true
+}
+
+func (oq *opsQueueBase[T]) hasRoomLocked(numOps int) bool {
+	return oq.params.MaxSize <= 0 || oq.lenLocked()+numOps <= oq.params.MaxSize
+}
+
+func (oq *opsQueueBase[T]) overflowLocked(numOps int) {
+	oq.numOpsDropped += uint64(numOps)
+	if oq.isOverflowing {
+		return
+	}
+
+	oq.isOverflowing = true
+	if oq.params.OnOverflow != nil {
+		go oq.params.OnOverflow()
+	}
+}
+
+func (oq *opsQueueBase[T]) lenLocked() int {
+	return oq.ops.Len() + oq.highOps.Len()
+}
+
+func (oq *opsQueueBase[T]) popLocked() T {
+	if oq.highOps.Len() != 0 && (oq.ops.Len() == 0 || oq.numHighOpsSinceNormal < maxHighOpsBeforeNormal) {
+		oq.numHighOpsSinceNormal++
+		return oq.highOps.PopFront()
+	}
+
+	oq.numHighOpsSinceNormal = 0
+	return oq.ops.PopFront()
+}
+
+// Len returns the number of ops pending processing.
+func (oq *opsQueueBase[T]) Len() int {
+	oq.lock.Lock()
+	defer oq.lock.Unlock()
+
+	return oq.lenLocked()
+}
+
+// Cap returns the currently allocated capacity of the queue.
+func (oq *opsQueueBase[T]) Cap() int {
+	oq.lock.Lock()
+	defer oq.lock.Unlock()
+
+	return oq.ops.Cap() + oq.highOps.Cap()
+}
+
+// GetStats returns processing stats of the queue.
+// Processing latency is measured only after the first call unless TrackProcessingTime is set.
+func (oq *opsQueueBase[T]) GetStats() OpsQueueStats {
+	oq.lock.Lock()
+	defer oq.lock.Unlock()
+
+	oq.isProcessingTimeTracked = true
+	return OpsQueueStats{
+		OpsProcessed:           oq.numOpsProcessed,
+		OpsDropped:             oq.numOpsDropped,
+		CurrentDepth:           oq.lenLocked(),
+		AvgProcessingLatencyMs: float64(oq.avgOpProcessingTime) / float64(time.Millisecond),
+		MaxProcessingLatencyMs: float64(oq.maxOpProcessingTime) / float64(time.Millisecond),
+	}
+}
+
+func (oq *opsQueueBase[T]) ResetStats() {
+	oq.lock.Lock()
+	defer oq.lock.Unlock()
+
+	oq.numOpsProcessed = 0
+	oq.numOpsDropped = 0
+	oq.avgOpProcessingTime = 0
+	oq.maxOpProcessingTime = 0
+}
+
+func (oq *opsQueueBase[T]) process() {
+	var opStartedAt time.Time
+	isOpRunning := false
 	for {
 		<-oq.wake
 		for {
 			oq.lock.Lock()
-			if oq.isStopped && (!oq.params.FlushOnStop || oq.ops.Len() == 0) {
+			if isOpRunning {
+				isOpRunning = false
+				oq.numOpsRunning--
+				oq.numOpsProcessed++
+				if !opStartedAt.IsZero() {
+					oq.updateOpProcessingTime(time.Since(opStartedAt))
+				}
+			}
+
+			if oq.isStopped && ((!oq.params.FlushOnStop && !oq.isDrain) || oq.lenLocked() == 0) {
+				oq.numWorkersRunning--
+				if oq.numWorkersRunning == 0 {
+					close(oq.doneChan)
+				}
 				oq.lock.Unlock()
 				return
 			}
 
-			if oq.ops.Len() == 0 {
+			if oq.lenLocked() == 0 {
 				oq.lock.Unlock()
 				break
 			}
-			op := oq.ops.PopFront()
+			op := oq.popLocked()
+			oq.numOpsRunning++
+			isOpRunning = true
+			isProcessingTimeTracked := oq.isProcessingTimeTracked
 			oq.lock.Unlock()
 
+			if isProcessingTimeTracked {
+				opStartedAt = time.Now()
+			} else {
+				opStartedAt = time.Time{}
+			}
 			op.run()
 		}
 	}
 }
+
+func (oq *opsQueueBase[T]) updateOpProcessingTime(processingTime time.Duration) {
+	if processingTime > oq.maxOpProcessingTime {
+		oq.maxOpProcessingTime = processingTime
+	}
+
+	if oq.avgOpProcessingTime == 0 {
+		oq.avgOpProcessingTime = processingTime
+		return
+	}
+
+	oq.avgOpProcessingTime += time.Duration(opProcessingTimeEWMAFactor * float64(processingTime-oq.avgOpProcessingTime))
+}
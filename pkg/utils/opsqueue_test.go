@@ -0,0 +1,315 @@
+// Copyright 2024 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/atomic"
+
+	"github.com/livekit/protocol/logger"
+
+	"github.com/livekit/livekit-server/pkg/utils"
+)
+
+func TestOpsQueueLen(t *testing.T) {
+	oq := utils.NewOpsQueue(utils.OpsQueueParams{
+		Name:    "test",
+		MinSize: 16,
+		Logger:  logger.GetLogger(),
+	})
+	require.Equal(t, 0, oq.Len())
+
+	for i := 0; i < 5; i++ {
+		oq.Enqueue(func() {})
+	}
+	require.Equal(t, 5, oq.Len())
+	require.GreaterOrEqual(t, oq.Cap(), 5)
+
+	oq.Start()
+	<-oq.Stop()
+}
+
+func TestOpsQueueEnqueueWithTimeout(t *testing.T) {
+	oq := utils.NewOpsQueue(utils.OpsQueueParams{
+		Name:    "test",
+		MinSize: 16,
+		Logger:  logger.GetLogger(),
+	})
+
+	require.True(t, oq.EnqueueWithTimeout(func() {}, 2))
+	require.True(t, oq.EnqueueWithTimeout(func() {}, 2))
+	require.False(t, oq.EnqueueWithTimeout(func() {}, 2))
+	require.Equal(t, 2, oq.Len())
+
+	oq.Start()
+	<-oq.Stop()
+	require.False(t, oq.EnqueueWithTimeout(func() {}, 10))
+}
+
+func TestOpsQueueEnqueueWithDeadline(t *testing.T) {
+	oq := utils.NewOpsQueue(utils.OpsQueueParams{
+		Name:    "test",
+		MinSize: 16,
+		Logger:  logger.GetLogger(),
+	})
+	oq.Start()
+	defer oq.Stop()
+
+	// no processing history yet, should not be dropped
+	done := make(chan struct{})
+	require.True(t, oq.EnqueueWithDeadline(func() { time.Sleep(50 * time.Millisecond) }, time.Now().Add(time.Second)))
+	require.True(t, oq.EnqueueWithDeadline(func() { close(done) }, time.Now().Add(time.Second)))
+	<-done
+
+	// block the worker so that there is an op in flight
+	started := make(chan struct{})
+	release := make(chan struct{})
+	require.True(t, oq.EnqueueWithDeadline(func() {
+		close(started)
+		<-release
+	}, time.Now().Add(time.Second)))
+	<-started
+
+	// average processing time is at least 45 ms, with the op in flight, expected wait is at least 90 ms
+	require.False(t, oq.EnqueueWithDeadline(func() {}, time.Now().Add(60*time.Millisecond)))
+	require.True(t, oq.EnqueueWithDeadline(func() {}, time.Now().Add(10*time.Second)))
+	close(release)
+}
+
+func TestOpsQueueDrain(t *testing.T) {
+	oq := utils.NewOpsQueue(utils.OpsQueueParams{
+		Name:        "test",
+		MinSize:     16,
+		FlushOnStop: false,
+		Logger:      logger.GetLogger(),
+	})
+
+	numProcessed := 0
+	for i := 0; i < 5; i++ {
+		oq.Enqueue(func() { numProcessed++ })
+	}
+	oq.Start()
+	<-oq.Drain()
+	require.Equal(t, 5, numProcessed)
+
+	oq.Enqueue(func() { numProcessed++ })
+	require.Equal(t, 0, oq.Len())
+}
+
+func TestOpsQueueWorkers(t *testing.T) {
+	oq := utils.NewOpsQueue(utils.OpsQueueParams{
+		Name:        "test",
+		MinSize:     16,
+		FlushOnStop: true,
+		Workers:     4,
+		Logger:      logger.GetLogger(),
+	})
+	oq.Start()
+
+	// all workers have to pick up an op for the ops to complete
+	var wg sync.WaitGroup
+	wg.Add(4)
+	release := make(chan struct{})
+	var numProcessed atomic.Int32
+	for i := 0; i < 4; i++ {
+		oq.Enqueue(func() {
+			wg.Done()
+			<-release
+			numProcessed.Inc()
+		})
+	}
+	wg.Wait()
+	close(release)
+
+	<-oq.Stop()
+	require.Equal(t, int32(4), numProcessed.Load())
+}
+
+func TestOpsQueueStats(t *testing.T) {
+	oq := utils.NewOpsQueue(utils.OpsQueueParams{
+		Name:        "test",
+		MinSize:     16,
+		FlushOnStop: true,
+		Logger:      logger.GetLogger(),
+	})
+	require.Equal(t, utils.OpsQueueStats{}, oq.GetStats())
+
+	for i := 0; i < 3; i++ {
+		oq.Enqueue(func() { time.Sleep(10 * time.Millisecond) })
+	}
+	require.False(t, oq.EnqueueWithTimeout(func() {}, 3))
+	require.Equal(t, 3, oq.GetStats().CurrentDepth)
+
+	oq.Start()
+	<-oq.Stop()
+
+	stats := oq.GetStats()
+	require.Equal(t, uint64(3), stats.OpsProcessed)
+	require.Equal(t, uint64(1), stats.OpsDropped)
+	require.Equal(t, 0, stats.CurrentDepth)
+	require.GreaterOrEqual(t, stats.AvgProcessingLatencyMs, 10.0)
+	require.GreaterOrEqual(t, stats.MaxProcessingLatencyMs, stats.AvgProcessingLatencyMs)
+
+	oq.ResetStats()
+	require.Equal(t, utils.OpsQueueStats{}, oq.GetStats())
+}
+
+func TestOpsQueuePriority(t *testing.T) {
+	oq := utils.NewOpsQueue(utils.OpsQueueParams{
+		Name:        "test",
+		MinSize:     16,
+		FlushOnStop: true,
+		Logger:      logger.GetLogger(),
+	})
+
+	var order []string
+	for i := 0; i < 2; i++ {
+		oq.Enqueue(func() { order = append(order, "normal") })
+	}
+	for i := 0; i < 10; i++ {
+		oq.EnqueueHigh(func() { order = append(order, "high") })
+	}
+	require.Equal(t, 12, oq.Len())
+
+	oq.Start()
+	<-oq.Stop()
+
+	expected := []string{}
+	for i := 0; i < 8; i++ {
+		expected = append(expected, "high")
+	}
+	expected = append(expected, "normal", "high", "high", "normal")
+	require.Equal(t, expected, order)
+}
+
+func TestOpsQueueEnqueueBatch(t *testing.T) {
+	oq := utils.NewOpsQueue(utils.OpsQueueParams{
+		Name:    "test",
+		MinSize: 16,
+		Logger:  logger.GetLogger(),
+	})
+	oq.Start()
+
+	// batch on an empty queue wakes the worker
+	var processed atomic.Int32
+	oq.EnqueueBatch([]func(){
+		func() { processed.Inc() },
+		func() { processed.Inc() },
+	})
+	require.Eventually(t, func() bool { return processed.Load() == 2 }, time.Second, 5*time.Millisecond)
+
+	// batch is not interleaved with ops enqueued concurrently
+	release := make(chan struct{})
+	oq.Enqueue(func() { <-release })
+
+	var order []string
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 50; i++ {
+			oq.Enqueue(func() { order = append(order, "single") })
+		}
+	}()
+	batch := make([]func(), 0, 10)
+	for i := 0; i < 10; i++ {
+		batch = append(batch, func() { order = append(order, "batch") })
+	}
+	oq.EnqueueBatch(batch)
+	wg.Wait()
+	close(release)
+	<-oq.Drain()
+
+	require.Len(t, order, 60)
+	first := -1
+	for i, op := range order {
+		if op == "batch" {
+			first = i
+			break
+		}
+	}
+	require.NotEqual(t, -1, first)
+	for i := first; i < first+10; i++ {
+		require.Equal(t, "batch", order[i])
+	}
+
+	// dropped when stopped
+	oq.EnqueueBatch([]func(){func() {}, func() {}})
+	require.EqualValues(t, 2, oq.GetStats().OpsDropped)
+}
+
+func TestOpsQueueMaxSize(t *testing.T) {
+	var overflows atomic.Int32
+	oq := utils.NewOpsQueue(utils.OpsQueueParams{
+		Name:       "test",
+		MinSize:    16,
+		MaxSize:    3,
+		OnOverflow: func() { overflows.Inc() },
+		Logger:     logger.GetLogger(),
+	})
+
+	require.True(t, oq.Enqueue(func() {}))
+	require.True(t, oq.EnqueueHigh(func() {}))
+	require.False(t, oq.EnqueueBatch([]func(){func() {}, func() {}}))
+	require.True(t, oq.Enqueue(func() {}))
+	require.False(t, oq.Enqueue(func() {}))
+	require.False(t, oq.EnqueueHigh(func() {}))
+	require.Equal(t, 3, oq.Len())
+	require.EqualValues(t, 4, oq.GetStats().OpsDropped)
+
+	// one notification per overflow event, successful enqueue in between ends the first event
+	require.Eventually(t, func() bool { return overflows.Load() == 2 }, time.Second, 5*time.Millisecond)
+
+	oq.Start()
+	require.Eventually(t, func() bool { return oq.Len() == 0 }, time.Second, 5*time.Millisecond)
+
+	require.True(t, oq.EnqueueBatch([]func(){func() {}, func() {}, func() {}}))
+	require.Eventually(t, func() bool { return oq.Len() == 0 }, time.Second, 5*time.Millisecond)
+
+	// running op does not count towards max size
+	release := make(chan struct{})
+	require.True(t, oq.Enqueue(func() { <-release }))
+	require.Eventually(t, func() bool { return oq.Len() == 0 }, time.Second, 5*time.Millisecond)
+	for i := 0; i < 3; i++ {
+		require.True(t, oq.Enqueue(func() {}))
+	}
+	require.False(t, oq.Enqueue(func() {}))
+	require.Eventually(t, func() bool { return overflows.Load() == 3 }, time.Second, 5*time.Millisecond)
+
+	close(release)
+	<-oq.Stop()
+}
+
+func TestOpsQueueStatsTrackProcessingTime(t *testing.T) {
+	oq := utils.NewOpsQueue(utils.OpsQueueParams{
+		Name:                "test",
+		MinSize:             16,
+		FlushOnStop:         true,
+		TrackProcessingTime: true,
+		Logger:              logger.GetLogger(),
+	})
+	oq.Enqueue(func() { time.Sleep(10 * time.Millisecond) })
+	oq.Start()
+	<-oq.Stop()
+
+	// first query includes ops processed before it
+	stats := oq.GetStats()
+	require.Equal(t, uint64(1), stats.OpsProcessed)
+	require.GreaterOrEqual(t, stats.AvgProcessingLatencyMs, 10.0)
+}